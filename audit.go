@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditUser is stored as the author of every change, it defaults to the
+// user running gopaper
+var AuditUser = os.Getenv(`USER`)
+
+// AuditChange is one column of an audited change, Old is empty for a
+// create and New for a delete
+type AuditChange struct {
+	Column string `json:"column"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+}
+
+// recordChange is the AuditFunc of gopaper, it stores an AuditLog with
+// the old and new values of the columns a write changes. The old values
+// are read from the row inside the transaction of the write. Imports
+// write plays with InsertPlays, which audits the plays it updates but not
+// the ones it inserts.
+func recordChange(a Adapter, table, pkey string, id int64, action string, changes map[string]string) error {
+	l := NewAuditLog(a)
+	if table == l._table {
+		return nil
+	}
+	var old map[string]DBValue
+	if action != AuditCreate {
+		q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'", table, pkey, id)
+		results, err := a.Query(q)
+		if err != nil {
+			return err
+		}
+		if len(results) > 0 {
+			old = results[0]
+		}
+	}
+	var columns []string
+	if action == AuditDelete {
+		for c := range old {
+			if c != pkey {
+				columns = append(columns, c)
+			}
+		}
+	} else {
+		for c := range changes {
+			columns = append(columns, c)
+		}
+	}
+	sort.Strings(columns)
+	var list []AuditChange
+	for _, c := range columns {
+		ch := AuditChange{Column: c, New: changes[c]}
+		if v, ok := old[c]; ok {
+			ch.Old, _ = v.AsString()
+		}
+		if action == AuditUpdate && old != nil && ch.Old == ch.New {
+			continue
+		}
+		list = append(list, ch)
+	}
+	if action == AuditUpdate && len(list) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	l.TableName = table
+	l.RowId = id
+	l.Action = action
+	l.Changes = string(b)
+	l.ChangedBy = AuditUser
+	l.ChangedAt = NewDateTime(a)
+	l.ChangedAt.FromTime(time.Now().UTC())
+	return l.Create()
+}
+
+// ChangeList decodes the columns stored in Changes
+func (o *AuditLog) ChangeList() ([]AuditChange, error) {
+	var list []AuditChange
+	if o.Changes == `` {
+		return list, nil
+	}
+	err := json.Unmarshal([]byte(o.Changes), &list)
+	if err != nil {
+		return nil, o._adapter.Oops(fmt.Sprintf(`audit log %d has unreadable changes: %s`, o.Id, err))
+	}
+	return list, nil
+}
+
+// String renders the entry on one line, e.g.
+// 2016-01-05 10:00:00 alice update positions 3: sell 0 -> 120
+func (o *AuditLog) String() string {
+	var parts []string
+	list, err := o.ChangeList()
+	if err != nil {
+		parts = append(parts, o.Changes)
+	}
+	for _, c := range list {
+		parts = append(parts, fmt.Sprintf(`%s %q -> %q`, c.Column, c.Old, c.New))
+	}
+	return fmt.Sprintf(`%s %s %s %s %d: %s`, o.ChangedAt, o.ChangedBy, o.Action, o.TableName, o.RowId, strings.Join(parts, `, `))
+}
+
+// AuditHistory returns the AuditLogs of a row of table, oldest first
+func AuditHistory(a Adapter, table string, id int64) ([]*AuditLog, error) {
+	var logs []*AuditLog
+	m := NewAuditLog(a)
+	q := fmt.Sprintf("SELECT * FROM %s WHERE `table_name` = '%s' AND `row_id` = '%d' ORDER BY `changed_at`, `id`",
+		m._table, a.SafeString(table), id)
+	results, err := a.Query(q)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		l := NewAuditLog(a)
+		err = l.FromDBValueMap(result)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+	return logs, nil
+}
+
+// PositionHistory returns the AuditLogs of a Position, oldest first
+func PositionHistory(a Adapter, positionId int64) ([]*AuditLog, error) {
+	return AuditHistory(a, NewPosition(a)._table, positionId)
+}
+
+// historyCommand implements `gopaper history <position id>`
+func historyCommand(a Adapter, args []string, w io.Writer) error {
+	if len(args) != 1 {
+		return oops(`usage: gopaper history <position id>`)
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return oops(fmt.Sprintf(`%s is not a position id`, args[0]))
+	}
+	logs, err := PositionHistory(a, id)
+	if err != nil {
+		return err
+	}
+	for _, l := range logs {
+		fmt.Fprintln(w, l)
+	}
+	return nil
+}
+
+func init() {
+	SetAuditor(recordChange)
+}
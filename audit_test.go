@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAuditTrail(t *testing.T) {
+	a := newFakeAdapter()
+	a.rows = func(q string) []map[string]string {
+		if strings.HasPrefix(q, `SELECT * FROM positions WHERE`) {
+			return []map[string]string{{`id`: `1`, `portfolio_id`: `7`, `symbol`: `AAA`, `started_at`: `2016-01-04 00:00:00`,
+				`closed_at`: ``, `ptype`: `long`, `buy`: `100`, `sell`: `0`, `stop_loss`: `90`, `quantity`: `10`, `lock_version`: `0`}}
+		}
+		return nil
+	}
+	p := NewPosition(a)
+	p.PortfolioId = 7
+	p.Symbol = `AAA`
+	p.Ptype = `long`
+	p.Buy = 100
+	p.StopLoss = 90
+	p.Quantity = 10
+	p.StartedAt = testDateTime(a, 2016, 1, 4)
+	err := p.Create()
+	if err != nil || a.count(`INSERT INTO audit_log`) != 1 {
+		t.Errorf(`the create should be audited %v %v`, err, a.executed)
+	}
+	if !strings.Contains(a.executed[1], `{"column":"buy","old":"","new":"100"}`) {
+		t.Errorf(`the create should record every column got %s`, a.executed[1])
+	}
+	// the stop loss is set to the value it already has, only the sell
+	// price is a change
+	p.SetSell(120)
+	p.SetStopLoss(90)
+	err = p.Save()
+	if err != nil || a.count(`INSERT INTO audit_log`) != 2 {
+		t.Errorf(`the update should be audited %v %v`, err, a.executed)
+		return
+	}
+	if !strings.Contains(a.executed[2], `'[{"column":"sell","old":"0","new":"120"}]'`) {
+		t.Errorf(`the update should record the old and new sell got %s`, a.executed[2])
+	}
+	_, err = p.UpdateStopLoss(90)
+	if err != nil || a.count(`INSERT INTO audit_log`) != 2 {
+		t.Errorf(`an update which changes nothing should not be audited %v`, a.executed)
+	}
+	err = p.Delete()
+	if err != nil || a.count(`INSERT INTO audit_log`) != 3 || !strings.Contains(a.executed[len(a.executed)-2], `'delete'`) {
+		t.Errorf(`the delete should be audited %v %v`, err, a.executed)
+	}
+}
+
+func TestPositionHistory(t *testing.T) {
+	a := newFakeAdapter()
+	a.rows = func(q string) []map[string]string {
+		if !strings.Contains(q, "`table_name` = 'positions' AND `row_id` = '3'") {
+			return nil
+		}
+		return []map[string]string{{`id`: `1`, `table_name`: `positions`, `row_id`: `3`, `action`: `update`,
+			`changes`: `[{"column":"sell","old":"0","new":"120"}]`, `changed_by`: `alice`, `changed_at`: `2016-01-05 10:00:00`}}
+	}
+	var b strings.Builder
+	err := historyCommand(a, []string{`3`}, &b)
+	if err != nil {
+		t.Errorf(`history failed %s`, err)
+	}
+	if b.String() != "2016-01-05 10:00:00 alice update positions 3: sell \"0\" -> \"120\"\n" {
+		t.Errorf(`unexpected history %q`, b.String())
+	}
+}
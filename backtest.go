@@ -0,0 +1,405 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Backtester replays the bars stored in Play through a strategy. The
+// strategy opens and closes real Positions in a portfolio of their own,
+// so a finished backtest can be inspected like any other portfolio.
+type Backtester struct {
+	Adapter  Adapter
+	Strategy Strategy
+	// Name of the isolated portfolio, a timestamped name is used when empty
+	Name string
+	// StartingCash is in the same units as the Play prices
+	StartingCash int
+	// From and To optionally limit the days which are replayed
+	From time.Time
+	To   time.Time
+	// Symbols limits the market data to these symbols when not empty
+	Symbols []string
+}
+
+// NewBacktester returns a Backtester for the strategy
+func NewBacktester(a Adapter, strategy Strategy, startingCash int) *Backtester {
+	return &Backtester{Adapter: a, Strategy: strategy, StartingCash: startingCash}
+}
+
+// BacktestResult is the report of a finished backtest
+type BacktestResult struct {
+	Portfolio *Portfolio
+	// Trades holds every position the strategy opened, all of them are
+	// closed by the end of the backtest
+	Trades []*Position
+	Curve  []EquityPoint
+	Stats  *PortfolioStats
+}
+
+// Backtest is the state of a running backtest as seen by the strategy
+type Backtest struct {
+	Portfolio *Portfolio
+	adapter   Adapter
+	day       time.Time
+	bars      map[string]*Play
+	history   map[string][]*Play
+	cash      int
+	open      []*Position
+	positions []*Position
+}
+
+// marketBars holds the bars of every symbol, grouped by day
+type marketBars struct {
+	days  []time.Time
+	byDay map[time.Time]map[string]*Play
+}
+
+// loadMarketBars reads every Play together with the symbol of its Position.
+// When several positions share a symbol the first bar seen for a day wins.
+func loadMarketBars(a Adapter, symbols []string) (*marketBars, error) {
+	wanted := make(map[string]bool)
+	for _, s := range symbols {
+		wanted[s] = true
+	}
+	q := fmt.Sprintf("SELECT pl.*, po.`symbol` AS `symbol` FROM %s pl JOIN %s po ON po.`id` = pl.`position_id` WHERE po.`symbol` IS NOT NULL AND po.`symbol` != '' AND pl.`day` IS NOT NULL ORDER BY pl.`day`, pl.`id`",
+		NewPlay(a)._table, NewPosition(a)._table)
+	results, err := a.Query(q)
+	if err != nil {
+		return nil, err
+	}
+	m := &marketBars{byDay: make(map[time.Time]map[string]*Play)}
+	for _, result := range results {
+		symbol, err := result["symbol"].AsString()
+		if err != nil {
+			return nil, err
+		}
+		if len(wanted) > 0 && !wanted[symbol] {
+			continue
+		}
+		p := NewPlay(a)
+		err = p.FromDBValueMap(result)
+		if err != nil {
+			return nil, err
+		}
+		day := truncateDay(p.Day.ToTime())
+		bars, ok := m.byDay[day]
+		if !ok {
+			bars = make(map[string]*Play)
+			m.byDay[day] = bars
+			m.days = append(m.days, day)
+		}
+		if _, ok := bars[symbol]; !ok {
+			bars[symbol] = p
+		}
+	}
+	sort.Slice(m.days, func(i, j int) bool { return m.days[i].Before(m.days[j]) })
+	return m, nil
+}
+
+// Run replays every trading day through the strategy and returns the
+// report. Positions still open after the last day are closed at their
+// last price. A PortfolioSnapshot is stored for every day.
+func (b *Backtester) Run() (*BacktestResult, error) {
+	a := b.Adapter
+	market, err := loadMarketBars(a, b.Symbols)
+	if err != nil {
+		return nil, err
+	}
+	p := NewPortfolio(a)
+	p.Name = b.Name
+	if p.Name == `` {
+		p.Name = fmt.Sprintf(`backtest %s`, time.Now().UTC().Format(`2006-01-02 15:04:05`))
+	}
+	p.Description = `created by the backtester`
+	p.Value = b.StartingCash
+	err = p.Create()
+	if err != nil {
+		return nil, err
+	}
+	bt := &Backtest{
+		Portfolio: p,
+		adapter:   a,
+		history:   make(map[string][]*Play),
+		cash:      b.StartingCash,
+	}
+	result := &BacktestResult{Portfolio: p}
+	err = b.Strategy.OnStart(bt)
+	if err != nil {
+		return nil, err
+	}
+	for _, day := range market.days {
+		if (!b.From.IsZero() && day.Before(truncateDay(b.From))) || (!b.To.IsZero() && day.After(truncateDay(b.To))) {
+			continue
+		}
+		bt.day = day
+		bt.bars = market.byDay[day]
+		for symbol, bar := range bt.bars {
+			bt.history[symbol] = append(bt.history[symbol], bar)
+		}
+		err = bt.applyStops()
+		if err != nil {
+			return nil, err
+		}
+		err = b.Strategy.OnBar(bt, day, bt.bars)
+		if err != nil {
+			return nil, err
+		}
+		point, err := bt.snapshot()
+		if err != nil {
+			return nil, err
+		}
+		result.Curve = append(result.Curve, point)
+	}
+	err = b.Strategy.OnEnd(bt)
+	if err != nil {
+		return nil, err
+	}
+	err = bt.closeAll()
+	if err != nil {
+		return nil, err
+	}
+	result.Trades = bt.positions
+	result.Stats = ComputePortfolioStats(bt.positions, result.Curve)
+	result.Stats.PortfolioId = p.Id
+	result.Stats.Name = p.Name
+	return result, nil
+}
+
+// Day returns the trading day being replayed
+func (bt *Backtest) Day() time.Time {
+	return bt.day
+}
+
+// Cash returns the cash of the backtest portfolio
+func (bt *Backtest) Cash() int {
+	return bt.cash
+}
+
+// History returns the bars of symbol up to and including the current day
+func (bt *Backtest) History(symbol string) []*Play {
+	return bt.history[symbol]
+}
+
+// lastPrice returns the most recent close of symbol
+func (bt *Backtest) lastPrice(symbol string) (int, bool) {
+	h := bt.history[symbol]
+	if len(h) == 0 {
+		return 0, false
+	}
+	return h[len(h)-1].AdjClose, true
+}
+
+// Equity returns the cash plus the value of the open positions at the
+// most recent close
+func (bt *Backtest) Equity() int {
+	equity := bt.cash
+	for _, p := range bt.open {
+		price, ok := bt.lastPrice(p.Symbol)
+		if !ok {
+			price = p.Buy
+		}
+		if isShortPosition(p) {
+			equity -= price * p.Quantity
+		} else {
+			equity += price * p.Quantity
+		}
+	}
+	return equity
+}
+
+// OpenPositions returns the positions which are currently open
+func (bt *Backtest) OpenPositions() []*Position {
+	return bt.open
+}
+
+// OpenPosition returns the open position in symbol, or nil
+func (bt *Backtest) OpenPosition(symbol string) *Position {
+	for _, p := range bt.open {
+		if p.Symbol == symbol {
+			return p
+		}
+	}
+	return nil
+}
+
+// CanBuy returns true when the cash pays for quantity of symbol at the
+// close of the current day, strategies skip the signals they cannot afford
+func (bt *Backtest) CanBuy(symbol string, quantity int) bool {
+	bar, ok := bt.bars[symbol]
+	return ok && quantity > 0 && bar.AdjClose*quantity <= bt.cash
+}
+
+// Buy opens a long position at the close of the current day. stopLoss
+// may be zero for no stop.
+func (bt *Backtest) Buy(symbol string, quantity, stopLoss int) (*Position, error) {
+	return bt.openPosition(symbol, `long`, quantity, stopLoss)
+}
+
+// Short opens a short position at the close of the current day. stopLoss
+// may be zero for no stop.
+func (bt *Backtest) Short(symbol string, quantity, stopLoss int) (*Position, error) {
+	return bt.openPosition(symbol, `short`, quantity, stopLoss)
+}
+
+// openPosition creates the Position through the model
+func (bt *Backtest) openPosition(symbol, ptype string, quantity, stopLoss int) (*Position, error) {
+	bar, ok := bt.bars[symbol]
+	if !ok {
+		return nil, bt.adapter.Oops(fmt.Sprintf(`no bar for %s on %s`, symbol, bt.day.Format(`2006-01-02`)))
+	}
+	if quantity <= 0 {
+		return nil, bt.adapter.Oops(fmt.Sprintf(`cannot open %d of %s`, quantity, symbol))
+	}
+	price := bar.AdjClose
+	if ptype == `long` && price*quantity > bt.cash {
+		return nil, bt.adapter.Oops(fmt.Sprintf(`not enough cash to buy %d of %s at %d`, quantity, symbol, price))
+	}
+	p := NewPosition(bt.adapter)
+	p.PortfolioId = bt.Portfolio.Id
+	p.Symbol = symbol
+	p.Ptype = ptype
+	p.Buy = price
+	p.StopLoss = stopLoss
+	p.Quantity = quantity
+	p.StartedAt = NewDateTime(bt.adapter)
+	p.StartedAt.FromTime(bt.day)
+	err := p.Create()
+	if err != nil {
+		return nil, err
+	}
+	if ptype == `short` {
+		bt.cash += price * quantity
+	} else {
+		bt.cash -= price * quantity
+	}
+	bt.open = append(bt.open, p)
+	bt.positions = append(bt.positions, p)
+	return p, nil
+}
+
+// Note records a Note about a position of the backtest
+func (bt *Backtest) Note(p *Position, text string) error {
+	n := NewNote(bt.adapter)
+	n.PortfolioId = bt.Portfolio.Id
+	n.PositionId = p.Id
+	n.Value = text
+	return n.Create()
+}
+
+// Close closes the position at the close of the current day
+func (bt *Backtest) Close(p *Position) error {
+	price, ok := bt.lastPrice(p.Symbol)
+	if !ok {
+		price = p.Buy
+	}
+	return bt.closeAt(p, price)
+}
+
+// closeAt closes the position at price and saves it
+func (bt *Backtest) closeAt(p *Position, price int) error {
+	idx := -1
+	for i, o := range bt.open {
+		if o == p {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return bt.adapter.Oops(fmt.Sprintf(`position %d is not open`, p.Id))
+	}
+	closedAt := NewDateTime(bt.adapter)
+	closedAt.FromTime(bt.day)
+	p.SetSell(price)
+	p.SetClosedAt(closedAt)
+	err := p.Save()
+	if err != nil {
+		return err
+	}
+	if isShortPosition(p) {
+		bt.cash -= price * p.Quantity
+	} else {
+		bt.cash += price * p.Quantity
+	}
+	bt.open = append(bt.open[:idx], bt.open[idx+1:]...)
+	return nil
+}
+
+// closeAll closes every open position at its last price
+func (bt *Backtest) closeAll() error {
+	for len(bt.open) > 0 {
+		err := bt.Close(bt.open[0])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyStops closes the positions whose stop loss was touched by the
+// current bar, at the stop or at the open when the price gapped past it
+func (bt *Backtest) applyStops() error {
+	for _, p := range append([]*Position(nil), bt.open...) {
+		bar, ok := bt.bars[p.Symbol]
+		if !ok || p.StopLoss <= 0 {
+			continue
+		}
+		price := p.StopLoss
+		if isShortPosition(p) {
+			if bar.High == 0 || bar.High < p.StopLoss {
+				continue
+			}
+			if bar.Open > price {
+				price = bar.Open
+			}
+		} else {
+			if bar.Low == 0 || bar.Low > p.StopLoss {
+				continue
+			}
+			if bar.Open != 0 && bar.Open < price {
+				price = bar.Open
+			}
+		}
+		err := bt.closeAt(p, price)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshot stores the PortfolioSnapshot of the current day and returns
+// the point on the equity curve
+func (bt *Backtest) snapshot() (EquityPoint, error) {
+	equity := bt.Equity()
+	s := NewPortfolioSnapshot(bt.adapter)
+	s.PortfolioId = bt.Portfolio.Id
+	s.Day = NewDateTime(bt.adapter)
+	s.Day.FromTime(bt.day)
+	s.Cash = bt.cash
+	s.MarketValue = equity - bt.cash
+	s.OpenPositions = len(bt.open)
+	err := s.Create()
+	return EquityPoint{Day: bt.day, Equity: float64(equity)}, err
+}
+
+// WriteText writes the stats followed by the list of trades to w
+func (r *BacktestResult) WriteText(w io.Writer) error {
+	err := r.Stats.WriteText(w)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nTrades:\n")
+	for _, p := range r.Trades {
+		_, err = fmt.Fprintf(w, "  %-8s %-5s %6d  %s %10.2f -> %s %10.2f  %12.2f\n",
+			p.Symbol, p.Ptype, p.Quantity,
+			p.StartedAt.ToTime().Format(`2006-01-02`), fromPrice(p.Buy),
+			p.ClosedAt.ToTime().Format(`2006-01-02`), fromPrice(p.Sell),
+			fromPrice(int(PositionPnL(p))))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeAdapter records the statements sent to it and answers queries
+// from rows, so code built on the models can be tested without MySQL
+type fakeAdapter struct {
+	*MysqlAdapter
+	executed []string
+	rows     func(q string) []map[string]string
+	lastId   int64
+	// noMatch makes AffectedRows return 0, as if no row matched
+	noMatch bool
+}
+
+func newFakeAdapter() *fakeAdapter {
+	m := NewMysqlAdapter(``)
+	m.SetLogs(ioutil.Discard)
+	return &fakeAdapter{MysqlAdapter: m}
+}
+
+func (f *fakeAdapter) Query(q string) ([]map[string]DBValue, error) {
+	var results []map[string]DBValue
+	if f.rows == nil {
+		return results, nil
+	}
+	for _, row := range f.rows(q) {
+		result := make(map[string]DBValue)
+		for k, v := range row {
+			dbv := f.NewDBValue()
+			dbv.SetInternalValue(k, v)
+			result[k] = dbv
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// QueryEach answers q from rows like Query, one row at a time
+func (f *fakeAdapter) QueryEach(q string, fn func(Row) error) error {
+	results, err := f.Query(q)
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		err = fn(DBValueMap(result))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeAdapter) Execute(q string) (ExecResult, error) {
+	f.executed = append(f.executed, q)
+	if strings.HasPrefix(q, `INSERT`) {
+		f.lastId++
+	}
+	return ExecResult{LastInsertId: f.lastId, RowsAffected: f.AffectedRows()}, nil
+}
+
+// Transaction runs f directly, the fake has nothing to roll back
+func (f *fakeAdapter) Transaction(fn func(Adapter) error) error {
+	return fn(f)
+}
+
+// Upsert runs the generic upsert against the fake
+func (f *fakeAdapter) Upsert(table, pkey string, keys, columns []string, values [][]string) ([]int64, error) {
+	return upsertRows(f, f.BatchSize, table, pkey, keys, columns, values)
+}
+
+func (f *fakeAdapter) LastInsertedId() int64 {
+	return f.lastId
+}
+
+func (f *fakeAdapter) AffectedRows() int64 {
+	if f.noMatch {
+		return 0
+	}
+	return 1
+}
+
+// count returns the number of executed statements starting with prefix
+func (f *fakeAdapter) count(prefix string) int {
+	var n int
+	for _, q := range f.executed {
+		if strings.HasPrefix(q, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+// testBarRow is a row of the backtester's market data query
+func testBarRow(id int64, symbol string, y, m, d, open, high, low, c int) map[string]string {
+	return map[string]string{
+		`id`:              fmt.Sprintf(`%d`, id),
+		`position_id`:     `1`,
+		`day`:             time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC).Format(`2006-01-02 15:04:05`),
+		`open`:            fmt.Sprintf(`%d`, open),
+		`high`:            fmt.Sprintf(`%d`, high),
+		`low`:             fmt.Sprintf(`%d`, low),
+		`pvolume`:         `0`,
+		`pchange`:         `0`,
+		`pchange_percent`: `0`,
+		`adj_close`:       fmt.Sprintf(`%d`, c),
+		`data_source`:     `test`,
+		`symbol`:          symbol,
+	}
+}
+
+func TestBacktesterRun(t *testing.T) {
+	a := newFakeAdapter()
+	a.rows = func(q string) []map[string]string {
+		if !strings.Contains(q, `JOIN`) {
+			return nil
+		}
+		return []map[string]string{
+			testBarRow(1, `AAA`, 2016, 1, 4, 100, 100, 100, 100),
+			testBarRow(2, `AAA`, 2016, 1, 5, 100, 110, 100, 110),
+			testBarRow(3, `AAA`, 2016, 1, 6, 110, 120, 110, 120),
+			testBarRow(4, `AAA`, 2016, 1, 7, 120, 120, 115, 115),
+		}
+	}
+	var days int
+	strategy := func(bt *Backtest, day time.Time, bars map[string]*Play) error {
+		days++
+		if len(bt.History(`AAA`)) != days {
+			t.Errorf(`history should have %d bars got %d`, days, len(bt.History(`AAA`)))
+		}
+		switch days {
+		case 1:
+			_, err := bt.Buy(`AAA`, 10, 0)
+			return err
+		case 3:
+			return bt.Close(bt.OpenPosition(`AAA`))
+		}
+		return nil
+	}
+	r, err := NewBacktester(a, StrategyFunc(strategy), 10000).Run()
+	if err != nil {
+		t.Errorf(`backtest failed %s`, err)
+		return
+	}
+	if days != 4 {
+		t.Errorf(`expected 4 trading days got %d`, days)
+	}
+	if len(r.Trades) != 1 || r.Trades[0].Buy != 100 || r.Trades[0].Sell != 120 {
+		t.Errorf(`wrong trades %+v`, r.Trades)
+		return
+	}
+	want := []float64{10000, 10100, 10200, 10200}
+	for i, pt := range r.Curve {
+		if pt.Equity != want[i] {
+			t.Errorf(`day %d equity should be %f got %f`, i, want[i], pt.Equity)
+		}
+	}
+	if r.Stats.Trades != 1 || r.Stats.Wins != 1 || r.Stats.EndEquity != 10200 {
+		t.Errorf(`wrong stats %+v`, r.Stats)
+	}
+	if a.count(`INSERT INTO portfolios`) != 1 || a.count(`INSERT INTO positions`) != 1 || a.count(`INSERT INTO portfolio_snapshots`) != 4 {
+		t.Errorf(`expected the models to be saved got %v`, a.executed)
+	}
+	var buf bytes.Buffer
+	err = r.WriteText(&buf)
+	if err != nil || !strings.Contains(buf.String(), `AAA`) {
+		t.Errorf(`trades missing from the report %s`, buf.String())
+	}
+}
+
+func TestBacktesterStopLoss(t *testing.T) {
+	a := newFakeAdapter()
+	a.rows = func(q string) []map[string]string {
+		if !strings.Contains(q, `JOIN`) {
+			return nil
+		}
+		return []map[string]string{
+			testBarRow(1, `AAA`, 2016, 1, 4, 100, 100, 100, 100),
+			testBarRow(2, `BBB`, 2016, 1, 4, 50, 50, 50, 50),
+			// AAA gaps below the stop, BBB rises through the stop
+			testBarRow(3, `AAA`, 2016, 1, 5, 85, 90, 80, 88),
+			testBarRow(4, `BBB`, 2016, 1, 5, 52, 60, 51, 58),
+		}
+	}
+	strategy := func(bt *Backtest, day time.Time, bars map[string]*Play) error {
+		if len(bt.History(`AAA`)) > 1 {
+			return nil
+		}
+		_, err := bt.Buy(`AAA`, 10, 90)
+		if err != nil {
+			return err
+		}
+		_, err = bt.Short(`BBB`, 10, 55)
+		return err
+	}
+	r, err := NewBacktester(a, StrategyFunc(strategy), 1000).Run()
+	if err != nil {
+		t.Errorf(`backtest failed %s`, err)
+		return
+	}
+	if len(r.Trades) != 2 {
+		t.Errorf(`expected 2 trades got %d`, len(r.Trades))
+		return
+	}
+	if r.Trades[0].Sell != 85 {
+		t.Errorf(`the long should be stopped at the open 85 got %d`, r.Trades[0].Sell)
+	}
+	if r.Trades[1].Sell != 55 {
+		t.Errorf(`the short should be stopped at 55 got %d`, r.Trades[1].Sell)
+	}
+	if r.Curve[1].Equity != 1000-150-50 {
+		t.Errorf(`wrong equity after the stops %f`, r.Curve[1].Equity)
+	}
+}
+
+func TestBacktestNotEnoughCash(t *testing.T) {
+	a := newFakeAdapter()
+	a.rows = func(q string) []map[string]string {
+		if !strings.Contains(q, `JOIN`) {
+			return nil
+		}
+		return []map[string]string{testBarRow(1, `AAA`, 2016, 1, 4, 100, 100, 100, 100)}
+	}
+	strategy := func(bt *Backtest, day time.Time, bars map[string]*Play) error {
+		_, err := bt.Buy(`AAA`, 11, 0)
+		return err
+	}
+	_, err := NewBacktester(a, StrategyFunc(strategy), 1000).Run()
+	if err == nil {
+		t.Errorf(`buying more than the cash allows should fail`)
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BarSource is a provider of daily bars for a symbol
+type BarSource interface {
+	// Name identifies the source, it is recorded as the DataSource of each bar
+	Name() string
+	// FetchBars returns the bars for symbol from the day from to the
+	// day to inclusive, ordered by day
+	FetchBars(symbol string, from, to time.Time) ([]Bar, error)
+}
+
+// filterBars keeps the bars between from and to inclusive
+func filterBars(bars []Bar, from, to time.Time) []Bar {
+	from, to = truncateDay(from), truncateDay(to)
+	var kept []Bar
+	for _, b := range bars {
+		if b.Day.Before(from) || b.Day.After(to) {
+			continue
+		}
+		kept = append(kept, b)
+	}
+	return kept
+}
+
+// DirBarSource reads bars from a directory of CSV files, one per
+// symbol, named like AAPL.csv
+type DirBarSource struct {
+	Dir string
+	// Columns overrides the detected CSV columns, see ParseBarsCSV
+	Columns BarColumns
+}
+
+// NewDirBarSource returns a BarSource for the CSV files in dir
+func NewDirBarSource(dir string) *DirBarSource {
+	return &DirBarSource{Dir: dir}
+}
+
+// Name returns dir:<directory>
+func (s *DirBarSource) Name() string {
+	return `dir:` + s.Dir
+}
+
+// FetchBars reads <Dir>/<symbol>.csv, falling back to the lower case
+// file name. Invalid rows are dropped.
+func (s *DirBarSource) FetchBars(symbol string, from, to time.Time) ([]Bar, error) {
+	fname := filepath.Join(s.Dir, symbol+`.csv`)
+	if !fileExists(fname) {
+		fname = filepath.Join(s.Dir, strings.ToLower(symbol)+`.csv`)
+	}
+	f, err := os.Open(fname)
+	if err != nil {
+		return nil, oops(fmt.Sprintf(`no bars for %s in %s`, symbol, s.Dir))
+	}
+	defer f.Close()
+	bars, _, err := ParseBarsCSV(f, s.Columns)
+	if err != nil {
+		return nil, err
+	}
+	return filterBars(bars, from, to), nil
+}
+
+// HTTPBarSource fetches bars as CSV over HTTP. URL is a template where
+// {symbol}, {from} and {to} are replaced, the dates are formatted with
+// DateLayout, e.g.
+//
+//	http://localhost:8080/bars/{symbol}.csv?from={from}&to={to}
+type HTTPBarSource struct {
+	URL        string
+	DateLayout string
+	Columns    BarColumns
+	Client     *http.Client
+}
+
+// NewHTTPBarSource returns a BarSource for the URL template
+func NewHTTPBarSource(tmpl string) *HTTPBarSource {
+	return &HTTPBarSource{
+		URL:        tmpl,
+		DateLayout: `2006-01-02`,
+		Client:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name returns http:<host>
+func (s *HTTPBarSource) Name() string {
+	u, err := url.Parse(s.URL)
+	if err != nil || u.Host == `` {
+		return `http`
+	}
+	return `http:` + u.Host
+}
+
+// FetchBars requests the bars for symbol and parses the CSV response
+func (s *HTTPBarSource) FetchBars(symbol string, from, to time.Time) ([]Bar, error) {
+	u := strings.NewReplacer(
+		`{symbol}`, url.PathEscape(symbol),
+		`{from}`, url.QueryEscape(from.Format(s.DateLayout)),
+		`{to}`, url.QueryEscape(to.Format(s.DateLayout)),
+	).Replace(s.URL)
+	resp, err := s.Client.Get(u)
+	if err != nil {
+		return nil, oops(fmt.Sprintf(`could not fetch bars for %s %s`, symbol, err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, oops(fmt.Sprintf(`could not fetch bars for %s: %s`, symbol, resp.Status))
+	}
+	bars, _, err := ParseBarsCSV(resp.Body, s.Columns)
+	if err != nil {
+		return nil, err
+	}
+	return filterBars(bars, from, to), nil
+}
+
+// openPositionsWithSymbol returns the positions which are still open
+// and have a symbol to fetch prices for
+func openPositionsWithSymbol(a Adapter) ([]*Position, error) {
+	var positions []*Position
+	m := NewPosition(a)
+	q := fmt.Sprintf("SELECT * FROM %s WHERE `closed_at` IS NULL AND `symbol` IS NOT NULL AND `symbol` != '' ORDER BY `id`", m._table)
+	results, err := a.Query(q)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		p := NewPosition(a)
+		err = p.FromDBValueMap(result)
+		if err != nil {
+			return nil, err
+		}
+		positions = append(positions, p)
+	}
+	return positions, nil
+}
+
+// firstMissingDay returns the first weekday from start up to and including
+// end which has no Play, and false when there is no gap
+func firstMissingDay(plays []*Play, start, end time.Time) (time.Time, bool) {
+	have := make(map[time.Time]bool)
+	for _, p := range plays {
+		if !p.Day.IsZero() {
+			have[truncateDay(p.Day.ToTime())] = true
+		}
+	}
+	for d := truncateDay(start); !d.After(end); d = d.AddDate(0, 0, 1) {
+		if !isWeekend(d) && !have[d] {
+			return d, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// UpdatePrices fills the gaps in the Plays of every open position from
+// the BarSource, up to and including the day to. Existing Plays are left
+// alone and each new Play records the source as its DataSource.
+// Inconsistent bars are handled according to policy.
+func UpdatePrices(a Adapter, src BarSource, to time.Time, policy BarPolicy) ([]*ImportReport, error) {
+	var reports []*ImportReport
+	positions, err := openPositionsWithSymbol(a)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range positions {
+		report := &ImportReport{PositionId: p.Id}
+		if p.StartedAt.IsZero() {
+			reports = append(reports, report)
+			continue
+		}
+		plays, err := playsForPosition(a, p.Id)
+		if err != nil {
+			return reports, err
+		}
+		from, missing := firstMissingDay(plays, p.StartedAt.ToTime(), to)
+		if !missing {
+			reports = append(reports, report)
+			continue
+		}
+		bars, err := src.FetchBars(p.Symbol, from, to)
+		if err != nil {
+			return reports, err
+		}
+		report, err = InsertMissingBars(a, p.Id, bars, src.Name(), policy)
+		reports = append(reports, report)
+		if err != nil {
+			return reports, err
+		}
+	}
+	return reports, nil
+}
+
+// updatePricesCommand implements `gopaper update-prices (-dir path | -url template)`
+func updatePricesCommand(a Adapter, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet(`update-prices`, flag.ContinueOnError)
+	fs.SetOutput(w)
+	dir := fs.String(`dir`, ``, `a directory of <SYMBOL>.csv files`)
+	tmpl := fs.String(`url`, ``, `a URL template with {symbol}, {from} and {to} returning CSV`)
+	flagBars := fs.Bool(`flag-inconsistent`, false, `store inconsistent bars and report them instead of rejecting them`)
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	var src BarSource
+	switch {
+	case *dir != `` && *tmpl == ``:
+		src = NewDirBarSource(*dir)
+	case *tmpl != `` && *dir == ``:
+		src = NewHTTPBarSource(*tmpl)
+	default:
+		return oops(`usage: gopaper update-prices (-dir path | -url template)`)
+	}
+	reports, err := UpdatePrices(a, src, truncateDay(time.Now().UTC()), barPolicyFromFlag(*flagBars))
+	for _, r := range reports {
+		r.Print(w)
+	}
+	return err
+}
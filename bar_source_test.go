@@ -0,0 +1,98 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testBarsCSV = `Date,Open,High,Low,Close,Volume
+2016-01-04,10,11,9,10.5,100
+2016-01-05,10.5,12,10,11.5,200
+2016-01-06,11.5,12,11,11,300
+`
+
+func TestDirBarSource(t *testing.T) {
+	dir, err := ioutil.TempDir(``, `gopaper-bars`)
+	if err != nil {
+		t.Errorf(`could not create temp dir %s`, err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	err = filePutContents(filepath.Join(dir, `aapl.csv`), testBarsCSV)
+	if err != nil {
+		t.Errorf(`could not write csv %s`, err)
+		return
+	}
+	src := NewDirBarSource(dir)
+	from := time.Date(2016, 1, 5, 0, 0, 0, 0, time.UTC)
+	bars, err := src.FetchBars(`AAPL`, from, from.AddDate(0, 0, 10))
+	if err != nil {
+		t.Errorf(`failed to fetch bars %s`, err)
+		return
+	}
+	if len(bars) != 2 || toPrice(bars[0].Close) != 1150 {
+		t.Errorf(`wrong bars %+v`, bars)
+	}
+	_, err = src.FetchBars(`MSFT`, from, from)
+	if err == nil {
+		t.Errorf(`expected an error for a missing symbol`)
+	}
+}
+
+func TestHTTPBarSource(t *testing.T) {
+	var got string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.String()
+		if r.URL.Path != `/bars/AAPL.csv` {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(testBarsCSV))
+	}))
+	defer ts.Close()
+	src := NewHTTPBarSource(ts.URL + `/bars/{symbol}.csv?from={from}&to={to}`)
+	from := time.Date(2016, 1, 4, 0, 0, 0, 0, time.UTC)
+	bars, err := src.FetchBars(`AAPL`, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Errorf(`failed to fetch bars %s`, err)
+		return
+	}
+	if got != `/bars/AAPL.csv?from=2016-01-04&to=2016-01-05` {
+		t.Errorf(`wrong request %s`, got)
+	}
+	if len(bars) != 2 || bars[1].Volume != 200 {
+		t.Errorf(`wrong bars %+v`, bars)
+	}
+	_, err = src.FetchBars(`MSFT`, from, from)
+	if err == nil {
+		t.Errorf(`expected an error for a 404`)
+	}
+	if src.Name()[:5] != `http:` {
+		t.Errorf(`wrong name %s`, src.Name())
+	}
+}
+
+func TestFirstMissingDay(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	plays := []*Play{testPlay(a, 1, 2016, 1, 4, 1), testPlay(a, 1, 2016, 1, 5, 1)}
+	start := time.Date(2016, 1, 4, 0, 0, 0, 0, time.UTC)
+	day, missing := firstMissingDay(plays, start, start.AddDate(0, 0, 7))
+	if !missing || day.Day() != 6 {
+		t.Errorf(`expected the 6th to be missing got %s %v`, day, missing)
+	}
+	_, missing = firstMissingDay(plays, start, start.AddDate(0, 0, 1))
+	if missing {
+		t.Errorf(`expected no gap`)
+	}
+	// the 9th and 10th are a weekend
+	plays = append(plays, testPlay(a, 1, 2016, 1, 6, 1), testPlay(a, 1, 2016, 1, 7, 1), testPlay(a, 1, 2016, 1, 8, 1))
+	_, missing = firstMissingDay(plays, start, start.AddDate(0, 0, 6))
+	if missing {
+		t.Errorf(`weekends should not count as gaps`)
+	}
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PriceScale is the number of stored units per unit of price. Prices
+// are kept as integers in Play, so 12.34 is stored as 1234.
+// Play.PchangePercent uses the same scale, so 1.25% is stored as 125.
+const PriceScale = 100
+
+// BarPolicy decides what happens to a bar which fails ValidateBar
+type BarPolicy int
+
+const (
+	// RejectInconsistentBars drops the bar and reports it
+	RejectInconsistentBars BarPolicy = iota
+	// FlagInconsistentBars stores the bar anyway and reports it
+	FlagInconsistentBars
+)
+
+// barPolicyFromFlag maps the -flag-inconsistent command line flag to a BarPolicy
+func barPolicyFromFlag(flagged bool) BarPolicy {
+	if flagged {
+		return FlagInconsistentBars
+	}
+	return RejectInconsistentBars
+}
+
+// Bar is one day of OHLC data for a symbol, as read from a CSV file
+// or a market data provider, before it is stored as a Play.
+type Bar struct {
+	Day      time.Time
+	Open     float64
+	High     float64
+	Low      float64
+	Close    float64
+	AdjClose float64
+	Volume   int64
+}
+
+// toPrice converts a price to the integer units stored in Play
+func toPrice(f float64) int {
+	return int(math.Round(f * PriceScale))
+}
+
+// fromPrice converts an integer price stored in Play back to a float
+func fromPrice(i int) float64 {
+	return float64(i) / PriceScale
+}
+
+// ClosePrice returns the adjusted close when there is one, otherwise
+// the close. Play only has room for one closing price.
+func (b Bar) ClosePrice() float64 {
+	if b.AdjClose != 0 {
+		return b.AdjClose
+	}
+	return b.Close
+}
+
+// ValidateBar checks that the high and low of the bar contain its open
+// and close. Sources without an open, high or low leave them at zero and
+// those checks are skipped. The range is unadjusted like the close, the
+// adjusted close is only used when a source has no close.
+func ValidateBar(b Bar) error {
+	c := b.Close
+	if c == 0 {
+		c = b.AdjClose
+	}
+	if b.High != 0 {
+		if b.High < c || b.High < b.Open {
+			return oops(fmt.Sprintf(`high %.2f is below the open %.2f or close %.2f`, b.High, b.Open, c))
+		}
+	}
+	if b.Low != 0 {
+		if b.Low > c || (b.Open != 0 && b.Low > b.Open) {
+			return oops(fmt.Sprintf(`low %.2f is above the open %.2f or close %.2f`, b.Low, b.Open, c))
+		}
+		if b.High != 0 && b.Low > b.High {
+			return oops(fmt.Sprintf(`low %.2f is above the high %.2f`, b.Low, b.High))
+		}
+	}
+	return nil
+}
+
+// BarIssue describes a bar which failed ValidateBar
+type BarIssue struct {
+	Day time.Time
+	Err string
+}
+
+func (i BarIssue) String() string {
+	return fmt.Sprintf(`%s: %s`, i.Day.Format(`2006-01-02`), i.Err)
+}
+
+// BarColumns maps the fields of a Bar to the header names of a CSV file.
+// Empty entries are optional columns which are not present.
+type BarColumns struct {
+	Date     string
+	Open     string
+	High     string
+	Low      string
+	Close    string
+	AdjClose string
+	Volume   string
+}
+
+// barColumnAliases lists the normalized header names used by Yahoo and
+// Stooq style exports for each field, see normalizeHeader
+var barColumnAliases = map[string][]string{
+	`date`:      {`date`, `day`, `timestamp`},
+	`open`:      {`open`},
+	`high`:      {`high`},
+	`low`:       {`low`},
+	`close`:     {`close`, `price`, `last`},
+	`adj_close`: {`adjclose`, `adjustedclose`},
+	`volume`:    {`volume`, `vol`},
+}
+
+// normalizeHeader lowercases a header and strips the decorations used by
+// the various exporters, so `Adj Close`, `adj_close` and `<ADJCLOSE>` match
+func normalizeHeader(h string) string {
+	h = strings.ToLower(strings.TrimSpace(h))
+	return strings.NewReplacer(`<`, ``, `>`, ``, ` `, ``, `_`, ``, `-`, ``, "\ufeff", ``).Replace(h)
+}
+
+// DetectBarColumns guesses the BarColumns from a CSV header row
+func DetectBarColumns(header []string) BarColumns {
+	found := make(map[string]string)
+	for _, h := range header {
+		n := normalizeHeader(h)
+		for field, aliases := range barColumnAliases {
+			if _, ok := found[field]; ok {
+				continue
+			}
+			for _, alias := range aliases {
+				if n == alias {
+					found[field] = h
+				}
+			}
+		}
+	}
+	return BarColumns{
+		Date:     found[`date`],
+		Open:     found[`open`],
+		High:     found[`high`],
+		Low:      found[`low`],
+		Close:    found[`close`],
+		AdjClose: found[`adj_close`],
+		Volume:   found[`volume`],
+	}
+}
+
+// merge returns c with the non empty entries of o applied on top
+func (c BarColumns) merge(o BarColumns) BarColumns {
+	for _, f := range []struct{ dst, src *string }{
+		{&c.Date, &o.Date},
+		{&c.Open, &o.Open},
+		{&c.High, &o.High},
+		{&c.Low, &o.Low},
+		{&c.Close, &o.Close},
+		{&c.AdjClose, &o.AdjClose},
+		{&c.Volume, &o.Volume},
+	} {
+		if *f.src != `` {
+			*f.dst = *f.src
+		}
+	}
+	return c
+}
+
+// ParseBarColumns reads a column mapping like `date=Day,close=Last` and
+// applies it on top of cols
+func ParseBarColumns(cols BarColumns, spec string) (BarColumns, error) {
+	if strings.TrimSpace(spec) == `` {
+		return cols, nil
+	}
+	for _, pair := range strings.Split(spec, `,`) {
+		kv := strings.SplitN(pair, `=`, 2)
+		if len(kv) != 2 {
+			return cols, oops(fmt.Sprintf(`bad column mapping %s, expected field=Header`, pair))
+		}
+		header := strings.TrimSpace(kv[1])
+		switch normalizeHeader(kv[0]) {
+		case `date`:
+			cols.Date = header
+		case `open`:
+			cols.Open = header
+		case `high`:
+			cols.High = header
+		case `low`:
+			cols.Low = header
+		case `close`:
+			cols.Close = header
+		case `adjclose`:
+			cols.AdjClose = header
+		case `volume`:
+			cols.Volume = header
+		default:
+			return cols, oops(fmt.Sprintf(`unknown bar field %s`, kv[0]))
+		}
+	}
+	return cols, nil
+}
+
+// RowError describes a CSV row which could not be turned into a Bar
+type RowError struct {
+	Line int
+	Err  string
+}
+
+func (e RowError) String() string {
+	return fmt.Sprintf(`line %d: %s`, e.Line, e.Err)
+}
+
+// barDateLayouts are the date formats accepted in CSV files
+var barDateLayouts = []string{`2006-01-02`, `20060102`, `2006-01-02 15:04:05`, `01/02/2006`, `2006/01/02`}
+
+// parseBarDate parses the date column of a CSV row
+func parseBarDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range barDateLayouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return truncateDay(t), nil
+		}
+	}
+	return time.Time{}, oops(fmt.Sprintf(`cannot parse date %q`, s))
+}
+
+// ParseBarsCSV reads OHLC bars from a CSV file with a header row. The
+// columns are detected from the header, any non empty entry of cols
+// overrides what was detected. Rows
+// which cannot be parsed are returned as RowErrors and do not stop the
+// import.
+func ParseBarsCSV(r io.Reader, cols BarColumns) ([]Bar, []RowError, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, oops(fmt.Sprintf(`could not read csv header %s`, err))
+	}
+	cols = DetectBarColumns(header).merge(cols)
+	index := make(map[string]int)
+	for i, h := range header {
+		index[normalizeHeader(h)] = i
+	}
+	lookup := func(name string) int {
+		if name == `` {
+			return -1
+		}
+		if i, ok := index[normalizeHeader(name)]; ok {
+			return i
+		}
+		return -1
+	}
+	dateCol, closeCol, adjCol := lookup(cols.Date), lookup(cols.Close), lookup(cols.AdjClose)
+	if dateCol < 0 {
+		return nil, nil, oops(fmt.Sprintf(`no date column %q in header %v`, cols.Date, header))
+	}
+	if closeCol < 0 && adjCol < 0 {
+		return nil, nil, oops(fmt.Sprintf(`no close column %q in header %v`, cols.Close, header))
+	}
+	openCol, highCol, lowCol, volCol := lookup(cols.Open), lookup(cols.High), lookup(cols.Low), lookup(cols.Volume)
+
+	var bars []Bar
+	var invalid []RowError
+	line := 1
+	for {
+		record, err := cr.Read()
+		line++
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			invalid = append(invalid, RowError{Line: line, Err: err.Error()})
+			continue
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == `` {
+			continue
+		}
+		b, err := barFromRecord(record, dateCol, openCol, highCol, lowCol, closeCol, adjCol, volCol)
+		if err != nil {
+			invalid = append(invalid, RowError{Line: line, Err: err.Error()})
+			continue
+		}
+		bars = append(bars, b)
+	}
+	return bars, invalid, nil
+}
+
+// barFromRecord converts one CSV record, a column index of -1 means the
+// column is absent
+func barFromRecord(record []string, dateCol, openCol, highCol, lowCol, closeCol, adjCol, volCol int) (Bar, error) {
+	var b Bar
+	field := func(i int) (string, bool) {
+		if i < 0 {
+			return ``, false
+		}
+		if i >= len(record) {
+			return ``, true
+		}
+		return strings.TrimSpace(record[i]), true
+	}
+	price := func(i int, name string, dst *float64) error {
+		s, ok := field(i)
+		if !ok {
+			return nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return oops(fmt.Sprintf(`bad %s %q`, name, s))
+		}
+		if f < 0 {
+			return oops(fmt.Sprintf(`negative %s %q`, name, s))
+		}
+		*dst = f
+		return nil
+	}
+	s, _ := field(dateCol)
+	day, err := parseBarDate(s)
+	if err != nil {
+		return b, err
+	}
+	b.Day = day
+	for _, p := range []struct {
+		col  int
+		name string
+		dst  *float64
+	}{
+		{openCol, `open`, &b.Open},
+		{highCol, `high`, &b.High},
+		{lowCol, `low`, &b.Low},
+		{closeCol, `close`, &b.Close},
+		{adjCol, `adj close`, &b.AdjClose},
+	} {
+		err = price(p.col, p.name, p.dst)
+		if err != nil {
+			return b, err
+		}
+	}
+	if b.ClosePrice() == 0 {
+		return b, oops(`missing close`)
+	}
+	if s, ok := field(volCol); ok && s != `` {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v < 0 {
+			return b, oops(fmt.Sprintf(`bad volume %q`, s))
+		}
+		b.Volume = int64(v)
+	}
+	return b, nil
+}
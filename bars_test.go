@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBarsCSVYahoo(t *testing.T) {
+	data := `Date,Open,High,Low,Close,Adj Close,Volume
+2016-01-04,102.610001,105.370003,102.000000,105.349998,100.274513,67649400
+2016-01-05,105.750000,105.849998,102.410004,102.709999,97.761681,55791000
+2016-01-06,null,null,null,null,null,null
+`
+	bars, invalid, err := ParseBarsCSV(strings.NewReader(data), BarColumns{})
+	if err != nil {
+		t.Errorf(`failed to parse %s`, err)
+		return
+	}
+	if len(bars) != 2 || len(invalid) != 1 {
+		t.Errorf(`expected 2 bars and 1 invalid row got %d %d`, len(bars), len(invalid))
+		return
+	}
+	if invalid[0].Line != 4 {
+		t.Errorf(`wrong line for invalid row %+v`, invalid[0])
+	}
+	b := bars[0]
+	if !b.Day.Equal(time.Date(2016, 1, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf(`wrong day %s`, b.Day)
+	}
+	if toPrice(b.Open) != 10261 || toPrice(b.High) != 10537 || toPrice(b.Low) != 10200 {
+		t.Errorf(`wrong prices %+v`, b)
+	}
+	if toPrice(b.ClosePrice()) != 10027 || b.Volume != 67649400 {
+		t.Errorf(`wrong close or volume %+v`, b)
+	}
+}
+
+func TestParseBarsCSVStooq(t *testing.T) {
+	data := `<TICKER>,<PER>,<DATE>,<TIME>,<OPEN>,<HIGH>,<LOW>,<CLOSE>,<VOL>,<OPENINT>
+AAPL.US,D,20160104,000000,102.61,105.37,102,105.35,67649400,0
+AAPL.US,D,2016010x,000000,102.61,105.37,102,105.35,67649400,0
+`
+	bars, invalid, err := ParseBarsCSV(strings.NewReader(data), BarColumns{})
+	if err != nil {
+		t.Errorf(`failed to parse %s`, err)
+		return
+	}
+	if len(bars) != 1 || len(invalid) != 1 {
+		t.Errorf(`expected 1 bar and 1 invalid row got %d %d`, len(bars), len(invalid))
+		return
+	}
+	if toPrice(bars[0].ClosePrice()) != 10535 || bars[0].Day.Day() != 4 {
+		t.Errorf(`wrong bar %+v`, bars[0])
+	}
+}
+
+func TestParseBarsCSVMapping(t *testing.T) {
+	cols, err := ParseBarColumns(BarColumns{}, `date=When,close=Px`)
+	if err != nil {
+		t.Errorf(`failed to parse mapping %s`, err)
+		return
+	}
+	data := "When,Px\n01/05/2016,10.5\n"
+	bars, _, err := ParseBarsCSV(strings.NewReader(data), cols)
+	if err != nil || len(bars) != 1 {
+		t.Errorf(`failed to parse mapped csv %v %+v`, err, bars)
+		return
+	}
+	if toPrice(bars[0].ClosePrice()) != 1050 || bars[0].Day.Month() != time.January {
+		t.Errorf(`wrong bar %+v`, bars[0])
+	}
+	_, err = ParseBarColumns(BarColumns{}, `colour=Red`)
+	if err == nil {
+		t.Errorf(`expected an error for an unknown field`)
+	}
+	_, _, err = ParseBarsCSV(strings.NewReader("A,B\n1,2\n"), BarColumns{})
+	if err == nil {
+		t.Errorf(`expected an error when there is no date column`)
+	}
+}
+
+func TestValidateBar(t *testing.T) {
+	good := Bar{Open: 10, High: 12, Low: 9, Close: 11}
+	if err := ValidateBar(good); err != nil {
+		t.Errorf(`good bar failed validation %s`, err)
+	}
+	for _, b := range []Bar{
+		{Open: 10, High: 10.5, Low: 9, Close: 11},
+		{Open: 13, High: 12, Low: 9, Close: 11},
+		{Open: 10, High: 12, Low: 10.5, Close: 11},
+		{Open: 10, High: 12, Low: 9, Close: 8},
+	} {
+		if ValidateBar(b) == nil {
+			t.Errorf(`inconsistent bar passed validation %+v`, b)
+		}
+	}
+	// a close only bar has nothing to check
+	if err := ValidateBar(Bar{Close: 11}); err != nil {
+		t.Errorf(`close only bar failed validation %s`, err)
+	}
+	// Yahoo adjusts the close for dividends but not the range
+	yahoo := Bar{Open: 102.610001, High: 105.370003, Low: 102.000000, Close: 105.349998, AdjClose: 100.274513, Volume: 67649400}
+	if err := ValidateBar(yahoo); err != nil {
+		t.Errorf(`the adjusted close should not be checked against the range %s`, err)
+	}
+	if ValidateBar(Bar{Open: 10, High: 12, Low: 9, AdjClose: 8}) == nil {
+		t.Errorf(`without a close the adjusted close should be checked`)
+	}
+}
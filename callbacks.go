@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// startPosition is the BeforeCreate callback of Position, positions
+// created without a start are started now
+func startPosition(a Adapter, model interface{}) error {
+	p, ok := model.(*Position)
+	if !ok || !p.StartedAt.IsZero() {
+		return nil
+	}
+	p.StartedAt = NewDateTime(a)
+	p.StartedAt.FromTime(time.Now().UTC())
+	return nil
+}
+
+// auditPositionClose is the AfterSave callback of Position, it records a
+// Note when the position is created closed or a Save or UpdateClosedAt
+// closes an open position
+func auditPositionClose(a Adapter, model interface{}) error {
+	p, ok := model.(*Position)
+	if !ok || p.ClosedAt.IsZero() {
+		return nil
+	}
+	if !p._new && (!p.IsClosedAtDirty || (p._orig != nil && !p._orig.ClosedAt.IsZero())) {
+		return nil
+	}
+	n := NewNote(a)
+	n.PortfolioId = p.PortfolioId
+	n.PositionId = p.Id
+	n.Value = fmt.Sprintf(`closed %s %d %s at %.2f on %s`, p.Ptype, p.Quantity, p.Symbol,
+		fromPrice(p.Sell), p.ClosedAt.ToTime().Format(`2006-01-02`))
+	return n.Create()
+}
+
+func init() {
+	RegisterCallback(`Position`, BeforeCreate, startPosition)
+	RegisterCallback(`Position`, AfterSave, auditPositionClose)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPositionCallbacks(t *testing.T) {
+	a := newFakeAdapter()
+	p := NewPosition(a)
+	p.PortfolioId = 7
+	p.Symbol = `AAA`
+	p.Ptype = `long`
+	p.Buy = 100
+	p.Quantity = 10
+	err := p.Create()
+	if err != nil || p.StartedAt.IsZero() {
+		t.Errorf(`Create should start the position %v`, err)
+	}
+	if a.count(`INSERT INTO notes`) != 0 {
+		t.Errorf(`an open position should not be audited %v`, a.executed)
+	}
+	p.SetSell(120)
+	p.SetClosedAt(testDateTime(a, 2016, 1, 5))
+	err = p.Save()
+	if err != nil || a.count(`UPDATE positions`) != 1 {
+		t.Errorf(`the position should be updated %v %v`, err, a.executed)
+	}
+	var note string
+	for _, q := range a.executed {
+		if strings.HasPrefix(q, `INSERT INTO notes`) {
+			note = q
+		}
+	}
+	if !strings.Contains(note, `closed long 10 AAA at 1.20 on 2016-01-05`) {
+		t.Errorf(`closing should write an audit note got %v`, a.executed)
+	}
+}
+
+func TestCallbackErrorStopsTheWrite(t *testing.T) {
+	a := newFakeAdapter()
+	RegisterCallback(`Setting`, BeforeDelete, func(a Adapter, model interface{}) error {
+		if model.(*Setting).Skey == `locked` {
+			return oops(`locked settings cannot be deleted`)
+		}
+		return nil
+	})
+	defer delete(_callbacks, `Setting`)
+	s := NewSetting(a)
+	s.Id = 1
+	s.Skey = `locked`
+	if s.Delete() == nil || a.count(`DELETE`) != 0 {
+		t.Errorf(`the callback should stop the delete %v`, a.executed)
+	}
+	s.Skey = `other`
+	if s.Delete() != nil || a.count(`DELETE FROM settings`) != 1 {
+		t.Errorf(`the setting should be deleted %v`, a.executed)
+	}
+}
+
+func TestPositionCloseNotes(t *testing.T) {
+	a := newFakeAdapter()
+	p := NewPosition(a)
+	p.PortfolioId = 7
+	p.Symbol = `AAA`
+	p.Ptype = `long`
+	p.Buy = 100
+	p.Sell = 90
+	p.Quantity = 10
+	p.ClosedAt = testDateTime(a, 2016, 1, 4)
+	err := p.Create()
+	if err != nil || a.count(`INSERT INTO notes`) != 1 {
+		t.Errorf(`a position created closed should be noted %v %v`, err, a.executed)
+	}
+	p = NewPosition(a)
+	p.PortfolioId = 7
+	p.Symbol = `BBB`
+	p.Ptype = `long`
+	p.Buy = 100
+	p.Quantity = 10
+	err = p.Create()
+	if err != nil || a.count(`INSERT INTO notes`) != 1 {
+		t.Errorf(`an open position should not be noted %v %v`, err, a.executed)
+	}
+	_, err = p.UpdateClosedAt(testDateTime(a, 2016, 1, 5))
+	var note string
+	for _, q := range a.executed {
+		if strings.HasPrefix(q, `INSERT INTO notes`) {
+			note = q
+		}
+	}
+	if err != nil || a.count(`INSERT INTO notes`) != 2 || !strings.Contains(note, `closed long 10 BBB at 0.00 on 2016-01-05`) {
+		t.Errorf(`UpdateClosedAt should note the close %v %v`, err, a.executed)
+	}
+	if p.IsClosedAtDirty || p.ClosedAt.IsZero() {
+		t.Errorf(`the close should be stored on the model`)
+	}
+	_, err = p.UpdateClosedAt(testDateTime(a, 2016, 1, 6))
+	if err != nil || a.count(`INSERT INTO notes`) != 2 {
+		t.Errorf(`moving the close of a closed position should not be noted again %v %v`, err, a.executed)
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// runCommand dispatches the positional arguments given to gopaper,
+// e.g. `gopaper stats 1`. With no arguments there is nothing to do.
+func runCommand(a Adapter, args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return nil
+	}
+	switch args[0] {
+	case `stats`:
+		return statsCommand(a, args[1:], w)
+	case `snapshot`:
+		return snapshotCommand(a, args[1:], w)
+	case `import`:
+		return importCommand(a, args[1:], w)
+	case `update-prices`:
+		return updatePricesCommand(a, args[1:], w)
+	case `strategy`:
+		return strategyCommand(a, args[1:], w)
+	case `setting`:
+		return settingCommand(a, args[1:], w)
+	case `risk`:
+		return riskCommand(a, args[1:], w)
+	case `history`:
+		return historyCommand(a, args[1:], w)
+	}
+	return oops(fmt.Sprintf(`unknown command %s`, args[0]))
+}
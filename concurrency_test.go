@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestConcurrentCreates creates plays from many goroutines sharing one
+// Adapter, each must get the id of its own INSERT. Run it with -race.
+func TestConcurrentCreates(t *testing.T) {
+	a, d := newCountingAdapter()
+	defer a.Close()
+	plays := make([]*Play, 200)
+	for i := range plays {
+		plays[i] = NewPlay(a)
+		plays[i].PositionId = 1
+		plays[i].DataSource = fmt.Sprintf(`race-%d`, i)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i < len(plays); i += 8 {
+				err := plays[i].Save()
+				if err != nil {
+					t.Errorf(`could not create play %d %v`, i, err)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	seen := make(map[int64]bool)
+	for i, p := range plays {
+		var id int64
+		for q, qid := range d.ids {
+			if strings.HasPrefix(q, `INSERT INTO plays`) && strings.HasSuffix(q, fmt.Sprintf(`'race-%d')`, i)) {
+				id = qid
+			}
+		}
+		if id == 0 || p.Id != id || seen[p.Id] {
+			t.Errorf(`play %d should have the id %d of its INSERT got %d`, i, id, p.Id)
+		}
+		seen[p.Id] = true
+	}
+	// plays and their audit log entries
+	if a.LastInsertedId() == 0 || d.executed != 400 {
+		t.Errorf(`expected 400 statements got %d`, d.executed)
+	}
+}
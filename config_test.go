@@ -0,0 +1,119 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadYAMLProfiles(t *testing.T) {
+	y, err := fileGetContents(`test_data/profiles.yml`)
+	if err != nil {
+		t.Errorf(`failed to load yaml %s`, err)
+		return
+	}
+	a := NewMysqlAdapter(``)
+	err = a.LoadYAML(y, `prod`)
+	if err != nil || a.Host != `db.example.com` || a.Port != 3306 || a.Database != `my_db` || a.User != `root` || a.DBPrefix != `wp_` {
+		t.Errorf(`did not apply the prod profile %+v %v`, a, err)
+	}
+	a = NewMysqlAdapter(``)
+	err = a.LoadYAML(y, `test`)
+	if err != nil || a.Host != `localhost` || a.Database != `my_db_test` || a.Pass != `rootpass` {
+		t.Errorf(`did not apply the test profile %+v %v`, a, err)
+	}
+	err = NewMysqlAdapter(``).LoadYAML(y, ``)
+	if err == nil || !strings.Contains(err.Error(), `select one of the profiles base, dev, prod, test`) {
+		t.Errorf(`a profile must be selected got %v`, err)
+	}
+	err = NewMysqlAdapter(``).LoadYAML(y, `staging`)
+	if err == nil || !strings.Contains(err.Error(), `no profile staging`) {
+		t.Errorf(`an unknown profile should fail got %v`, err)
+	}
+	_, err = NewMysqlAdapterEx(`test_data/profiles.yml#nope`)
+	if err == nil || !strings.HasPrefix(err.Error(), `test_data/profiles.yml:1:1: no profile nope`) {
+		t.Errorf(`NewMysqlAdapterEx should select the profile after # got %v`, err)
+	}
+	err = NewMysqlAdapter(``).LoadYAML([]byte("host: localhost\nuser: root\ndatabase: my_db\n"), `dev`)
+	if err == nil || !strings.Contains(err.Error(), `there are no profiles`) {
+		t.Errorf(`a single configuration has no profiles got %v`, err)
+	}
+}
+
+func TestLoadYAMLErrors(t *testing.T) {
+	y := []byte("host: \"127.0.0..\"\nuser: \"root\"\ndatabse: \"my_db\"\nport: 99999\nretries: -1\n")
+	err := NewMysqlAdapter(``).LoadYAML(y, ``)
+	errs, ok := err.(ConfigErrors)
+	if !ok {
+		t.Errorf(`expected ConfigErrors got %v`, err)
+		return
+	}
+	expected := []string{
+		`3:1: unknown key databse`,
+		`1:7: host "127.0.0.." is not a valid host name or address`,
+		`1:1: database is required`,
+		`4:7: port 99999 is out of range`,
+		`5:10: retries must not be negative`,
+	}
+	if len(errs) != len(expected) {
+		t.Errorf(`expected %d errors got %v`, len(expected), errs)
+		return
+	}
+	for i, e := range expected {
+		if errs[i].Error() != e {
+			t.Errorf(`expected %s got %s`, e, errs[i])
+		}
+	}
+	err = NewMysqlAdapter(``).LoadYAML([]byte("host: localhost\nuser: root\ndatabase: my_db\nport: many\n"), ``)
+	if err == nil || !strings.Contains(err.Error(), `cannot unmarshal`) {
+		t.Errorf(`a port which is not a number should fail got %v`, err)
+	}
+	err = NewMysqlAdapter(``).LoadYAML([]byte(``), ``)
+	if err == nil || err.Error() != `1:1: the configuration is empty` {
+		t.Errorf(`an empty file should fail got %v`, err)
+	}
+	_, err = NewMysqlAdapterEx(`test_data/silly.yml`)
+	if err == nil || err.Error() != `test_data/silly.yml:1:7: host "127.0.0.." is not a valid host name or address` {
+		t.Errorf(`silly.yml should fail to load got %v`, err)
+	}
+	_, err = NewMysqlAdapterEx(`test_data/nonsenseyaml.yml`)
+	if _, ok := err.(*ConfigError); !ok {
+		t.Errorf(`nonsenseyaml.yml should fail to load got %v`, err)
+	}
+}
+
+func TestValidHost(t *testing.T) {
+	for _, h := range []string{`localhost`, `127.0.0.1`, `::1`, `db-1.example.com`, `db.example.com:3307`, `[::1]:3306`} {
+		if !validHost(h) {
+			t.Errorf(`%s should be valid`, h)
+		}
+	}
+	for _, h := range []string{``, `127.0.0..`, `-db.example.com`, `db_1`, `db.example.com:0`, `db.example.com:port`} {
+		if validHost(h) {
+			t.Errorf(`%s should not be valid`, h)
+		}
+	}
+}
+
+func TestLoadYAMLEnv(t *testing.T) {
+	y := []byte("host: localhost\nport: 3306\n")
+	os.Setenv(`TEST_DB_USER`, `root`)
+	os.Setenv(`TEST_DB_DATABASE`, `my_db`)
+	defer os.Unsetenv(`TEST_DB_USER`)
+	defer os.Unsetenv(`TEST_DB_DATABASE`)
+	a := NewMysqlAdapter(``)
+	err := a.loadYAML(`env.yml`, y, ``, `TEST_DB_`)
+	if err != nil || a.User != `root` || a.Database != `my_db` {
+		t.Errorf(`the environment should complete the configuration %+v %v`, a, err)
+	}
+	os.Setenv(`TEST_DB_HOST`, `127.0.0..`)
+	defer os.Unsetenv(`TEST_DB_HOST`)
+	err = NewMysqlAdapter(``).loadYAML(`env.yml`, y, ``, `TEST_DB_`)
+	if err == nil || err.Error() != `TEST_DB_HOST: host "127.0.0.." is not a valid host name or address` {
+		t.Errorf(`a bad variable should be named got %v`, err)
+	}
+	err = NewMysqlAdapter(``).LoadYAML(y, ``)
+	if err == nil || !strings.Contains(err.Error(), `user is required`) {
+		t.Errorf(`LoadYAML should not read the environment got %v`, err)
+	}
+}
@@ -15,16 +15,27 @@ CREATE TABLE IF NOT EXISTS `notes` (
     portfolio_id BIGINT NOT NULL,
     position_id BIGINT NOT NULL
 );
+CREATE TABLE IF NOT EXISTS `portfolio_snapshots` (
+    id BIGINT auto_increment PRIMARY KEY,
+    portfolio_id BIGINT NOT NULL,
+    day DATETIME,
+    cash INT,
+    market_value INT,
+    open_positions INT,
+    UNIQUE KEY `portfolio_day` (portfolio_id, day)
+);
 CREATE TABLE IF NOT EXISTS `positions` (
     id BIGINT auto_increment PRIMARY KEY,
     portfolio_id BIGINT NOT NULL,
+    symbol VARCHAR(255),
     started_at DATETIME,
     closed_at DATETIME,
     ptype VARCHAR(255),
     buy int,
     sell int,
     stop_loss int,
-    quantity int
+    quantity int,
+    lock_version INT NOT NULL DEFAULT 0
 );
 CREATE TABLE IF NOT EXISTS `plays` (
     id BIGINT auto_increment PRIMARY KEY,
@@ -37,5 +48,16 @@ CREATE TABLE IF NOT EXISTS `plays` (
     pchange INT,
     pchange_percent INT,
     adj_close INT,
-    data_source VARCHAR(255)
-);
\ No newline at end of file
+    data_source VARCHAR(255),
+    UNIQUE KEY `position_day` (position_id, day)
+);
+CREATE TABLE IF NOT EXISTS `audit_log` (
+    id BIGINT auto_increment PRIMARY KEY,
+    table_name VARCHAR(255) NOT NULL,
+    row_id BIGINT NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    changes TEXT,
+    changed_by VARCHAR(255),
+    changed_at DATETIME NOT NULL,
+    KEY `table_row` (table_name, row_id)
+);
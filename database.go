@@ -2,9 +2,15 @@ package main
 import (
     "database/sql"
     "fmt"
-    _ "github.com/go-sql-driver/mysql" // This is standard for this library.
+    mysqldriver "github.com/go-sql-driver/mysql" // This is standard for this library.
+    "database/sql/driver"
+    "net"
+    "net/url"
+    "crypto/tls"
+    "crypto/x509"
     "strconv"
-    "gopkg.in/yaml.v2"
+    "gopkg.in/yaml.v3"
+    "reflect"
     "regexp"
     "errors"
     "os"
@@ -12,7 +18,12 @@ import (
     "io/ioutil"
     "bufio"
     "log"
+    "log/slog"
+    "context"
     "strings"
+    "sort"
+    "sync"
+    "time"
 )
 
 
@@ -34,18 +45,24 @@ type Adapter interface {
     Open(string,string,string,string) error
     Close()
     Query(string) ([]map[string]DBValue,error)
-    Execute(string) error
+    Execute(string) (ExecResult,error)
     LastInsertedId() int64
     AffectedRows() int64
     DatabasePrefix() string
     LogInfo(string)
     LogError(error)
     LogDebug(string)
+    Log(slog.Level,string,...slog.Attr)
     SetLogs(io.Writer)
     SetLogFilter(LogFilter)
     Oops(string) error
     SafeString(string)string
     NewDBValue() DBValue
+    Transaction(func(Adapter) error) error
+    Upsert(string,string,[]string,[]string,[][]string) ([]int64,error)
+    QueryEach(string,func(Row) error) error
+    Health() Health
+    Stats() []StatementStats
 }
 
 
@@ -60,21 +77,73 @@ type MysqlAdapter struct {
     Pass string `yaml:"pass"`
     // The database name
     Database string `yaml:"database"`
+    // The TCP port, 3306 when it is not set
+    Port int `yaml:"port"`
+    // The path of a unix socket, used instead of Host and Port when set
+    Socket string `yaml:"socket"`
+    // The TLS mode: true, false, skip-verify or preferred. With TLSCA
+    // set the server certificate is checked against that CA instead.
+    TLS string `yaml:"tls"`
+    // The path of a PEM file with the CA certificate(s) of the server
+    TLSCA string `yaml:"tls_ca"`
+    // The connection character set and collation
+    Charset string `yaml:"charset"`
+    Collation string `yaml:"collation"`
+    // Any other parameter of the driver or system variable, for
+    // instance loc: "UTC". parseTime is refused, the models read
+    // DATETIMEs as the strings MySQL returns.
+    Params map[string]string `yaml:"params"`
     // A prefix, if any - can be blank
     DBPrefix string `yaml:"prefix"`
+    // The number of rows each statement of Upsert writes, DefaultBatchSize
+    // when it is not set
+    BatchSize int `yaml:"batch_size"`
+    // The connection pool, zero leaves the database/sql default
+    MaxOpenConns int `yaml:"max_open_conns"`
+    MaxIdleConns int `yaml:"max_idle_conns"`
+    ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+    // PingOnBorrow pings the database before each statement run outside
+    // a transaction, so a dead connection is noticed and retried
+    PingOnBorrow bool `yaml:"ping_on_borrow"`
+    // Retries is how often a statement failing with a transient error
+    // is retried, waiting RetryBackoff and then twice as long each time
+    Retries int `yaml:"retries"`
+    RetryBackoff time.Duration `yaml:"retry_backoff"`
+    // The least level logged: debug, info, warn or error
+    LogLevel string `yaml:"log_level"`
+    // text for the [INFO]: lines of SetLogs, json for one JSON object
+    // per line
+    LogFormat string `yaml:"log_format"`
+    // Statements taking at least SlowQuery are logged at WARN and to
+    // the slow log, zero turns this off
+    SlowQuery time.Duration `yaml:"slow_query"`
+    // MaskQueries logs the statements with their values replaced by ?,
+    // see MaskSQL, and the values quoted in their errors masked too
+    MaskQueries bool `yaml:"mask_queries"`
     _infoLog *log.Logger
+    _warnLog *log.Logger
     _errorLog *log.Logger
     _debugLog *log.Logger
+    _logger *slog.Logger
+    _level *slog.LevelVar
+    _slowLog *log.Logger
+    _stats *queryStats
     _conn *sql.DB
-    _lid int64
-    _cnt int64
+    _last *lastResult
     _opened bool
+    _tx *sql.Tx
     _logFilter LogFilter
     _safeStringFilter SafeStringFilter
 }
 // NewMysqlAdapter returns a pointer to MysqlAdapter
 func NewMysqlAdapter(pre string) *MysqlAdapter {
-    return &MysqlAdapter{DBPrefix: pre}
+    a := &MysqlAdapter{DBPrefix: pre, Retries: DefaultRetries, RetryBackoff: DefaultRetryBackoff}
+    a.SetLogLevel(slog.LevelDebug)
+    a._stats = newQueryStats()
+    a._last = &lastResult{}
+    // Open may already log its retries
+    a.SetLogs(ioutil.Discard)
+    return a
 } 
 // NewMysqlAdapterEx sets everything up based on your YAML config
 // Args: fname is a string path to a YAML config file
@@ -85,13 +154,42 @@ func NewMysqlAdapter(pre string) *MysqlAdapter {
 //     pass: "dbuserpass"
 //     database: "my_db"
 //     prefix: "wp_"
+//     batch_size: 500
+//     max_open_conns: 10
+//     max_idle_conns: 5
+//     conn_max_lifetime: 5m
+//     ping_on_borrow: true
+//     retries: 2
+//     retry_backoff: 100ms
+//     log_level: info
+//     log_format: json
+//     slow_query: 250ms
+//     mask_queries: true
+//     port: 3306
+//     socket: /var/run/mysqld/mysqld.sock
+//     tls: true
+//     tls_ca: /etc/mysql/ca.pem
+//     charset: utf8mb4
+//     collation: utf8mb4_unicode_ci
+//     params:
+//         loc: UTC
+// The file may instead hold several named profiles, each a mapping
+// like the one above, and fname selects one with file#profile:
+//     dev:
+//         host: "localhost"
+//         ...
+//     prod:
+//         host: "db.example.com"
+//         ...
+// The environment overrides the file, see FromEnv.
 func NewMysqlAdapterEx(fname string) (*MysqlAdapter,error) {
     a := NewMysqlAdapter(``)
+    fname, profile := splitProfile(fname)
     y,err := fileGetContents(fname)
     if err != nil {
         return nil,err
     }
-    err = a.FromYAML(y)
+    err = a.loadYAML(fname,y,profile,EnvPrefix)
     if err != nil {
         return nil,err
     }
@@ -117,6 +215,16 @@ func (a *MysqlAdapter) SafeString(s string) string {
 func (a *MysqlAdapter) SetInfoLog(t io.Writer) {
     a._infoLog = log.New(t,`[INFO]:`,log.Ldate|log.Ltime|log.Lshortfile)
 }
+// SetWarnLog Sets the _warnLog to the io.Writer, use ioutil.Discard if you
+// don't want this one at all.
+func (a *MysqlAdapter) SetWarnLog(t io.Writer) {
+    a._warnLog = log.New(t,`[WARN]:`,log.Ldate|log.Ltime|log.Lshortfile)
+}
+// SetSlowLog Sets the _slowLog to the io.Writer, the statements slower
+// than SlowQuery are written to it. SetLogs leaves it alone.
+func (a *MysqlAdapter) SetSlowLog(t io.Writer) {
+    a._slowLog = log.New(t,`[SLOW]:`,log.Ldate|log.Ltime)
+}
 // SetErrorLog Sets the _errorLog to the io.Writer, use ioutil.Discard if you
 // don't want this one at all.
 func (a *MysqlAdapter) SetErrorLog(t io.Writer) {
@@ -128,43 +236,183 @@ func (a *MysqlAdapter) SetDebugLog(t io.Writer) {
     a._debugLog = log.New(t,`[DEBUG]:`,log.Ldate|log.Ltime|log.Lshortfile)
 }
 // SetLogs Sets ALL logs to the io.Writer, use ioutil.Discard if you
-// don't want this one at all.
+// don't want this one at all. With LogFormat json the records are
+// written as JSON instead of to the tagged logs.
 func (a *MysqlAdapter) SetLogs(t io.Writer) {
     a.SetInfoLog(t)
+    a.SetWarnLog(t)
     a.SetErrorLog(t)
     a.SetDebugLog(t)
+    if a.LogFormat == `json` {
+        a.SetLogHandler(slog.NewJSONHandler(t,&slog.HandlerOptions{Level: slog.LevelDebug}))
+        return
+    }
+    a.SetLogHandler(&tagHandler{a: a})
 }
-// LogInfo Tags the string with INFO and puts it into _infoLog.
-func (a *MysqlAdapter) LogInfo(s string) {
-    if a._logFilter != nil {
-        s = a._logFilter(`INFO`,s)
+// SetLogHandler sends the log records to h, any slog.Handler will do.
+// The level of the Adapter is checked before h sees a record.
+func (a *MysqlAdapter) SetLogHandler(h slog.Handler) {
+    a._logger = slog.New(h)
+}
+// SetLogLevel sets the least level which is logged
+func (a *MysqlAdapter) SetLogLevel(l slog.Level) {
+    if a._level == nil {
+        a._level = new(slog.LevelVar)
     }
-    if s == "" {
+    a._level.Set(l)
+}
+// Logger returns the slog.Logger of the Adapter, records logged with it
+// skip the LogFilter
+func (a *MysqlAdapter) Logger() *slog.Logger {
+    return a._logger
+}
+// Log logs msg with the attributes at level. The LogFilter sees the
+// record as a line of text, if it changes the line the line becomes
+// the message and the attributes are dropped. The message and the
+// string attributes are redacted first, see Redact.
+func (a *MysqlAdapter) Log(level slog.Level, msg string, attrs ...slog.Attr) {
+    if a._logger == nil || (a._level != nil && level < a._level.Level()) {
         return
     }
-    a._infoLog.Println(s)
-}
-// LogError Tags the string with ERROR and puts it into _errorLog.
-func (a *MysqlAdapter) LogError(s error) {
+    msg = a.Redact(msg)
+    attrs = append([]slog.Attr(nil),attrs...)
+    for i, at := range attrs {
+        if at.Value.Kind() == slog.KindString {
+            attrs[i].Value = slog.StringValue(a.Redact(at.Value.String()))
+        }
+    }
     if a._logFilter != nil {
-        ns := a._logFilter(`ERROR`,fmt.Sprintf(`%s`,s))
-        if ns == `` {
+        line := formatRecord(msg,attrs)
+        s := a._logFilter(levelTag(level),line)
+        if s == `` {
             return
         }
-        a._errorLog.Println(ns)
+        if s != line {
+            msg, attrs = s, nil
+        }
+    }
+    if msg == `` && len(attrs) == 0 {
         return
     }
-    a._errorLog.Println(s)
+    a._logger.LogAttrs(context.Background(),level,msg,attrs...)
+}
+// Redact replaces the password of any DSN in s with ***. Only DSNs are
+// redacted, replacing the password wherever it appears would mangle
+// the logs when it is short or a common word.
+func (a *MysqlAdapter) Redact(s string) string {
+    return RedactDSN(s)
+}
+// maskQuery returns q with its values masked when MaskQueries is set
+func (a *MysqlAdapter) maskQuery(q string) string {
+    if a.MaskQueries {
+        return MaskSQL(q)
+    }
+    return q
+}
+// maskError returns the text of err with its quoted values masked when
+// MaskQueries is set, the driver quotes the offending values
+func (a *MysqlAdapter) maskError(err error) string {
+    if a.MaskQueries {
+        return _sqlStringRegexp.ReplaceAllString(err.Error(),`?`)
+    }
+    return err.Error()
+}
+// LogInfo Tags the string with INFO and puts it into _infoLog.
+func (a *MysqlAdapter) LogInfo(s string) {
+    a.Log(slog.LevelInfo,s)
+}
+// LogError Tags the string with ERROR and puts it into _errorLog.
+func (a *MysqlAdapter) LogError(s error) {
+    a.Log(slog.LevelError,fmt.Sprintf(`%s`,s))
 }
 // LogDebug Tags the string with DEBUG and puts it into _debugLog.
 func (a *MysqlAdapter) LogDebug(s string) {
-    if a._logFilter != nil {
-        s = a._logFilter(`DEBUG`,s)
-    }
-    if s == "" {
+    a.Log(slog.LevelDebug,s)
+}
+// levelTag returns the tag of level as the LogFilter sees it
+func levelTag(level slog.Level) string {
+    switch {
+    case level >= slog.LevelError:
+        return `ERROR`
+    case level >= slog.LevelWarn:
+        return `WARN`
+    case level >= slog.LevelInfo:
+        return `INFO`
+    }
+    return `DEBUG`
+}
+// formatRecord renders a record as the message followed by key=value
+// pairs, quoting the values which need it
+func formatRecord(msg string, attrs []slog.Attr) string {
+    var b strings.Builder
+    b.WriteString(msg)
+    for _, at := range attrs {
+        formatAttr(&b,``,at)
+    }
+    return b.String()
+}
+func formatAttr(b *strings.Builder, group string, at slog.Attr) {
+    v := at.Value.Resolve()
+    if v.Kind() == slog.KindGroup {
+        for _, ga := range v.Group() {
+            formatAttr(b,group + at.Key + `.`,ga)
+        }
         return
     }
-    a._debugLog.Println(s)
+    s := v.String()
+    if s == `` || strings.ContainsAny(s," =\"\t\n") {
+        s = strconv.Quote(s)
+    }
+    if b.Len() > 0 {
+        b.WriteString(` `)
+    }
+    b.WriteString(group + at.Key + `=` + s)
+}
+// tagHandler is the slog.Handler of SetLogs, it writes each record as
+// a line to the tagged log of its level
+type tagHandler struct {
+    a *MysqlAdapter
+    attrs []slog.Attr
+    group string
+}
+func (h *tagHandler) Enabled(_ context.Context, level slog.Level) bool {
+    return h.a._level == nil || level >= h.a._level.Level()
+}
+func (h *tagHandler) Handle(_ context.Context, r slog.Record) error {
+    attrs := append([]slog.Attr{},h.attrs...)
+    r.Attrs(func(at slog.Attr) bool {
+        if h.group != `` {
+            at.Key = h.group + at.Key
+        }
+        attrs = append(attrs,at)
+        return true
+    })
+    l := h.a._debugLog
+    switch levelTag(r.Level) {
+    case `ERROR`:
+        l = h.a._errorLog
+    case `WARN`:
+        l = h.a._warnLog
+    case `INFO`:
+        l = h.a._infoLog
+    }
+    return l.Output(1,formatRecord(r.Message,attrs))
+}
+func (h *tagHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+    nh := *h
+    nh.attrs = append([]slog.Attr{},h.attrs...)
+    for _, at := range attrs {
+        if h.group != `` {
+            at.Key = h.group + at.Key
+        }
+        nh.attrs = append(nh.attrs,at)
+    }
+    return &nh
+}
+func (h *tagHandler) WithGroup(name string) slog.Handler {
+    nh := *h
+    nh.group = h.group + name + `.`
+    return &nh
 }
 // NewDBValue Creates a new DBValue, mostly used internally, but
 // you may wish to use it in special circumstances.
@@ -175,29 +423,249 @@ func (a *MysqlAdapter) NewDBValue() DBValue {
 func (a *MysqlAdapter) DatabasePrefix() string {
     return a.DBPrefix
 }
-// FromYAML Set the Adapter's members from a YAML file
+// FromYAML Set the Adapter's members from a YAML file, which must
+// hold a single configuration, see LoadYAML
 func (a *MysqlAdapter) FromYAML(b []byte) error {
-    return yaml.Unmarshal(b,a)
+    return a.LoadYAML(b,``)
+}
+// LoadYAML sets the Adapter's members from the profile of a YAML file,
+// when profile is blank the file must hold a single configuration or a
+// single profile. Unknown keys and invalid values are reported as
+// ConfigErrors with their line and column.
+func (a *MysqlAdapter) LoadYAML(b []byte, profile string) error {
+    return a.loadYAML(``,b,profile,``)
+}
+// loadYAML decodes the profile of the file, applies the environment
+// variables starting with envPrefix, when it is not blank, and only then
+// validates the configuration
+func (a *MysqlAdapter) loadYAML(fname string, b []byte, profile string, envPrefix string) error {
+    var doc yaml.Node
+    err := yaml.Unmarshal(b,&doc)
+    if err != nil {
+        return &ConfigError{File: fname, Msg: err.Error()}
+    }
+    if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+        return &ConfigError{File: fname, Line: 1, Column: 1, Msg: `the configuration is empty`}
+    }
+    n, err := selectProfile(fname,doc.Content[0],profile)
+    if err != nil {
+        return err
+    }
+    var errs ConfigErrors
+    known := yamlKeys(a)
+    keys := map[string]*yaml.Node{}
+    for i := 0; i + 1 < len(n.Content); i += 2 {
+        k := n.Content[i]
+        if k.Value == `<<` {
+            continue
+        }
+        if known[k.Value] == false {
+            errs = append(errs,configError(fname,k,`unknown key %s`,k.Value))
+            continue
+        }
+        keys[k.Value] = n.Content[i+1]
+    }
+    err = n.Decode(a)
+    if err != nil {
+        errs = append(errs,configError(fname,n,`%s`,err))
+        return errs
+    }
+    env := map[string]string{}
+    if envPrefix != `` {
+        env, err = a.fromEnv(envPrefix)
+        if err != nil {
+            return err
+        }
+    }
+    for _, p := range a.problems() {
+        if name, ok := env[p.key]; ok {
+            errs = append(errs,&ConfigError{Msg: name + `: ` + p.msg})
+            continue
+        }
+        at, ok := keys[p.key]
+        if ok == false {
+            at = n
+        }
+        errs = append(errs,configError(fname,at,`%s`,p.msg))
+    }
+    if len(errs) > 0 {
+        return errs
+    }
+    if a.LogLevel != `` {
+        var l slog.Level
+        l.UnmarshalText([]byte(a.LogLevel))
+        a.SetLogLevel(l)
+    }
+    return nil
+}
+// Validate checks that the members form a usable configuration
+func (a *MysqlAdapter) Validate() error {
+    var errs ConfigErrors
+    for _, p := range a.problems() {
+        errs = append(errs,&ConfigError{Msg: p.msg})
+    }
+    if len(errs) > 0 {
+        return errs
+    }
+    return nil
+}
+type configProblem struct {
+    key string
+    msg string
+}
+// problems lists what is wrong with the configuration along with the
+// YAML key which is to blame
+func (a *MysqlAdapter) problems() []configProblem {
+    var ps []configProblem
+    add := func(key, format string, args ...interface{}) {
+        ps = append(ps,configProblem{key,fmt.Sprintf(format,args...)})
+    }
+    if a.Socket == `` {
+        if a.Host == `` {
+            add(`host`,`host is required unless socket is set`)
+        } else if validHost(a.Host) == false {
+            add(`host`,`host %q is not a valid host name or address`,a.Host)
+        }
+    }
+    if a.User == `` {
+        add(`user`,`user is required`)
+    }
+    if a.Database == `` {
+        add(`database`,`database is required`)
+    }
+    if a.Port < 0 || a.Port > 65535 {
+        add(`port`,`port %d is out of range`,a.Port)
+    }
+    switch a.TLS {
+    case ``, `true`, `false`, `skip-verify`, `preferred`:
+    default:
+        add(`tls`,`tls must be true, false, skip-verify or preferred, not %q`,a.TLS)
+    }
+    for _, n := range []struct{ key string; v int }{
+        {`batch_size`,a.BatchSize},{`max_open_conns`,a.MaxOpenConns},{`max_idle_conns`,a.MaxIdleConns},{`retries`,a.Retries},
+    } {
+        if n.v < 0 {
+            add(n.key,`%s must not be negative`,n.key)
+        }
+    }
+    if a.LogLevel != `` {
+        var l slog.Level
+        if l.UnmarshalText([]byte(a.LogLevel)) != nil {
+            add(`log_level`,`log_level must be debug, info, warn or error, not %q`,a.LogLevel)
+        }
+    }
+    switch a.LogFormat {
+    case ``, `text`, `json`:
+    default:
+        add(`log_format`,`log_format must be text or json, not %q`,a.LogFormat)
+    }
+    for _, d := range []struct{ key string; v time.Duration }{
+        {`conn_max_lifetime`,a.ConnMaxLifetime},{`retry_backoff`,a.RetryBackoff},{`slow_query`,a.SlowQuery},
+    } {
+        if d.v < 0 {
+            add(d.key,`%s must not be negative`,d.key)
+        }
+    }
+    return ps
+}
+// splitProfile splits file#profile
+func splitProfile(fname string) (string,string) {
+    i := strings.LastIndex(fname,`#`)
+    if i < 0 {
+        return fname, ``
+    }
+    return fname[:i], fname[i+1:]
+}
+// selectProfile returns the mapping of profile in n. A mapping with any
+// key of the configuration is a single configuration, otherwise each
+// key names a profile.
+func selectProfile(fname string, n *yaml.Node, profile string) (*yaml.Node,error) {
+    if n.Kind != yaml.MappingNode {
+        return nil, configError(fname,n,`expected a mapping of configuration keys`)
+    }
+    known := yamlKeys(&MysqlAdapter{})
+    var names []string
+    profiles := map[string]*yaml.Node{}
+    for i := 0; i + 1 < len(n.Content); i += 2 {
+        k, v := n.Content[i], n.Content[i+1]
+        if known[k.Value] {
+            if profile != `` {
+                return nil, configError(fname,n,`there are no profiles to select %s from`,profile)
+            }
+            return n, nil
+        }
+        if v.Kind == yaml.AliasNode {
+            v = v.Alias
+        }
+        if v.Kind == yaml.MappingNode {
+            names = append(names,k.Value)
+            profiles[k.Value] = v
+        }
+    }
+    if profile == `` && len(names) == 1 {
+        profile = names[0]
+    }
+    p, ok := profiles[profile]
+    if ok == false {
+        sort.Strings(names)
+        if profile == `` {
+            return nil, configError(fname,n,`select one of the profiles %s with file#profile`,strings.Join(names,`, `))
+        }
+        return nil, configError(fname,n,`no profile %s, the profiles are %s`,profile,strings.Join(names,`, `))
+    }
+    return p, nil
+}
+// yamlKeys returns the set of yaml keys of the struct v points to
+func yamlKeys(v interface{}) map[string]bool {
+    keys := map[string]bool{}
+    t := reflect.TypeOf(v).Elem()
+    for i := 0; i < t.NumField(); i++ {
+        tag := strings.Split(t.Field(i).Tag.Get(`yaml`),`,`)[0]
+        if tag != `` && tag != `-` {
+            keys[tag] = true
+        }
+    }
+    return keys
+}
+var _hostLabelRegexp = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`)
+// validHost returns true for an IP address or host name, with an
+// optional :port
+func validHost(h string) bool {
+    if host, port, err := net.SplitHostPort(h); err == nil {
+        p, err := strconv.Atoi(port)
+        if err != nil || p < 1 || p > 65535 {
+            return false
+        }
+        h = host
+    }
+    if net.ParseIP(h) != nil {
+        return true
+    }
+    if len(h) == 0 || len(h) > 253 {
+        return false
+    }
+    for _, l := range strings.Split(h,`.`) {
+        if _hostLabelRegexp.MatchString(l) == false {
+            return false
+        }
+    }
+    return true
 }
 // Open Opens the database connection. Be sure to use 
 // a.Close() as closing is NOT handled for you.
 func (a *MysqlAdapter) Open(h,u,p,d string) error {
-    if ( h != "localhost") {
-        l := fmt.Sprintf("%s:%s@tcp(%s)/%s",u,p,h,d)
-        tc, err := sql.Open("mysql",l)
-        if err != nil {
-            return a.Oops(fmt.Sprintf(`%s with %s`,err,l))
-        }
-        a._conn = tc
-    } else {
-        l := fmt.Sprintf("%s:%s@/%s",u,p,d)
-        tc, err := sql.Open("mysql",l)
-        if err != nil {
-            return a.Oops(fmt.Sprintf(`%s with %s`,err,l))
-        }
-        a._conn = tc
+    a.Host, a.User, a.Pass, a.Database = h, u, p, d
+    l, err := a.DSN()
+    if err != nil {
+        return a.Oops(fmt.Sprintf(`%s with %s`,err,RedactDSN(l)))
     }
-    err := a._conn.Ping()
+    tc, err := sql.Open("mysql",l)
+    if err != nil {
+        return a.Oops(fmt.Sprintf(`%s with %s`,err,RedactDSN(l)))
+    }
+    a._conn = tc
+    a.configurePool()
+    err = a.retry(`Ping`,a._conn.Ping)
     if err != nil {
         return err
     }
@@ -205,6 +673,116 @@ func (a *MysqlAdapter) Open(h,u,p,d string) error {
     return nil
 
 }
+// EnvPrefix starts the names of the environment variables read by
+// NewMysqlAdapterEx, so GOPAPER_DB_PASS overrides pass
+const EnvPrefix = `GOPAPER_DB_`
+// FromEnv overrides the connection settings with those of the
+// environment variables named prefix followed by HOST, PORT, SOCKET,
+// USER, PASS or DATABASE, which keeps secrets out of the YAML file
+func (a *MysqlAdapter) FromEnv(prefix string) error {
+    _, err := a.fromEnv(prefix)
+    return err
+}
+// fromEnv returns the YAML keys it overrode along with the name of
+// their variable
+func (a *MysqlAdapter) fromEnv(prefix string) (map[string]string,error) {
+    set := map[string]string{}
+    for _, s := range []struct{ name string; to *string }{
+        {`HOST`,&a.Host},{`SOCKET`,&a.Socket},{`USER`,&a.User},{`PASS`,&a.Pass},{`DATABASE`,&a.Database},
+    } {
+        if v, ok := os.LookupEnv(prefix + s.name); ok {
+            *s.to = v
+            set[strings.ToLower(s.name)] = prefix + s.name
+        }
+    }
+    if v, ok := os.LookupEnv(prefix + `PORT`); ok {
+        port, err := strconv.Atoi(v)
+        if err != nil {
+            return set, a.Oops(fmt.Sprintf(`%sPORT is not a number: %s`,prefix,v))
+        }
+        a.Port = port
+        set[`port`] = prefix + `PORT`
+    }
+    return set, nil
+}
+// DSN returns the data source name of the connection. A localhost
+// without Port or Socket connects to the default address as it always
+// has. The returned DSN is valid even with an error, for reporting.
+func (a *MysqlAdapter) DSN() (string,error) {
+    cfg := mysqldriver.NewConfig()
+    cfg.User = a.User
+    cfg.Passwd = a.Pass
+    cfg.DBName = a.Database
+    cfg.Collation = a.Collation
+    switch {
+    case a.Socket != ``:
+        cfg.Net = `unix`
+        cfg.Addr = a.Socket
+    case a.Port != 0:
+        cfg.Net = `tcp`
+        cfg.Addr = net.JoinHostPort(a.Host,strconv.Itoa(a.Port))
+    case a.Host != `localhost`:
+        cfg.Net = `tcp`
+        cfg.Addr = a.Host
+    }
+    var params []string
+    if len(a.Params) > 0 {
+        v := url.Values{}
+        for k, p := range a.Params {
+            v.Set(k,p)
+        }
+        params = append(params,v.Encode())
+    }
+    if a.Charset != `` {
+        // the driver does not unescape the charset, which may be a list
+        params = append(params,`charset=` + a.Charset)
+    }
+    tlsName, err := a.tlsConfig()
+    if tlsName != `` {
+        params = append(params,`tls=` + tlsName)
+    }
+    l := cfg.FormatDSN()
+    if len(params) > 0 {
+        if strings.Contains(l,`?`) {
+            l += `&`
+        } else {
+            l += `?`
+        }
+        l += strings.Join(params,`&`)
+    }
+    if err != nil {
+        return l, err
+    }
+    // parsing tells us about unknown or bad parameters now rather
+    // than at the first connection
+    parsed, err := mysqldriver.ParseDSN(l)
+    if err == nil && parsed.ParseTime {
+        err = errors.New(`parseTime is not supported, AsDateTime expects DATETIMEs as strings`)
+    }
+    return l, err
+}
+// tlsConfig returns the value of the tls parameter of the DSN. With
+// TLSCA set it registers a configuration trusting that CA.
+func (a *MysqlAdapter) tlsConfig() (string,error) {
+    if a.TLSCA == `` {
+        return a.TLS, nil
+    }
+    if a.TLS == `false` {
+        return a.TLS, nil
+    }
+    pem, err := ioutil.ReadFile(a.TLSCA)
+    if err != nil {
+        return ``, err
+    }
+    pool := x509.NewCertPool()
+    if pool.AppendCertsFromPEM(pem) == false {
+        return ``, fmt.Errorf(`no certificates in %s`,a.TLSCA)
+    }
+    c := &tls.Config{RootCAs: pool, ServerName: a.Host}
+    name := fmt.Sprintf(`gopaper-%p`,a)
+    err = mysqldriver.RegisterTLSConfig(name,c)
+    return name, err
+}
 // Close This should be called in your application with a defer a.Close() 
 // or something similar. Closing is not automatic!
 func (a *MysqlAdapter) Close() {
@@ -216,13 +794,74 @@ func (a *MysqlAdapter) Query(q string) ([]map[string]DBValue,error) {
     if a._opened != true {
         return nil,a.Oops(`you must first open the connection`)
     }
-    results := new([]map[string]DBValue)
-    a.LogInfo(q)
-    rows, err := a._conn.Query(q)
+    start := time.Now()
+    rows, err := a.queryRetry(`Query`,q)
     if err != nil {
+        a.recordStatement(`query`,q,start,0,err)
         return nil,err
     }
     defer rows.Close()
+    results, err := a.readRows(rows)
+    a.recordStatement(`query`,q,start,int64(len(results)),err)
+    return results,err
+}
+// QueryEach runs q like Query but calls f with each row as it is read
+// instead of collecting them, the buffers of the Row are reused for
+// every row. An error from f stops the iteration and is returned.
+func (a *MysqlAdapter) QueryEach(q string, f func(Row) error) error {
+    if a._opened != true {
+        return a.Oops(`you must first open the connection`)
+    }
+    start := time.Now()
+    rows, err := a.queryRetry(`QueryEach`,q)
+    if err != nil {
+        a.recordStatement(`query`,q,start,0,err)
+        return err
+    }
+    defer rows.Close()
+    var n int64
+    err = a.eachRow(rows,func(r Row) error {
+        n++
+        return f(r)
+    })
+    a.recordStatement(`query`,q,start,n,err)
+    return err
+}
+// queryRetry runs q, retrying transient errors outside of a transaction.
+// In one a deadlock or lock wait timeout has already rolled back the
+// whole transaction, so the error fails the Transaction instead.
+func (a *MysqlAdapter) queryRetry(method, q string) (*sql.Rows,error) {
+    if a._tx != nil {
+        return a.query(q)
+    }
+    var rows *sql.Rows
+    err := a.retry(method,func() (err error) {
+        rows, err = a.query(q)
+        return err
+    })
+    return rows, err
+}
+// query runs q in the transaction of the Adapter, if there is one
+func (a *MysqlAdapter) query(q string) (*sql.Rows,error) {
+    if a._tx != nil {
+        return a._tx.Query(q)
+    }
+    err := a.borrow()
+    if err != nil {
+        return nil,err
+    }
+    return a._conn.Query(q)
+}
+// rowScanner is the part of *sql.Rows that results are read with
+type rowScanner interface {
+    Columns() ([]string,error)
+    Next() bool
+    Scan(...interface{}) error
+    Err() error
+}
+// readRows collects rows as DBValue maps for Query
+func (a *MysqlAdapter) readRows(rows rowScanner) ([]map[string]DBValue,error) {
+    var results []map[string]DBValue
     columns, err := rows.Columns()
     if err != nil {
         return nil, err
@@ -243,46 +882,231 @@ func (a *MysqlAdapter) Query(q string) ([]map[string]DBValue,error) {
             res[k] = a.NewDBValue()
             res[k].SetInternalValue(k,string(col))
         }
-        *results = append(*results,res)
+        results = append(results,res)
+    }
+    return results,rows.Err()
+}
+// mysqlRow is the Row of QueryEach, one is reused for all the rows
+type mysqlRow struct {
+    columns []string
+    index map[string]int
+    raw []sql.RawBytes
+    values []MysqlValue
+}
+// Columns returns the columns in the order of the query
+func (r *mysqlRow) Columns() []string {
+    return r.columns
+}
+// Get returns the value of a column, or nil when there is no such column
+func (r *mysqlRow) Get(column string) DBValue {
+    i, ok := r.index[column]
+    if !ok {
+        return nil
+    }
+    r.values[i]._v = string(r.raw[i])
+    return &r.values[i]
+}
+// eachRow calls f with every row for QueryEach
+func (a *MysqlAdapter) eachRow(rows rowScanner, f func(Row) error) error {
+    columns, err := rows.Columns()
+    if err != nil {
+        return err
+    }
+    r := &mysqlRow{
+        columns: columns,
+        index: make(map[string]int,len(columns)),
+        raw: make([]sql.RawBytes,len(columns)),
+        values: make([]MysqlValue,len(columns)),
+    }
+    scanArgs := make([]interface{},len(columns))
+    for i,c := range columns {
+        r.index[c] = i
+        r.values[i] = MysqlValue{_k: c, _adapter: a}
+        scanArgs[i] = &r.raw[i]
+    }
+    for rows.Next() {
+        err = rows.Scan(scanArgs...)
+        if err != nil {
+            return err
+        }
+        err = f(r)
+        if err != nil {
+            return err
+        }
     }
-    return *results,nil
+    return rows.Err()
 }
 // Oops A function for catching errors generated by
 // the library and funneling them to the log files
 func (a *MysqlAdapter) Oops(s string) error {
-    e := errors.New(s)
+    e := errors.New(a.Redact(s))
     a.LogError(e)
     return e
 }
 // Execute For UPDATE and INSERT calls, i.e. nothing that
-// returns a result set.
-func (a *MysqlAdapter) Execute(q string) error {
+// returns a result set. The ExecResult holds the id of an INSERT and
+// the number of rows changed.
+func (a *MysqlAdapter) Execute(q string) (ExecResult,error) {
     if a._opened != true {
-        return a.Oops(`you must first open the connection`)
+        return ExecResult{},a.Oops(`you must first open the connection`)
+    }
+    if a._tx != nil {
+        r, _, err := a.execute(a._tx,q)
+        return r,err
+    }
+    var res ExecResult
+    err := a.retry(`Execute`,func() error {
+        tx, err := a.begin()
+        if err != nil {
+            return err
+        }
+        defer tx.Rollback();
+        var sent bool
+        res, sent, err = a.execute(tx,q)
+        if err != nil {
+            if sent && isRolledBack(err) == false {
+                // the server may have applied it
+                return final(err)
+            }
+            return err
+        }
+        err = tx.Commit()
+        if err != nil {
+            // the commit may have gone through, running the statement
+            // again could apply it twice
+            return final(a.oopsErr(`could not Commit Transaction`,err))
+        }
+        return nil
+    })
+    return res,err
+}
+// begin starts a transaction, pinging first when PingOnBorrow is set
+func (a *MysqlAdapter) begin() (*sql.Tx,error) {
+    err := a.borrow()
+    if err != nil {
+        return nil,err
     }
     tx, err := a._conn.Begin()
     if err != nil {
-        return a.Oops(fmt.Sprintf(`could not Begin Transaction %s`,err))
+        return nil,a.oopsErr(`could not Begin Transaction`,err)
     }
-    defer tx.Rollback();
+    return tx,nil
+}
+// execute runs q in the transaction tx, sent is true once the statement
+// went to the server
+func (a *MysqlAdapter) execute(tx *sql.Tx, q string) (r ExecResult, sent bool, err error) {
+    start := time.Now()
     stmt, err := tx.Prepare(q)
     if err != nil {
-        return a.Oops(fmt.Sprintf(`could not Prepare Statement %s`,err))
+        a.recordStatement(`execute`,q,start,0,err)
+        return r,false,a.oopsErr(`could not Prepare Statement`,err)
     }
     defer stmt.Close()
-    a.LogInfo(q)
     res,err := stmt.Exec()
     if err != nil {
-        return a.Oops(fmt.Sprintf(`could not Exec stmt %s`,err))
+        a.recordStatement(`execute`,q,start,0,err)
+        return r,true,a.oopsErr(`could not Exec stmt`,err)
+    }
+    r.LastInsertId,err = res.LastInsertId()
+    if err != nil {
+        return r,true,a.Oops(fmt.Sprintf(`could not get LastInsertId %s`,err))
+    }
+    r.RowsAffected,err = res.RowsAffected()
+    if err != nil {
+        return r,true,a.Oops(fmt.Sprintf(`could not get RowsAffected %s`,err))
+    }
+    a._last.set(r)
+    a.recordStatement(`execute`,q,start,r.RowsAffected,nil,slog.Int64(`id`,r.LastInsertId))
+    return r,true,nil
+}
+// lastResult keeps the ExecResult of the latest Execute of any caller
+// for LastInsertedId and AffectedRows, the copies made by Transaction
+// share it
+type lastResult struct {
+    mu sync.Mutex
+    r ExecResult
+}
+func (l *lastResult) set(r ExecResult) {
+    if l == nil {
+        return
+    }
+    l.mu.Lock()
+    l.r = r
+    l.mu.Unlock()
+}
+func (l *lastResult) get() ExecResult {
+    if l == nil {
+        return ExecResult{}
+    }
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    return l.r
+}
+var _statementTableRegexp = regexp.MustCompile("(?i)\\b(?:FROM|INTO|UPDATE)\\s+`?(\\w+)")
+// statementTable returns the first table named by q
+func statementTable(q string) string {
+    m := _statementTableRegexp.FindStringSubmatch(q)
+    if m == nil {
+        return ``
+    }
+    return m[1]
+}
+// recordStatement counts q in the Stats and logs it with its table,
+// duration and the number of rows it read or changed, at ERROR when it
+// failed and at WARN when it was slow
+func (a *MysqlAdapter) recordStatement(msg, q string, start time.Time, rows int64, err error, attrs ...slog.Attr) {
+    d := time.Since(start)
+    slow := a.SlowQuery > 0 && d >= a.SlowQuery
+    a._stats.record(q,d,rows,err != nil,slow)
+    level := slog.LevelInfo
+    switch {
+    case err != nil:
+        level = slog.LevelError
+    case slow:
+        level = slog.LevelWarn
+        msg = `slow ` + msg
+        if a._slowLog != nil {
+            a._slowLog.Printf(`%s rows=%d %s`,d,rows,a.Redact(a.maskQuery(q)))
+        }
+    }
+    if a._logger == nil || (a._level != nil && level < a._level.Level()) {
+        return
+    }
+    attrs = append([]slog.Attr{
+        slog.String(`table`,statementTable(q)),
+        slog.String(`query`,a.maskQuery(q)),
+        slog.Duration(`duration`,d),
+        slog.Int64(`rows`,rows),
+    },attrs...)
+    if err != nil {
+        attrs = append(attrs,slog.String(`error`,a.maskError(err)))
+    }
+    a.Log(level,msg,attrs...)
+}
+// Transaction runs f with a copy of the Adapter bound to a new
+// transaction, which is committed when f returns nil and rolled
+// back otherwise. Inside a transaction f joins the outer one.
+func (a *MysqlAdapter) Transaction(f func(Adapter) error) error {
+    if a._tx != nil {
+        return f(a)
+    }
+    if a._opened != true {
+        return a.Oops(`you must first open the connection`)
     }
-    a._lid,err = res.LastInsertId()
-    a.LogInfo(fmt.Sprintf(`LastInsertedId is %d`,a._lid))
+    var tx *sql.Tx
+    err := a.retry(`Begin`,func() (err error) {
+        tx, err = a.begin()
+        return err
+    })
     if err != nil {
-        return a.Oops(fmt.Sprintf(`could not get LastInsertId %s`,err))
+        return err
     }
-    a._cnt,err = res.RowsAffected()
+    defer tx.Rollback()
+    ta := *a
+    ta._tx = tx
+    err = f(&ta)
     if err != nil {
-        return a.Oops(fmt.Sprintf(`could not get RowsAffected %s`,err))
+        return err
     }
     err = tx.Commit()
     if err != nil {
@@ -290,13 +1114,151 @@ func (a *MysqlAdapter) Execute(q string) error {
     }
     return nil
 }
-// LastInsertedId Grab the last auto_incremented id
+// LastInsertedId Grab the last auto_incremented id of any caller of
+// Execute. Only kept for compatibility, when the Adapter is shared by
+// goroutines it may be the id of another's INSERT, use the ExecResult.
 func (a *MysqlAdapter) LastInsertedId() int64 {
-    return a._lid
+    return a._last.get().LastInsertId
 }
-// AffectedRows Grab the number of AffectedRows
+// AffectedRows Grab the number of AffectedRows of the last statement of
+// any caller of Execute, only kept for compatibility like LastInsertedId
 func (a *MysqlAdapter) AffectedRows() int64 {
-    return a._cnt
+    return a._last.get().RowsAffected
+}
+// Stats returns a snapshot of the statistics of every statement shape
+// run so far, the shapes taking the most time in total first
+func (a *MysqlAdapter) Stats() []StatementStats {
+    return a._stats.snapshot()
+}
+// ResetStats forgets the statistics gathered so far
+func (a *MysqlAdapter) ResetStats() {
+    a._stats.reset()
+}
+// queryStats gathers the StatementStats of an Adapter, the copies made
+// by Transaction share it
+type queryStats struct {
+    mu sync.Mutex
+    shapes map[string]*StatementStats
+}
+func newQueryStats() *queryStats {
+    return &queryStats{shapes: map[string]*StatementStats{}}
+}
+func (qs *queryStats) record(q string, d time.Duration, rows int64, failed, slow bool) {
+    if qs == nil {
+        return
+    }
+    shape := NormalizeSQL(q)
+    qs.mu.Lock()
+    defer qs.mu.Unlock()
+    s, ok := qs.shapes[shape]
+    if ok == false {
+        s = &StatementStats{Shape: shape}
+        qs.shapes[shape] = s
+    }
+    s.Count++
+    s.Rows += rows
+    s.Total += d
+    if d > s.Max {
+        s.Max = d
+    }
+    if failed {
+        s.Errors++
+    }
+    if slow {
+        s.Slow++
+    }
+}
+func (qs *queryStats) snapshot() []StatementStats {
+    if qs == nil {
+        return nil
+    }
+    qs.mu.Lock()
+    defer qs.mu.Unlock()
+    ss := make([]StatementStats,0,len(qs.shapes))
+    for _, s := range qs.shapes {
+        ss = append(ss,*s)
+    }
+    sort.Slice(ss,func(i, j int) bool {
+        if ss[i].Total != ss[j].Total {
+            return ss[i].Total > ss[j].Total
+        }
+        return ss[i].Shape < ss[j].Shape
+    })
+    return ss
+}
+func (qs *queryStats) reset() {
+    if qs == nil {
+        return
+    }
+    qs.mu.Lock()
+    defer qs.mu.Unlock()
+    qs.shapes = map[string]*StatementStats{}
+}
+// Health pings the database and reports how long it took along with
+// the statistics of the connection pool
+func (a *MysqlAdapter) Health() Health {
+    var h Health
+    if a._opened != true {
+        h.Err = a.Oops(`you must first open the connection`)
+        return h
+    }
+    start := time.Now()
+    h.Err = a._conn.Ping()
+    h.Latency = time.Since(start)
+    h.Stats = a._conn.Stats()
+    return h
+}
+// configurePool applies the pool settings to the connection
+func (a *MysqlAdapter) configurePool() {
+    if a.MaxOpenConns > 0 {
+        a._conn.SetMaxOpenConns(a.MaxOpenConns)
+    }
+    if a.MaxIdleConns > 0 {
+        a._conn.SetMaxIdleConns(a.MaxIdleConns)
+    }
+    if a.ConnMaxLifetime > 0 {
+        a._conn.SetConnMaxLifetime(a.ConnMaxLifetime)
+    }
+}
+// borrow pings the database when PingOnBorrow is set
+func (a *MysqlAdapter) borrow() error {
+    if a.PingOnBorrow != true {
+        return nil
+    }
+    return a._conn.Ping()
+}
+// retry runs f until it succeeds, fails with an error which is not
+// transient or has been retried Retries times. It waits RetryBackoff
+// before the first retry and twice as long before each next one. An
+// error f wraps with final is returned as it is, without retrying.
+func (a *MysqlAdapter) retry(what string, f func() error) error {
+    backoff := a.RetryBackoff
+    for attempt := 0; ; attempt++ {
+        err := f()
+        var fe *finalError
+        if errors.As(err,&fe) {
+            return fe.err
+        }
+        if err == nil || attempt >= a.Retries || isTransient(err) == false {
+            return err
+        }
+        a.Log(slog.LevelWarn,`retrying`,slog.String(`what`,what),slog.String(`error`,err.Error()),slog.Duration(`backoff`,backoff))
+        time.Sleep(backoff)
+        backoff *= 2
+    }
+}
+// oopsErr is Oops for errors from the driver, the error returned wraps
+// err so that isTransient can still see what it was
+func (a *MysqlAdapter) oopsErr(s string, err error) error {
+    e := fmt.Errorf(`%s %w`,s,err)
+    a.Log(slog.LevelError,s + ` ` + a.maskError(err))
+    return e
+}
+// Upsert writes values to the columns of table, updating the rows which
+// match a stored row on the unique key keys, BatchSize rows at a time.
+// It returns the values under pkey of the rows, see upsertRows.
+func (a *MysqlAdapter) Upsert(table, pkey string, keys, columns []string, values [][]string) ([]int64,error) {
+    return upsertRows(a,a.BatchSize,table,pkey,keys,columns,values)
 }
 
 
@@ -312,6 +1274,27 @@ type DBValue interface {
     AsDateTime() (*DateTime,error)
     SetInternalValue(string,string)
 }
+// Row is a row of results read by Adapter.QueryEach, the DBValues it
+// returns are only valid until the function given to QueryEach returns
+type Row interface {
+    Columns() []string
+    Get(string) DBValue
+}
+// DBValueMap is a row returned by Adapter.Query, it is also a Row
+type DBValueMap map[string]DBValue
+// Columns returns the columns of the row, sorted by name
+func (m DBValueMap) Columns() []string {
+    var columns []string
+    for c := range m {
+        columns = append(columns,c)
+    }
+    sort.Strings(columns)
+    return columns
+}
+// Get returns the value of a column, or nil when there is no such column
+func (m DBValueMap) Get(column string) DBValue {
+    return m[column]
+}
 // MysqlValue Implements DBValue for MySQL, you'll generally
 // not interact directly with this type, but it
 // is there for special cases.
@@ -366,6 +1349,10 @@ func (v *MysqlValue) AsFloat64() (float64,error) {
 // parsing may fail.
 func (v *MysqlValue) AsDateTime() (*DateTime,error) {
     dt := NewDateTime(v._adapter)
+    if v._v == `` {
+        // NULL columns come back empty, leave the DateTime zeroed
+        return dt,nil
+    }
     err := dt.FromString(v._v)
     if err != nil {
         return &DateTime{}, err
@@ -397,10 +1384,13 @@ type DateTime struct {
     Seconds int
     _adapter Adapter
 }
+// _dateTimeRegexp matches the DATETIME strings MySQL returns, it is
+// compiled once as FromString runs for every DATETIME read
+var _dateTimeRegexp = regexp.MustCompile("(?P<year>[\\d]{4})-(?P<month>[\\d]{2})-(?P<day>[\\d]{2}) (?P<hours>[\\d]{2}):(?P<minutes>[\\d]{2}):(?P<seconds>[\\d]{2})")
 // FromString Converts a string like 0000-00-00 00:00:00 into a DateTime
 func (d *DateTime) FromString(s string) error {
     es := s
-    re := regexp.MustCompile("(?P<year>[\\d]{4})-(?P<month>[\\d]{2})-(?P<day>[\\d]{2}) (?P<hours>[\\d]{2}):(?P<minutes>[\\d]{2}):(?P<seconds>[\\d]{2})")
+    re := _dateTimeRegexp
     n1 := re.SubexpNames()
     ir2 := re.FindAllStringSubmatch(es, -1)
     if len(ir2) == 0 {
@@ -461,42 +1451,449 @@ func (d *DateTime) ToString() string {
 func (d *DateTime) String() string {
     return d.ToString()
 }
-// NewDateTime Returns a basic DateTime value
-func NewDateTime(a Adapter) *DateTime {
-    d := &DateTime{_adapter: a}
-    return d
-}
-func fileExists(p string) bool {
-    if _, err := os.Stat(p); os.IsNotExist(err) {
-        return false
+// IsZero Returns true when the DateTime was never set, i.e. it came
+// from a NULL column or was never filled out.
+func (d *DateTime) IsZero() bool {
+    if d == nil {
+        return true
+    }
+    return d.Year == 0 && d.Month == 0 && d.Day == 0 && d.Hours == 0 && d.Minutes == 0 && d.Seconds == 0
+}
+// ToTime Converts the DateTime into a time.Time in UTC
+func (d *DateTime) ToTime() time.Time {
+    return time.Date(d.Year,time.Month(d.Month),d.Day,d.Hours,d.Minutes,d.Seconds,0,time.UTC)
+}
+// FromTime Sets the DateTime from a time.Time
+func (d *DateTime) FromTime(t time.Time) {
+    d.Year = t.Year()
+    d.Month = int(t.Month())
+    d.Day = t.Day()
+    d.Hours = t.Hour()
+    d.Minutes = t.Minute()
+    d.Seconds = t.Second()
+}
+// dateTimeSQL Quotes a DateTime for use in a query, a nil or
+// zero DateTime is written as NULL
+func dateTimeSQL(d *DateTime) string {
+    if d.IsZero() {
+        return `NULL`
+    }
+    return fmt.Sprintf(`'%s'`,d.ToString())
+}
+// ValidationErrors is returned by Validate, and so by Create and Save,
+// when a model is invalid. It maps field names to what is wrong with them.
+type ValidationErrors map[string][]string
+// Add records a problem with a field
+func (e ValidationErrors) Add(field, msg string) {
+    e[field] = append(e[field],msg)
+}
+// Error lists the problems ordered by field name
+func (e ValidationErrors) Error() string {
+    var fields []string
+    for f := range e {
+        fields = append(fields,f)
+    }
+    sort.Strings(fields)
+    var parts []string
+    for _,f := range fields {
+        parts = append(parts,fmt.Sprintf(`%s %s`,f,strings.Join(e[f],` and `)))
+    }
+    return strings.Join(parts,`, `)
+}
+// ValidatorFunc checks a model before Create or Save write it to the
+// database, returning an error stops the write. Return ValidationErrors
+// to report problems with fields, they are merged with the problems
+// found by the other validators.
+type ValidatorFunc func(a Adapter, model interface{}) error
+var _validators = make(map[string][]ValidatorFunc)
+// RegisterValidator adds a ValidatorFunc for a model, by model name, e.g.
+// Position. Validators run in the order they were registered.
+func RegisterValidator(model string, v ValidatorFunc) {
+    _validators[model] = append(_validators[model],v)
+}
+// runValidators runs the validators registered for a model, adding their
+// ValidationErrors to errs. Any other error is returned at once.
+func runValidators(a Adapter, model string, o interface{}, errs ValidationErrors) error {
+    for _,v := range _validators[model] {
+        err := v(a,o)
+        if ve, ok := err.(ValidationErrors); ok {
+            for f,msgs := range ve {
+                for _,msg := range msgs {
+                    errs.Add(f,msg)
+                }
+            }
+            continue
+        }
+        if err != nil {
+            return err
+        }
     }
-    return true
-}
-func filePutContents(p string, txt string) error {
-    f, err := os.Create(p)
-    if err != nil {
-        return err
+    if len(errs) > 0 {
+        return errs
     }
-    w := bufio.NewWriter(f)
-    _, err = w.WriteString(txt)
-    w.Flush()
     return nil
 }
-func fileGetContents(p string) ([]byte, error) {
-    return ioutil.ReadFile(p)
+// The lifecycle events a CallbackFunc can be registered for. Create runs
+// BeforeSave, BeforeCreate, AfterCreate and AfterSave in that order, an
+// update through Save or Update runs BeforeSave and AfterSave and the
+// single column UpdateXxx runs AfterSave.
+const (
+    BeforeCreate = `before_create`
+    AfterCreate = `after_create`
+    BeforeSave = `before_save`
+    AfterSave = `after_save`
+    BeforeDelete = `before_delete`
+)
+// CallbackFunc is run by the generated Create, Save, Update, UpdateXxx and
+// Delete methods inside the transaction of the write. a is bound to that
+// transaction, returning an error rolls it back.
+type CallbackFunc func(a Adapter, model interface{}) error
+var _callbacks = make(map[string]map[string][]CallbackFunc)
+// RegisterCallback adds a CallbackFunc for an event of a model, by model
+// name, e.g. RegisterCallback(`Position`,BeforeCreate,f)
+func RegisterCallback(model, event string, f CallbackFunc) {
+    if _callbacks[model] == nil {
+        _callbacks[model] = make(map[string][]CallbackFunc)
+    }
+    _callbacks[model][event] = append(_callbacks[model][event],f)
+}
+// runCallbacks runs the callbacks registered for an event of a model
+// and returns the first error
+func runCallbacks(a Adapter, model, event string, o interface{}) error {
+    for _,f := range _callbacks[model][event] {
+        err := f(a,o)
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+// The actions passed to an AuditFunc
+const (
+    AuditCreate = `create`
+    AuditUpdate = `update`
+    AuditDelete = `delete`
+)
+// AuditFunc records a change to the row of table whose primary key pkey
+// is id. changes maps the columns written to their new values and is nil
+// for a delete. It runs inside the transaction of the write, before the
+// row is changed for an update or a delete and after it is inserted for
+// a create, returning an error rolls the write back.
+type AuditFunc func(a Adapter, table, pkey string, id int64, action string, changes map[string]string) error
+var _auditor AuditFunc
+// SetAuditor sets the AuditFunc called by the generated Create, Update,
+// UpdateXxx and Delete methods, nil turns auditing off
+func SetAuditor(f AuditFunc) {
+    _auditor = f
+}
+// recordAudit calls the AuditFunc when there is one
+func recordAudit(a Adapter, table, pkey string, id int64, action string, changes map[string]string) error {
+    if _auditor == nil {
+        return nil
+    }
+    return _auditor(a,table,pkey,id,action,changes)
+}
+// auditValue formats a field for an AuditFunc, DateTimes the way they are
+// stored and nil DateTimes as the empty string
+func auditValue(v interface{}) string {
+    if d, ok := v.(*DateTime); ok {
+        if d.IsZero() {
+            return ``
+        }
+        return d.ToString()
+    }
+    return fmt.Sprintf(`%v`,v)
+}
+// ErrStaleObject is returned by Save and Update on models with a
+// lock_version column when the row was changed since the model was
+// loaded. Reload the model and apply the change again.
+var ErrStaleObject = errors.New(`the row was changed since it was loaded`)
+// ConfigError is a problem with an Adapter configuration, Line and
+// Column are those of the offending YAML node when known
+type ConfigError struct {
+    File string
+    Line int
+    Column int
+    Msg string
+}
+// Error renders the error as file:line:column: message
+func (e *ConfigError) Error() string {
+    var at []string
+    if e.File != `` {
+        at = append(at,e.File)
+    }
+    if e.Line > 0 {
+        at = append(at,strconv.Itoa(e.Line),strconv.Itoa(e.Column))
+    }
+    if len(at) == 0 {
+        return e.Msg
+    }
+    return strings.Join(at,`:`) + `: ` + e.Msg
+}
+// ConfigErrors are all the problems found in a configuration
+type ConfigErrors []*ConfigError
+// Error renders one error per line
+func (es ConfigErrors) Error() string {
+    var lines []string
+    for _, e := range es {
+        lines = append(lines,e.Error())
+    }
+    return strings.Join(lines,"\n")
+}
+func configError(fname string, n *yaml.Node, format string, args ...interface{}) *ConfigError {
+    return &ConfigError{File: fname, Line: n.Line, Column: n.Column, Msg: fmt.Sprintf(format,args...)}
+}
+// ExecResult is what Execute reports about the statement it ran, unlike
+// LastInsertedId and AffectedRows it belongs to the caller alone
+type ExecResult struct {
+    // LastInsertId is the AUTO_INCREMENT id of an INSERT
+    LastInsertId int64
+    // RowsAffected is the number of rows changed
+    RowsAffected int64
+}
+// The retry settings of a new MysqlAdapter, see MysqlAdapter.Retries
+const (
+    DefaultRetries = 2
+    DefaultRetryBackoff = 100 * time.Millisecond
+)
+// Health is the report of Adapter.Health on the database connection
+type Health struct {
+    // Err is why the database cannot be used, nil when it can
+    Err error
+    // Latency is how long the ping took
+    Latency time.Duration
+    // Stats are the statistics of the connection pool
+    Stats sql.DBStats
+}
+// OK returns true when the database can be used
+func (h Health) OK() bool {
+    return h.Err == nil
+}
+// String renders the report on one line
+func (h Health) String() string {
+    if h.OK() == false {
+        return fmt.Sprintf(`unavailable: %s`,h.Err)
+    }
+    return fmt.Sprintf(`ok, ping %s, %d open connections (%d in use, %d idle), waited %d times`,
+        h.Latency,h.Stats.OpenConnections,h.Stats.InUse,h.Stats.Idle,h.Stats.WaitCount)
+}
+// StatementStats are the statistics of the statements sharing a Shape
+type StatementStats struct {
+    // Shape is the statement with its values replaced, see NormalizeSQL
+    Shape string
+    Count int64
+    Errors int64
+    // Slow is how many took at least the SlowQuery of the Adapter
+    Slow int64
+    // Rows is the total of the rows read or changed
+    Rows int64
+    Total time.Duration
+    Max time.Duration
+}
+// Mean returns the average duration of the statements
+func (s StatementStats) Mean() time.Duration {
+    if s.Count == 0 {
+        return 0
+    }
+    return s.Total / time.Duration(s.Count)
+}
+// String renders the statistics on one line
+func (s StatementStats) String() string {
+    return fmt.Sprintf(`count %d total %s mean %s max %s rows %d errors %d slow %d: %s`,
+        s.Count,s.Total,s.Mean(),s.Max,s.Rows,s.Errors,s.Slow,s.Shape)
+}
+var (
+    _sqlStringRegexp = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.|"")*"`)
+    _sqlNumberRegexp = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+    _sqlListRegexp = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+    _sqlListsRegexp = regexp.MustCompile(`\(\.\.\.\)(?:\s*,\s*\(\.\.\.\))+`)
+    _sqlSpaceRegexp = regexp.MustCompile(`\s+`)
+)
+// NormalizeSQL returns the shape of q: the literal strings and numbers
+// become ?, lists of them (...) and runs of such lists (...),... so
+// that the statements of a finder share one shape whatever the values.
+func NormalizeSQL(q string) string {
+    q = _sqlStringRegexp.ReplaceAllString(q,`?`)
+    q = _sqlNumberRegexp.ReplaceAllString(q,`?`)
+    q = _sqlListRegexp.ReplaceAllString(q,`(...)`)
+    q = _sqlListsRegexp.ReplaceAllString(q,`(...),...`)
+    q = _sqlSpaceRegexp.ReplaceAllString(q,` `)
+    return strings.TrimSpace(q)
+}
+// MaskSQL returns q with its literal strings and numbers replaced by ?,
+// unlike NormalizeSQL the statement keeps its length and layout
+func MaskSQL(q string) string {
+    q = _sqlStringRegexp.ReplaceAllString(q,`?`)
+    return _sqlNumberRegexp.ReplaceAllString(q,`?`)
+}
+var _dsnCredentialsRegexp = regexp.MustCompile(`([^\s:@/(]+):\S*@((?:tcp|unix)\(|/)`)
+// RedactDSN replaces the password of the DSNs in s with ***
+func RedactDSN(s string) string {
+    return _dsnCredentialsRegexp.ReplaceAllString(s,`${1}:***@${2}`)
+}
+// finalError marks an error of a statement which may have been applied,
+// retry gives up on it whether it is transient or not
+type finalError struct {
+    err error
+}
+func (e *finalError) Error() string {
+    return e.err.Error()
+}
+func (e *finalError) Unwrap() error {
+    return e.err
+}
+func final(err error) error {
+    return &finalError{err}
+}
+// isRolledBack returns true for the errors after which the server has
+// rolled the statement back: deadlocks and lock wait timeouts
+func isRolledBack(err error) bool {
+    var me *mysqldriver.MySQLError
+    return errors.As(err,&me) && (me.Number == 1205 || me.Number == 1213)
+}
+// isTransient returns true for the errors worth retrying: bad or lost
+// connections, network errors, deadlocks, lock wait timeouts and too
+// many connections
+func isTransient(err error) bool {
+    if errors.Is(err,driver.ErrBadConn) || errors.Is(err,mysqldriver.ErrInvalidConn) {
+        return true
+    }
+    var me *mysqldriver.MySQLError
+    if errors.As(err,&me) {
+        return me.Number == 1040 || me.Number == 1205 || me.Number == 1213
+    }
+    var ne net.Error
+    return errors.As(err,&ne)
+}
+// DefaultBatchSize is the number of rows Upsert writes per statement
+// when the Adapter does not configure one
+const DefaultBatchSize = 500
+// upsertRows implements Adapter.Upsert on top of Transaction, Execute
+// and Query. values are SQL literals in the order of columns, keys are
+// the columns of a unique key of table and a row whose keys match a
+// stored row updates it. The rows are written with multi-row INSERT ...
+// ON DUPLICATE KEY UPDATE statements of at most batchSize rows, in one
+// transaction, and the values under pkey of the rows are read back and
+// returned in the order of values.
+func upsertRows(a Adapter, batchSize int, table, pkey string, keys, columns []string, values [][]string) ([]int64,error) {
+    if batchSize <= 0 {
+        batchSize = DefaultBatchSize
+    }
+    keyIndex := make([]int,len(keys))
+    var quotedKeys []string
+    for i,k := range keys {
+        keyIndex[i] = -1
+        for j,c := range columns {
+            if c == k {
+                keyIndex[i] = j
+            }
+        }
+        if keyIndex[i] < 0 {
+            return nil,a.Oops(fmt.Sprintf(`the key %s of %s is not one of the columns`,k,table))
+        }
+        quotedKeys = append(quotedKeys,fmt.Sprintf("`%s`",k))
+    }
+    var quoted, updates []string
+    for _,c := range columns {
+        quoted = append(quoted,fmt.Sprintf("`%s`",c))
+        updates = append(updates,fmt.Sprintf("`%s` = VALUES(`%s`)",c,c))
+    }
+    ids := make([]int64,len(values))
+    err := a.Transaction(func(ta Adapter) error {
+        for start := 0; start < len(values); start += batchSize {
+            end := start + batchSize
+            if end > len(values) {
+                end = len(values)
+            }
+            var rows, matches, rowKeys []string
+            for _,row := range values[start:end] {
+                if len(row) != len(columns) {
+                    return ta.Oops(fmt.Sprintf(`%v does not have a value for each of %v`,row,columns))
+                }
+                rows = append(rows,`(` + strings.Join(row,`, `) + `)`)
+                var k, plain []string
+                for _,i := range keyIndex {
+                    k = append(k,row[i])
+                    plain = append(plain,strings.Trim(row[i],`'`))
+                }
+                matches = append(matches,`(` + strings.Join(k,`, `) + `)`)
+                rowKeys = append(rowKeys,strings.Join(plain,"\x00"))
+            }
+            q := fmt.Sprintf(`INSERT INTO %s (%s) VALUES %s ON DUPLICATE KEY UPDATE %s`,table,strings.Join(quoted,`, `),strings.Join(rows,`, `),strings.Join(updates,`, `))
+            _, err := ta.Execute(q)
+            if err != nil {
+                return err
+            }
+            q = fmt.Sprintf("SELECT `%s`, %s FROM %s WHERE (%s) IN (%s)",pkey,strings.Join(quotedKeys,`, `),table,strings.Join(quotedKeys,`, `),strings.Join(matches,`, `))
+            results, err := ta.Query(q)
+            if err != nil {
+                return err
+            }
+            found := make(map[string]int64)
+            for _,result := range results {
+                var plain []string
+                for _,k := range keys {
+                    v,_ := result[k].AsString()
+                    plain = append(plain,v)
+                }
+                id,err := result[pkey].AsInt64()
+                if err != nil {
+                    return err
+                }
+                found[strings.Join(plain,"\x00")] = id
+            }
+            for i,k := range rowKeys {
+                id,ok := found[k]
+                if !ok {
+                    return ta.Oops(fmt.Sprintf(`could not read back row %d written to %s`,start + i,table))
+                }
+                ids[start + i] = id
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return nil,err
+    }
+    return ids,nil
+}
+// NewDateTime Returns a basic DateTime value
+func NewDateTime(a Adapter) *DateTime {
+    d := &DateTime{_adapter: a}
+    return d
+}
+func fileExists(p string) bool {
+    if _, err := os.Stat(p); os.IsNotExist(err) {
+        return false
+    }
+    return true
+}
+func filePutContents(p string, txt string) error {
+    f, err := os.Create(p)
+    if err != nil {
+        return err
+    }
+    w := bufio.NewWriter(f)
+    _, err = w.WriteString(txt)
+    w.Flush()
+    return nil
+}
+func fileGetContents(p string) ([]byte, error) {
+    return ioutil.ReadFile(p)
 }
 
-// Note is a Object Relational Mapping to
+// AuditLog is a Object Relational Mapping to
 // the database table that represents it. In this case it is
-// notes. The table name will be Sprintf'd to include
+// audit_log. The table name will be Sprintf'd to include
 // the prefix you define in your YAML configuration for the
 // Adapter.
-type Note struct {
+type AuditLog struct {
     _table string
     _adapter Adapter
     _pkey string // 0 The name of the primary key in this table
     _conds []string
     _new bool
+    _orig *AuditLog
 
     _select []string
     _where []string
@@ -508,89 +1905,131 @@ type Note struct {
 
 
     Id int64
-    Value string
-    PortfolioId int64
-    PositionId int64
+    TableName string
+    RowId int64
+    Action string
+    Changes string
+    ChangedBy string
+    ChangedAt *DateTime
 	// Dirty markers for smart updates
     IsIdDirty bool
-    IsValueDirty bool
-    IsPortfolioIdDirty bool
-    IsPositionIdDirty bool
+    IsTableNameDirty bool
+    IsRowIdDirty bool
+    IsActionDirty bool
+    IsChangesDirty bool
+    IsChangedByDirty bool
+    IsChangedAtDirty bool
 	// Relationships
 }
 
-// NewNote binds an Adapter to a new instance
-// of Note and sets up the _table and primary keys
-func NewNote(a Adapter) *Note {
-    var o Note
-    o._table = fmt.Sprintf("%snotes",a.DatabasePrefix())
+// NewAuditLog binds an Adapter to a new instance
+// of AuditLog and sets up the _table and primary keys
+func NewAuditLog(a Adapter) *AuditLog {
+    var o AuditLog
+    o._table = fmt.Sprintf("%saudit_log",a.DatabasePrefix())
     o._adapter = a
     o._pkey = "id"
-    o._new = false
+    o._new = true
     return &o
 }
 
 
 // GetPrimaryKeyValue returns the value, usually int64 of
 // the PrimaryKey
-func (o *Note) GetPrimaryKeyValue() int64 {
+func (o *AuditLog) GetPrimaryKeyValue() int64 {
     return o.Id
 }
 // GetPrimaryKeyName returns the DB field name
-func (o *Note) GetPrimaryKeyName() string {
+func (o *AuditLog) GetPrimaryKeyName() string {
     return `id`
 }
 
 // GetId returns the value of 
-// Note.Id
-func (o *Note) GetId() int64 {
+// AuditLog.Id
+func (o *AuditLog) GetId() int64 {
     return o.Id
 }
 // SetId sets and marks as dirty the value of
-// Note.Id
-func (o *Note) SetId(arg int64) {
+// AuditLog.Id
+func (o *AuditLog) SetId(arg int64) {
     o.Id = arg
     o.IsIdDirty = true
 }
 
-// GetValue returns the value of 
-// Note.Value
-func (o *Note) GetValue() string {
-    return o.Value
+// GetTableName returns the value of 
+// AuditLog.TableName
+func (o *AuditLog) GetTableName() string {
+    return o.TableName
 }
-// SetValue sets and marks as dirty the value of
-// Note.Value
-func (o *Note) SetValue(arg string) {
-    o.Value = arg
-    o.IsValueDirty = true
+// SetTableName sets and marks as dirty the value of
+// AuditLog.TableName
+func (o *AuditLog) SetTableName(arg string) {
+    o.TableName = arg
+    o.IsTableNameDirty = true
 }
 
-// GetPortfolioId returns the value of 
-// Note.PortfolioId
-func (o *Note) GetPortfolioId() int64 {
-    return o.PortfolioId
+// GetRowId returns the value of 
+// AuditLog.RowId
+func (o *AuditLog) GetRowId() int64 {
+    return o.RowId
 }
-// SetPortfolioId sets and marks as dirty the value of
-// Note.PortfolioId
-func (o *Note) SetPortfolioId(arg int64) {
-    o.PortfolioId = arg
-    o.IsPortfolioIdDirty = true
+// SetRowId sets and marks as dirty the value of
+// AuditLog.RowId
+func (o *AuditLog) SetRowId(arg int64) {
+    o.RowId = arg
+    o.IsRowIdDirty = true
 }
 
-// GetPositionId returns the value of 
-// Note.PositionId
-func (o *Note) GetPositionId() int64 {
-    return o.PositionId
+// GetAction returns the value of 
+// AuditLog.Action
+func (o *AuditLog) GetAction() string {
+    return o.Action
 }
-// SetPositionId sets and marks as dirty the value of
-// Note.PositionId
-func (o *Note) SetPositionId(arg int64) {
-    o.PositionId = arg
-    o.IsPositionIdDirty = true
+// SetAction sets and marks as dirty the value of
+// AuditLog.Action
+func (o *AuditLog) SetAction(arg string) {
+    o.Action = arg
+    o.IsActionDirty = true
+}
+
+// GetChanges returns the value of 
+// AuditLog.Changes
+func (o *AuditLog) GetChanges() string {
+    return o.Changes
+}
+// SetChanges sets and marks as dirty the value of
+// AuditLog.Changes
+func (o *AuditLog) SetChanges(arg string) {
+    o.Changes = arg
+    o.IsChangesDirty = true
+}
+
+// GetChangedBy returns the value of 
+// AuditLog.ChangedBy
+func (o *AuditLog) GetChangedBy() string {
+    return o.ChangedBy
+}
+// SetChangedBy sets and marks as dirty the value of
+// AuditLog.ChangedBy
+func (o *AuditLog) SetChangedBy(arg string) {
+    o.ChangedBy = arg
+    o.IsChangedByDirty = true
+}
+
+// GetChangedAt returns the value of 
+// AuditLog.ChangedAt
+func (o *AuditLog) GetChangedAt() *DateTime {
+    return o.ChangedAt
+}
+// SetChangedAt sets and marks as dirty the value of
+// AuditLog.ChangedAt
+func (o *AuditLog) SetChangedAt(arg *DateTime) {
+    o.ChangedAt = arg
+    o.IsChangedAtDirty = true
 }
 
 // Find searchs against the database table field id and will return bool,error
-// This method is a programatically generated finder for Note
+// This method is a programatically generated finder for AuditLog
 //  
 // Note that Find returns a bool of true|false if found or not, not err, in the case of
 // found == true, the instance data will be filled out!
@@ -599,7 +2038,7 @@ func (o *Note) SetPositionId(arg int64) {
 // i.e. receiver is a pointer!
 //
 //```go
-//      m := NewNote(a)
+//      m := NewAuditLog(a)
 //      found,err := m.Find(23)
 //      .. handle err
 //      if found == false {
@@ -608,9 +2047,9 @@ func (o *Note) SetPositionId(arg int64) {
 //      ... do what you want with m here
 //```
 //
-func (o *Note) Find(_findById int64) (bool,error) {
+func (o *AuditLog) Find(_findById int64) (bool,error) {
 
-    var _modelSlice []*Note
+    var _modelSlice []*AuditLog
     q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "id", _findById)
     results, err := o._adapter.Query(q)
     if err != nil {
@@ -618,7 +2057,7 @@ func (o *Note) Find(_findById int64) (bool,error) {
     }
     
     for _,result := range results {
-        ro := NewNote(o._adapter)
+        ro := NewAuditLog(o._adapter)
         err = ro.FromDBValueMap(result)
         if err != nil {
             return false,o._adapter.Oops(fmt.Sprintf(`%s`,err))
@@ -630,33 +2069,35 @@ func (o *Note) Find(_findById int64) (bool,error) {
         // there was an error!
         return false, o._adapter.Oops(`not found`)
     }
-    o.FromNote(_modelSlice[0])
+    o.FromAuditLog(_modelSlice[0])
+    o._new = false
+    o.ResetDirty()
     return true,nil
 
 }
-// FindByValue searchs against the database table field value and will return []*Note,error
-// This method is a programatically generated finder for Note
+// FindByTableName searchs against the database table field table_name and will return []*AuditLog,error
+// This method is a programatically generated finder for AuditLog
 //
 //```go  
-//    m := NewNote(a)
-//    results,err := m.FindByValue(...)
+//    m := NewAuditLog(a)
+//    results,err := m.FindByTableName(...)
 //    // handle err
 //    for i,r := results {
-//      // now r is an instance of Note
+//      // now r is an instance of AuditLog
 //    }
 //```  
 //
-func (o *Note) FindByValue(_findByValue string) ([]*Note,error) {
+func (o *AuditLog) FindByTableName(_findByTableName string) ([]*AuditLog,error) {
 
-    var _modelSlice []*Note
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "value", _findByValue)
+    var _modelSlice []*AuditLog
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "table_name", _findByTableName)
     results, err := o._adapter.Query(q)
     if err != nil {
         return _modelSlice,err
     }
     
     for _,result := range results {
-        ro := NewNote(o._adapter)
+        ro := NewAuditLog(o._adapter)
         err = ro.FromDBValueMap(result)
         if err != nil {
             return _modelSlice,err
@@ -671,29 +2112,29 @@ func (o *Note) FindByValue(_findByValue string) ([]*Note,error) {
     return _modelSlice,nil
 
 }
-// FindByPortfolioId searchs against the database table field portfolio_id and will return []*Note,error
-// This method is a programatically generated finder for Note
+// FindByRowId searchs against the database table field row_id and will return []*AuditLog,error
+// This method is a programatically generated finder for AuditLog
 //
 //```go  
-//    m := NewNote(a)
-//    results,err := m.FindByPortfolioId(...)
+//    m := NewAuditLog(a)
+//    results,err := m.FindByRowId(...)
 //    // handle err
 //    for i,r := results {
-//      // now r is an instance of Note
+//      // now r is an instance of AuditLog
 //    }
 //```  
 //
-func (o *Note) FindByPortfolioId(_findByPortfolioId int64) ([]*Note,error) {
+func (o *AuditLog) FindByRowId(_findByRowId int64) ([]*AuditLog,error) {
 
-    var _modelSlice []*Note
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "portfolio_id", _findByPortfolioId)
+    var _modelSlice []*AuditLog
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "row_id", _findByRowId)
     results, err := o._adapter.Query(q)
     if err != nil {
         return _modelSlice,err
     }
     
     for _,result := range results {
-        ro := NewNote(o._adapter)
+        ro := NewAuditLog(o._adapter)
         err = ro.FromDBValueMap(result)
         if err != nil {
             return _modelSlice,err
@@ -708,29 +2149,29 @@ func (o *Note) FindByPortfolioId(_findByPortfolioId int64) ([]*Note,error) {
     return _modelSlice,nil
 
 }
-// FindByPositionId searchs against the database table field position_id and will return []*Note,error
-// This method is a programatically generated finder for Note
+// FindByAction searchs against the database table field action and will return []*AuditLog,error
+// This method is a programatically generated finder for AuditLog
 //
 //```go  
-//    m := NewNote(a)
-//    results,err := m.FindByPositionId(...)
+//    m := NewAuditLog(a)
+//    results,err := m.FindByAction(...)
 //    // handle err
 //    for i,r := results {
-//      // now r is an instance of Note
+//      // now r is an instance of AuditLog
 //    }
 //```  
 //
-func (o *Note) FindByPositionId(_findByPositionId int64) ([]*Note,error) {
+func (o *AuditLog) FindByAction(_findByAction string) ([]*AuditLog,error) {
 
-    var _modelSlice []*Note
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "position_id", _findByPositionId)
+    var _modelSlice []*AuditLog
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "action", _findByAction)
     results, err := o._adapter.Query(q)
     if err != nil {
         return _modelSlice,err
     }
     
     for _,result := range results {
-        ro := NewNote(o._adapter)
+        ro := NewAuditLog(o._adapter)
         err = ro.FromDBValueMap(result)
         if err != nil {
             return _modelSlice,err
@@ -745,158 +2186,649 @@ func (o *Note) FindByPositionId(_findByPositionId int64) ([]*Note,error) {
     return _modelSlice,nil
 
 }
+// FindByChanges searchs against the database table field changes and will return []*AuditLog,error
+// This method is a programatically generated finder for AuditLog
+//
+//```go  
+//    m := NewAuditLog(a)
+//    results,err := m.FindByChanges(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of AuditLog
+//    }
+//```  
+//
+func (o *AuditLog) FindByChanges(_findByChanges string) ([]*AuditLog,error) {
 
-// FromDBValueMap Converts a DBValueMap returned from Adapter.Query to a Note
-func (o *Note) FromDBValueMap(m map[string]DBValue) error {
-	_Id,err := m["id"].AsInt64()
+    var _modelSlice []*AuditLog
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "changes", _findByChanges)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewAuditLog(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+// FindByChangedBy searchs against the database table field changed_by and will return []*AuditLog,error
+// This method is a programatically generated finder for AuditLog
+//
+//```go  
+//    m := NewAuditLog(a)
+//    results,err := m.FindByChangedBy(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of AuditLog
+//    }
+//```  
+//
+func (o *AuditLog) FindByChangedBy(_findByChangedBy string) ([]*AuditLog,error) {
+
+    var _modelSlice []*AuditLog
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "changed_by", _findByChangedBy)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewAuditLog(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+// FindByChangedAt searchs against the database table field changed_at and will return []*AuditLog,error
+// This method is a programatically generated finder for AuditLog
+//
+//```go  
+//    m := NewAuditLog(a)
+//    results,err := m.FindByChangedAt(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of AuditLog
+//    }
+//```  
+//
+func (o *AuditLog) FindByChangedAt(_findByChangedAt *DateTime) ([]*AuditLog,error) {
+
+    var _modelSlice []*AuditLog
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "changed_at", _findByChangedAt)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewAuditLog(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+
+// FromDBValueMap Converts a DBValueMap returned from Adapter.Query to a AuditLog
+func (o *AuditLog) FromDBValueMap(m map[string]DBValue) error {
+    return o.FromRow(DBValueMap(m))
+}
+// FromRow Converts a Row, e.g. from Adapter.QueryEach, to a AuditLog
+func (o *AuditLog) FromRow(r Row) error {
+	_Id,err := r.Get("id").AsInt64()
 	if err != nil {
  		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
 	}
 	o.Id = _Id
-	_Value,err := m["value"].AsString()
+	_TableName,err := r.Get("table_name").AsString()
 	if err != nil {
  		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
 	}
-	o.Value = _Value
-	_PortfolioId,err := m["portfolio_id"].AsInt64()
+	o.TableName = _TableName
+	_RowId,err := r.Get("row_id").AsInt64()
 	if err != nil {
  		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
 	}
-	o.PortfolioId = _PortfolioId
-	_PositionId,err := m["position_id"].AsInt64()
+	o.RowId = _RowId
+	_Action,err := r.Get("action").AsString()
 	if err != nil {
  		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
 	}
-	o.PositionId = _PositionId
+	o.Action = _Action
+	_Changes,err := r.Get("changes").AsString()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Changes = _Changes
+	_ChangedBy,err := r.Get("changed_by").AsString()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.ChangedBy = _ChangedBy
+	_ChangedAt,err := r.Get("changed_at").AsDateTime()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.ChangedAt = _ChangedAt
 
+    o._new = false
+    o.ResetDirty()
  	return nil
 }
-// FromNote A kind of Clone function for Note
-func (o *Note) FromNote(m *Note) {
+// FromAuditLog A kind of Clone function for AuditLog
+func (o *AuditLog) FromAuditLog(m *AuditLog) {
 	o.Id = m.Id
-	o.Value = m.Value
-	o.PortfolioId = m.PortfolioId
-	o.PositionId = m.PositionId
+	o.TableName = m.TableName
+	o.RowId = m.RowId
+	o.Action = m.Action
+	o.Changes = m.Changes
+	o.ChangedBy = m.ChangedBy
+	o.ChangedAt = m.ChangedAt
 
 }
-// Reload A function to forcibly reload Note
-func (o *Note) Reload() error {
+// Reload A function to forcibly reload AuditLog
+func (o *AuditLog) Reload() error {
     _,err := o.Find(o.GetPrimaryKeyValue())
     return err
 }
+// EachAuditLog runs f for every AuditLog q selects, reading the rows
+// one at a time with Adapter.QueryEach. The AuditLog given to f is
+// reused for the next row, copy it with FromAuditLog to keep it.
+func EachAuditLog(a Adapter, q string, f func(*AuditLog) error) error {
+    o := NewAuditLog(a)
+    return a.QueryEach(q,func(r Row) error {
+        err := o.FromRow(r)
+        if err != nil {
+            return err
+        }
+        return f(o)
+    })
+}
 
+// transaction runs f with the model bound to a transaction of its
+// Adapter, see Adapter.Transaction
+func (o *AuditLog) transaction(f func() error) error {
+    _adapter := o._adapter
+    defer func() { o._adapter = _adapter }()
+    return _adapter.Transaction(func(a Adapter) error {
+        o._adapter = a
+        return f()
+    })
+}
 // Save is a dynamic saver 'inherited' by all models
-func (o *Note) Save() error {
+func (o *AuditLog) Save() error {
     if o._new == true {
         return o.Create()
     }
-    var sets []string
-    
-    if o.IsValueDirty == true {
-        sets = append(sets,fmt.Sprintf(`value = '%s'`,o._adapter.SafeString(o.Value)))
-    }
-
-    if o.IsPortfolioIdDirty == true {
-        sets = append(sets,fmt.Sprintf(`portfolio_id = '%d'`,o.PortfolioId))
-    }
-
-    if o.IsPositionIdDirty == true {
-        sets = append(sets,fmt.Sprintf(`position_id = '%d'`,o.PositionId))
-    }
-
-    frmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s = '%d'",o._table,strings.Join(sets,`,`),o._pkey, o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return err
-    }
-    return nil
+    return o.Update()
 }
 // Update is a dynamic updater, it considers whether or not
 // a field is 'dirty' and needs to be updated. Will only work
-// if you use the Getters and Setters
-func (o *Note) Update() error {
-    var sets []string
-    
-    if o.IsValueDirty == true {
-        sets = append(sets,fmt.Sprintf(`value = '%s'`,o._adapter.SafeString(o.Value)))
-    }
+// if you use the Getters and Setters, a model with nothing dirty
+// is not written at all
+func (o *AuditLog) Update() error {
+    if o.IsDirty() == false {
+        return nil
+    }
+    err := o.transaction(func() error {
+        err := runCallbacks(o._adapter,`AuditLog`,BeforeSave,o)
+        if err != nil {
+            return err
+        }
+        err = o.Validate()
+        if err != nil {
+            return err
+        }
+        var sets []string
+        changes := make(map[string]string)
+        
+        if o.IsTableNameDirty == true {
+            sets = append(sets,fmt.Sprintf(`table_name = '%s'`,o._adapter.SafeString(o.TableName)))
+            changes[`table_name`] = auditValue(o.TableName)
+        }
 
-    if o.IsPortfolioIdDirty == true {
-        sets = append(sets,fmt.Sprintf(`portfolio_id = '%d'`,o.PortfolioId))
-    }
+        if o.IsRowIdDirty == true {
+            sets = append(sets,fmt.Sprintf(`row_id = '%d'`,o.RowId))
+            changes[`row_id`] = auditValue(o.RowId)
+        }
 
-    if o.IsPositionIdDirty == true {
-        sets = append(sets,fmt.Sprintf(`position_id = '%d'`,o.PositionId))
-    }
+        if o.IsActionDirty == true {
+            sets = append(sets,fmt.Sprintf(`action = '%s'`,o._adapter.SafeString(o.Action)))
+            changes[`action`] = auditValue(o.Action)
+        }
+
+        if o.IsChangesDirty == true {
+            sets = append(sets,fmt.Sprintf(`changes = '%s'`,o._adapter.SafeString(o.Changes)))
+            changes[`changes`] = auditValue(o.Changes)
+        }
+
+        if o.IsChangedByDirty == true {
+            sets = append(sets,fmt.Sprintf(`changed_by = '%s'`,o._adapter.SafeString(o.ChangedBy)))
+            changes[`changed_by`] = auditValue(o.ChangedBy)
+        }
 
-    frmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s = '%d'",o._table,strings.Join(sets,`,`),o._pkey, o.Id)
-    err := o._adapter.Execute(frmt)
+        if o.IsChangedAtDirty == true {
+            sets = append(sets,fmt.Sprintf(`changed_at = %s`,dateTimeSQL(o.ChangedAt)))
+            changes[`changed_at`] = auditValue(o.ChangedAt)
+        }
+
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,changes)
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s = '%d'",o._table,strings.Join(sets,`,`),o._pkey, o.Id)
+        _, err = o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        return runCallbacks(o._adapter,`AuditLog`,AfterSave,o)
+    })
     if err != nil {
         return err
     }
+    o.ResetDirty()
     return nil
 }
 // Create inserts the model. Calling Save will call this function
 // automatically for new models
-func (o *Note) Create() error {
-    frmt := fmt.Sprintf("INSERT INTO %s (`value`, `portfolio_id`, `position_id`) VALUES ('%s', '%d', '%d')",o._table,o.Value, o.PortfolioId, o.PositionId)
-    err := o._adapter.Execute(frmt)
+func (o *AuditLog) Create() error {
+    err := o.transaction(func() error {
+        err := runCallbacks(o._adapter,`AuditLog`,BeforeSave,o)
+        if err != nil {
+            return err
+        }
+        err = runCallbacks(o._adapter,`AuditLog`,BeforeCreate,o)
+        if err != nil {
+            return err
+        }
+        err = o.Validate()
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("INSERT INTO %s (`table_name`, `row_id`, `action`, `changes`, `changed_by`, `changed_at`) VALUES ('%s', '%d', '%s', '%s', '%s', %s)",o._table,o.TableName, o.RowId, o.Action, o.Changes, o.ChangedBy, dateTimeSQL(o.ChangedAt))
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return o._adapter.Oops(fmt.Sprintf(`%s led to %s`,frmt,err))
+        }
+        o.Id = res.LastInsertId
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditCreate,map[string]string{
+            `table_name`: auditValue(o.TableName),
+            `row_id`: auditValue(o.RowId),
+            `action`: auditValue(o.Action),
+            `changes`: auditValue(o.Changes),
+            `changed_by`: auditValue(o.ChangedBy),
+            `changed_at`: auditValue(o.ChangedAt),
+        })
+        if err != nil {
+            return err
+        }
+        err = runCallbacks(o._adapter,`AuditLog`,AfterCreate,o)
+        if err != nil {
+            return err
+        }
+        return runCallbacks(o._adapter,`AuditLog`,AfterSave,o)
+    })
     if err != nil {
-        return o._adapter.Oops(fmt.Sprintf(`%s led to %s`,frmt,err))
+        return err
     }
-    o.Id = o._adapter.LastInsertedId()
     o._new = false
+    o.ResetDirty()
     return nil
 }
+// Delete removes the model from the database
+func (o *AuditLog) Delete() error {
+    return o.transaction(func() error {
+        err := runCallbacks(o._adapter,`AuditLog`,BeforeDelete,o)
+        if err != nil {
+            return err
+        }
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditDelete,nil)
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("DELETE FROM %s WHERE %s = '%d'",o._table,o._pkey, o.Id)
+        _, err = o._adapter.Execute(frmt)
+        return err
+    })
+}
+
+// Validate checks the model against the constraints of the
+// audit_log table and then runs the validators registered for
+// AuditLog. Create and Save call it before writing.
+func (o *AuditLog) Validate() error {
+    errs := make(ValidationErrors)
+    if o.TableName == "" {
+        errs.Add(`table_name`,`is required`)
+    }
+    if len(o.TableName) > 255 {
+        errs.Add(`table_name`,`is longer than 255`)
+    }
+    if o.RowId == 0 {
+        errs.Add(`row_id`,`is required`)
+    }
+    if o.Action == "" {
+        errs.Add(`action`,`is required`)
+    }
+    if len(o.Action) > 16 {
+        errs.Add(`action`,`is longer than 16`)
+    }
+    if len(o.ChangedBy) > 255 {
+        errs.Add(`changed_by`,`is longer than 255`)
+    }
+    if o.ChangedAt.IsZero() {
+        errs.Add(`changed_at`,`is required`)
+    }
+    return runValidators(o._adapter,`AuditLog`,o,errs)
+}
 
 
-// UpdateValue an immediate DB Query to update a single column, in this
-// case value
-func (o *Note) UpdateValue(_updValue string) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `value` = '%s' WHERE `id` = '%d'",o._table,_updValue,o.Id)
-    err := o._adapter.Execute(frmt)
+// UpdateTableName an immediate DB Query to update a single column, in this
+// case table_name. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *AuditLog) UpdateTableName(_updTableName string) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.TableName, o.IsTableNameDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`table_name`: auditValue(_updTableName)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `table_name` = '%s' WHERE `id` = '%d'",o._table,_updTableName,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.TableName = _updTableName
+        o.IsTableNameDirty = true
+        return runCallbacks(o._adapter,`AuditLog`,AfterSave,o)
+    })
     if err != nil {
+        o.TableName, o.IsTableNameDirty = prev, prevDirty
         return 0,err
     }
-    o.Value = _updValue
-    return o._adapter.AffectedRows(),nil
+    o.IsTableNameDirty = false
+    if o._orig != nil {
+        o._orig.TableName = _updTableName
+    }
+    return affected,nil
 }
 
-// UpdatePortfolioId an immediate DB Query to update a single column, in this
-// case portfolio_id
-func (o *Note) UpdatePortfolioId(_updPortfolioId int64) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `portfolio_id` = '%d' WHERE `id` = '%d'",o._table,_updPortfolioId,o.Id)
-    err := o._adapter.Execute(frmt)
+// UpdateRowId an immediate DB Query to update a single column, in this
+// case row_id. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *AuditLog) UpdateRowId(_updRowId int64) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.RowId, o.IsRowIdDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`row_id`: auditValue(_updRowId)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `row_id` = '%d' WHERE `id` = '%d'",o._table,_updRowId,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.RowId = _updRowId
+        o.IsRowIdDirty = true
+        return runCallbacks(o._adapter,`AuditLog`,AfterSave,o)
+    })
     if err != nil {
+        o.RowId, o.IsRowIdDirty = prev, prevDirty
         return 0,err
     }
-    o.PortfolioId = _updPortfolioId
-    return o._adapter.AffectedRows(),nil
+    o.IsRowIdDirty = false
+    if o._orig != nil {
+        o._orig.RowId = _updRowId
+    }
+    return affected,nil
 }
 
-// UpdatePositionId an immediate DB Query to update a single column, in this
-// case position_id
-func (o *Note) UpdatePositionId(_updPositionId int64) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `position_id` = '%d' WHERE `id` = '%d'",o._table,_updPositionId,o.Id)
-    err := o._adapter.Execute(frmt)
+// UpdateAction an immediate DB Query to update a single column, in this
+// case action. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *AuditLog) UpdateAction(_updAction string) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Action, o.IsActionDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`action`: auditValue(_updAction)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `action` = '%s' WHERE `id` = '%d'",o._table,_updAction,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.Action = _updAction
+        o.IsActionDirty = true
+        return runCallbacks(o._adapter,`AuditLog`,AfterSave,o)
+    })
+    if err != nil {
+        o.Action, o.IsActionDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsActionDirty = false
+    if o._orig != nil {
+        o._orig.Action = _updAction
+    }
+    return affected,nil
+}
+
+// UpdateChanges an immediate DB Query to update a single column, in this
+// case changes. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *AuditLog) UpdateChanges(_updChanges string) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Changes, o.IsChangesDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`changes`: auditValue(_updChanges)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `changes` = '%s' WHERE `id` = '%d'",o._table,_updChanges,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.Changes = _updChanges
+        o.IsChangesDirty = true
+        return runCallbacks(o._adapter,`AuditLog`,AfterSave,o)
+    })
     if err != nil {
+        o.Changes, o.IsChangesDirty = prev, prevDirty
         return 0,err
     }
-    o.PositionId = _updPositionId
-    return o._adapter.AffectedRows(),nil
+    o.IsChangesDirty = false
+    if o._orig != nil {
+        o._orig.Changes = _updChanges
+    }
+    return affected,nil
 }
 
-// Play is a Object Relational Mapping to
+// UpdateChangedBy an immediate DB Query to update a single column, in this
+// case changed_by. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *AuditLog) UpdateChangedBy(_updChangedBy string) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.ChangedBy, o.IsChangedByDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`changed_by`: auditValue(_updChangedBy)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `changed_by` = '%s' WHERE `id` = '%d'",o._table,_updChangedBy,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.ChangedBy = _updChangedBy
+        o.IsChangedByDirty = true
+        return runCallbacks(o._adapter,`AuditLog`,AfterSave,o)
+    })
+    if err != nil {
+        o.ChangedBy, o.IsChangedByDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsChangedByDirty = false
+    if o._orig != nil {
+        o._orig.ChangedBy = _updChangedBy
+    }
+    return affected,nil
+}
+
+// UpdateChangedAt an immediate DB Query to update a single column, in this
+// case changed_at. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *AuditLog) UpdateChangedAt(_updChangedAt *DateTime) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.ChangedAt, o.IsChangedAtDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`changed_at`: auditValue(_updChangedAt)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `changed_at` = %s WHERE `id` = '%d'",o._table,dateTimeSQL(_updChangedAt),o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.ChangedAt = _updChangedAt
+        o.IsChangedAtDirty = true
+        return runCallbacks(o._adapter,`AuditLog`,AfterSave,o)
+    })
+    if err != nil {
+        o.ChangedAt, o.IsChangedAtDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsChangedAtDirty = false
+    if o._orig != nil {
+        o._orig.ChangedAt = _updChangedAt
+    }
+    return affected,nil
+}
+
+// IsDirty returns true when a field was set through its setter since
+// the model was loaded or last saved
+func (o *AuditLog) IsDirty() bool {
+    return len(o.DirtyFields()) > 0
+}
+// DirtyFields returns the columns set through their setters since the
+// model was loaded or last saved, in the order of the table
+func (o *AuditLog) DirtyFields() []string {
+    var fields []string
+    if o.IsTableNameDirty == true {
+        fields = append(fields,`table_name`)
+    }
+    if o.IsRowIdDirty == true {
+        fields = append(fields,`row_id`)
+    }
+    if o.IsActionDirty == true {
+        fields = append(fields,`action`)
+    }
+    if o.IsChangesDirty == true {
+        fields = append(fields,`changes`)
+    }
+    if o.IsChangedByDirty == true {
+        fields = append(fields,`changed_by`)
+    }
+    if o.IsChangedAtDirty == true {
+        fields = append(fields,`changed_at`)
+    }
+    return fields
+}
+// ResetDirty clears the dirty markers and takes a snapshot of the
+// current values as the originals, it is called after loading and
+// saving. The snapshot is reused so loading rows into the same model
+// does not allocate a new one each time.
+func (o *AuditLog) ResetDirty() {
+    o.IsTableNameDirty = false
+    o.IsRowIdDirty = false
+    o.IsActionDirty = false
+    o.IsChangesDirty = false
+    o.IsChangedByDirty = false
+    o.IsChangedAtDirty = false
+    if o._orig == nil {
+        o._orig = &AuditLog{}
+    }
+    orig := o._orig
+    *orig = *o
+    orig._orig = nil
+    if o.ChangedAt != nil {
+        _ChangedAt := *o.ChangedAt
+        orig.ChangedAt = &_ChangedAt
+    }
+}
+// Original returns a column as it was when the model was loaded or last
+// saved, formatted like the audit log, and false for new models
+func (o *AuditLog) Original(column string) (string,bool) {
+    if o._orig == nil {
+        return ``,false
+    }
+    switch column {
+    case `table_name`:
+        return auditValue(o._orig.TableName),true
+    case `row_id`:
+        return auditValue(o._orig.RowId),true
+    case `action`:
+        return auditValue(o._orig.Action),true
+    case `changes`:
+        return auditValue(o._orig.Changes),true
+    case `changed_by`:
+        return auditValue(o._orig.ChangedBy),true
+    case `changed_at`:
+        return auditValue(o._orig.ChangedAt),true
+    }
+    return ``,false
+}
+
+// Note is a Object Relational Mapping to
 // the database table that represents it. In this case it is
-// plays. The table name will be Sprintf'd to include
+// notes. The table name will be Sprintf'd to include
 // the prefix you define in your YAML configuration for the
 // Adapter.
-type Play struct {
+type Note struct {
     _table string
     _adapter Adapter
     _pkey string // 0 The name of the primary key in this table
     _conds []string
     _new bool
+    _orig *Note
 
     _select []string
     _where []string
@@ -908,207 +2840,109 @@ type Play struct {
 
 
     Id int64
+    Value string
+    PortfolioId int64
     PositionId int64
-    Day *DateTime
-    Open int
-    High int
-    Low int
-    Pvolume int
-    Pchange int
-    PchangePercent int
-    AdjClose int
-    DataSource string
 	// Dirty markers for smart updates
     IsIdDirty bool
+    IsValueDirty bool
+    IsPortfolioIdDirty bool
     IsPositionIdDirty bool
-    IsDayDirty bool
-    IsOpenDirty bool
-    IsHighDirty bool
-    IsLowDirty bool
-    IsPvolumeDirty bool
-    IsPchangeDirty bool
-    IsPchangePercentDirty bool
-    IsAdjCloseDirty bool
-    IsDataSourceDirty bool
 	// Relationships
 }
 
-// NewPlay binds an Adapter to a new instance
-// of Play and sets up the _table and primary keys
-func NewPlay(a Adapter) *Play {
-    var o Play
-    o._table = fmt.Sprintf("%splays",a.DatabasePrefix())
+// NewNote binds an Adapter to a new instance
+// of Note and sets up the _table and primary keys
+func NewNote(a Adapter) *Note {
+    var o Note
+    o._table = fmt.Sprintf("%snotes",a.DatabasePrefix())
     o._adapter = a
     o._pkey = "id"
-    o._new = false
+    o._new = true
     return &o
 }
 
 
 // GetPrimaryKeyValue returns the value, usually int64 of
 // the PrimaryKey
-func (o *Play) GetPrimaryKeyValue() int64 {
+func (o *Note) GetPrimaryKeyValue() int64 {
     return o.Id
 }
 // GetPrimaryKeyName returns the DB field name
-func (o *Play) GetPrimaryKeyName() string {
+func (o *Note) GetPrimaryKeyName() string {
     return `id`
 }
 
 // GetId returns the value of 
-// Play.Id
-func (o *Play) GetId() int64 {
+// Note.Id
+func (o *Note) GetId() int64 {
     return o.Id
 }
 // SetId sets and marks as dirty the value of
-// Play.Id
-func (o *Play) SetId(arg int64) {
+// Note.Id
+func (o *Note) SetId(arg int64) {
     o.Id = arg
     o.IsIdDirty = true
 }
 
-// GetPositionId returns the value of 
-// Play.PositionId
-func (o *Play) GetPositionId() int64 {
-    return o.PositionId
+// GetValue returns the value of 
+// Note.Value
+func (o *Note) GetValue() string {
+    return o.Value
 }
-// SetPositionId sets and marks as dirty the value of
-// Play.PositionId
-func (o *Play) SetPositionId(arg int64) {
-    o.PositionId = arg
-    o.IsPositionIdDirty = true
+// SetValue sets and marks as dirty the value of
+// Note.Value
+func (o *Note) SetValue(arg string) {
+    o.Value = arg
+    o.IsValueDirty = true
 }
 
-// GetDay returns the value of 
-// Play.Day
-func (o *Play) GetDay() *DateTime {
-    return o.Day
+// GetPortfolioId returns the value of 
+// Note.PortfolioId
+func (o *Note) GetPortfolioId() int64 {
+    return o.PortfolioId
 }
-// SetDay sets and marks as dirty the value of
-// Play.Day
-func (o *Play) SetDay(arg *DateTime) {
-    o.Day = arg
-    o.IsDayDirty = true
+// SetPortfolioId sets and marks as dirty the value of
+// Note.PortfolioId
+func (o *Note) SetPortfolioId(arg int64) {
+    o.PortfolioId = arg
+    o.IsPortfolioIdDirty = true
 }
 
-// GetOpen returns the value of 
-// Play.Open
-func (o *Play) GetOpen() int {
-    return o.Open
+// GetPositionId returns the value of 
+// Note.PositionId
+func (o *Note) GetPositionId() int64 {
+    return o.PositionId
 }
-// SetOpen sets and marks as dirty the value of
-// Play.Open
-func (o *Play) SetOpen(arg int) {
-    o.Open = arg
-    o.IsOpenDirty = true
+// SetPositionId sets and marks as dirty the value of
+// Note.PositionId
+func (o *Note) SetPositionId(arg int64) {
+    o.PositionId = arg
+    o.IsPositionIdDirty = true
 }
 
-// GetHigh returns the value of 
-// Play.High
-func (o *Play) GetHigh() int {
-    return o.High
-}
-// SetHigh sets and marks as dirty the value of
-// Play.High
-func (o *Play) SetHigh(arg int) {
-    o.High = arg
-    o.IsHighDirty = true
-}
+// Find searchs against the database table field id and will return bool,error
+// This method is a programatically generated finder for Note
+//  
+// Note that Find returns a bool of true|false if found or not, not err, in the case of
+// found == true, the instance data will be filled out!
+//
+// A call to find ALWAYS overwrites the model you call Find on
+// i.e. receiver is a pointer!
+//
+//```go
+//      m := NewNote(a)
+//      found,err := m.Find(23)
+//      .. handle err
+//      if found == false {
+//          // handle found
+//      }
+//      ... do what you want with m here
+//```
+//
+func (o *Note) Find(_findById int64) (bool,error) {
 
-// GetLow returns the value of 
-// Play.Low
-func (o *Play) GetLow() int {
-    return o.Low
-}
-// SetLow sets and marks as dirty the value of
-// Play.Low
-func (o *Play) SetLow(arg int) {
-    o.Low = arg
-    o.IsLowDirty = true
-}
-
-// GetPvolume returns the value of 
-// Play.Pvolume
-func (o *Play) GetPvolume() int {
-    return o.Pvolume
-}
-// SetPvolume sets and marks as dirty the value of
-// Play.Pvolume
-func (o *Play) SetPvolume(arg int) {
-    o.Pvolume = arg
-    o.IsPvolumeDirty = true
-}
-
-// GetPchange returns the value of 
-// Play.Pchange
-func (o *Play) GetPchange() int {
-    return o.Pchange
-}
-// SetPchange sets and marks as dirty the value of
-// Play.Pchange
-func (o *Play) SetPchange(arg int) {
-    o.Pchange = arg
-    o.IsPchangeDirty = true
-}
-
-// GetPchangePercent returns the value of 
-// Play.PchangePercent
-func (o *Play) GetPchangePercent() int {
-    return o.PchangePercent
-}
-// SetPchangePercent sets and marks as dirty the value of
-// Play.PchangePercent
-func (o *Play) SetPchangePercent(arg int) {
-    o.PchangePercent = arg
-    o.IsPchangePercentDirty = true
-}
-
-// GetAdjClose returns the value of 
-// Play.AdjClose
-func (o *Play) GetAdjClose() int {
-    return o.AdjClose
-}
-// SetAdjClose sets and marks as dirty the value of
-// Play.AdjClose
-func (o *Play) SetAdjClose(arg int) {
-    o.AdjClose = arg
-    o.IsAdjCloseDirty = true
-}
-
-// GetDataSource returns the value of 
-// Play.DataSource
-func (o *Play) GetDataSource() string {
-    return o.DataSource
-}
-// SetDataSource sets and marks as dirty the value of
-// Play.DataSource
-func (o *Play) SetDataSource(arg string) {
-    o.DataSource = arg
-    o.IsDataSourceDirty = true
-}
-
-// Find searchs against the database table field id and will return bool,error
-// This method is a programatically generated finder for Play
-//  
-// Note that Find returns a bool of true|false if found or not, not err, in the case of
-// found == true, the instance data will be filled out!
-//
-// A call to find ALWAYS overwrites the model you call Find on
-// i.e. receiver is a pointer!
-//
-//```go
-//      m := NewPlay(a)
-//      found,err := m.Find(23)
-//      .. handle err
-//      if found == false {
-//          // handle found
-//      }
-//      ... do what you want with m here
-//```
-//
-func (o *Play) Find(_findById int64) (bool,error) {
-
-    var _modelSlice []*Play
+    var _modelSlice []*Note
     q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "id", _findById)
     results, err := o._adapter.Query(q)
     if err != nil {
@@ -1116,7 +2950,7 @@ func (o *Play) Find(_findById int64) (bool,error) {
     }
     
     for _,result := range results {
-        ro := NewPlay(o._adapter)
+        ro := NewNote(o._adapter)
         err = ro.FromDBValueMap(result)
         if err != nil {
             return false,o._adapter.Oops(fmt.Sprintf(`%s`,err))
@@ -1128,33 +2962,35 @@ func (o *Play) Find(_findById int64) (bool,error) {
         // there was an error!
         return false, o._adapter.Oops(`not found`)
     }
-    o.FromPlay(_modelSlice[0])
+    o.FromNote(_modelSlice[0])
+    o._new = false
+    o.ResetDirty()
     return true,nil
 
 }
-// FindByPositionId searchs against the database table field position_id and will return []*Play,error
-// This method is a programatically generated finder for Play
+// FindByValue searchs against the database table field value and will return []*Note,error
+// This method is a programatically generated finder for Note
 //
 //```go  
-//    m := NewPlay(a)
-//    results,err := m.FindByPositionId(...)
+//    m := NewNote(a)
+//    results,err := m.FindByValue(...)
 //    // handle err
 //    for i,r := results {
-//      // now r is an instance of Play
+//      // now r is an instance of Note
 //    }
 //```  
 //
-func (o *Play) FindByPositionId(_findByPositionId int64) ([]*Play,error) {
+func (o *Note) FindByValue(_findByValue string) ([]*Note,error) {
 
-    var _modelSlice []*Play
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "position_id", _findByPositionId)
+    var _modelSlice []*Note
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "value", _findByValue)
     results, err := o._adapter.Query(q)
     if err != nil {
         return _modelSlice,err
     }
     
     for _,result := range results {
-        ro := NewPlay(o._adapter)
+        ro := NewNote(o._adapter)
         err = ro.FromDBValueMap(result)
         if err != nil {
             return _modelSlice,err
@@ -1169,29 +3005,29 @@ func (o *Play) FindByPositionId(_findByPositionId int64) ([]*Play,error) {
     return _modelSlice,nil
 
 }
-// FindByDay searchs against the database table field day and will return []*Play,error
-// This method is a programatically generated finder for Play
+// FindByPortfolioId searchs against the database table field portfolio_id and will return []*Note,error
+// This method is a programatically generated finder for Note
 //
 //```go  
-//    m := NewPlay(a)
-//    results,err := m.FindByDay(...)
+//    m := NewNote(a)
+//    results,err := m.FindByPortfolioId(...)
 //    // handle err
 //    for i,r := results {
-//      // now r is an instance of Play
+//      // now r is an instance of Note
 //    }
 //```  
 //
-func (o *Play) FindByDay(_findByDay *DateTime) ([]*Play,error) {
+func (o *Note) FindByPortfolioId(_findByPortfolioId int64) ([]*Note,error) {
 
-    var _modelSlice []*Play
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "day", _findByDay)
+    var _modelSlice []*Note
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "portfolio_id", _findByPortfolioId)
     results, err := o._adapter.Query(q)
     if err != nil {
         return _modelSlice,err
     }
     
     for _,result := range results {
-        ro := NewPlay(o._adapter)
+        ro := NewNote(o._adapter)
         err = ro.FromDBValueMap(result)
         if err != nil {
             return _modelSlice,err
@@ -1206,29 +3042,29 @@ func (o *Play) FindByDay(_findByDay *DateTime) ([]*Play,error) {
     return _modelSlice,nil
 
 }
-// FindByOpen searchs against the database table field open and will return []*Play,error
-// This method is a programatically generated finder for Play
+// FindByPositionId searchs against the database table field position_id and will return []*Note,error
+// This method is a programatically generated finder for Note
 //
 //```go  
-//    m := NewPlay(a)
-//    results,err := m.FindByOpen(...)
+//    m := NewNote(a)
+//    results,err := m.FindByPositionId(...)
 //    // handle err
 //    for i,r := results {
-//      // now r is an instance of Play
+//      // now r is an instance of Note
 //    }
 //```  
 //
-func (o *Play) FindByOpen(_findByOpen int) ([]*Play,error) {
+func (o *Note) FindByPositionId(_findByPositionId int64) ([]*Note,error) {
 
-    var _modelSlice []*Play
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "open", _findByOpen)
+    var _modelSlice []*Note
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "position_id", _findByPositionId)
     results, err := o._adapter.Query(q)
     if err != nil {
         return _modelSlice,err
     }
     
     for _,result := range results {
-        ro := NewPlay(o._adapter)
+        ro := NewNote(o._adapter)
         err = ro.FromDBValueMap(result)
         if err != nil {
             return _modelSlice,err
@@ -1243,693 +3079,561 @@ func (o *Play) FindByOpen(_findByOpen int) ([]*Play,error) {
     return _modelSlice,nil
 
 }
-// FindByHigh searchs against the database table field high and will return []*Play,error
-// This method is a programatically generated finder for Play
-//
-//```go  
-//    m := NewPlay(a)
-//    results,err := m.FindByHigh(...)
-//    // handle err
-//    for i,r := results {
-//      // now r is an instance of Play
-//    }
-//```  
-//
-func (o *Play) FindByHigh(_findByHigh int) ([]*Play,error) {
 
-    var _modelSlice []*Play
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "high", _findByHigh)
-    results, err := o._adapter.Query(q)
-    if err != nil {
-        return _modelSlice,err
-    }
-    
-    for _,result := range results {
-        ro := NewPlay(o._adapter)
-        err = ro.FromDBValueMap(result)
+// FromDBValueMap Converts a DBValueMap returned from Adapter.Query to a Note
+func (o *Note) FromDBValueMap(m map[string]DBValue) error {
+    return o.FromRow(DBValueMap(m))
+}
+// FromRow Converts a Row, e.g. from Adapter.QueryEach, to a Note
+func (o *Note) FromRow(r Row) error {
+	_Id,err := r.Get("id").AsInt64()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Id = _Id
+	_Value,err := r.Get("value").AsString()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Value = _Value
+	_PortfolioId,err := r.Get("portfolio_id").AsInt64()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.PortfolioId = _PortfolioId
+	_PositionId,err := r.Get("position_id").AsInt64()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.PositionId = _PositionId
+
+    o._new = false
+    o.ResetDirty()
+ 	return nil
+}
+// FromNote A kind of Clone function for Note
+func (o *Note) FromNote(m *Note) {
+	o.Id = m.Id
+	o.Value = m.Value
+	o.PortfolioId = m.PortfolioId
+	o.PositionId = m.PositionId
+
+}
+// Reload A function to forcibly reload Note
+func (o *Note) Reload() error {
+    _,err := o.Find(o.GetPrimaryKeyValue())
+    return err
+}
+// EachNote runs f for every Note q selects, reading the rows
+// one at a time with Adapter.QueryEach. The Note given to f is
+// reused for the next row, copy it with FromNote to keep it.
+func EachNote(a Adapter, q string, f func(*Note) error) error {
+    o := NewNote(a)
+    return a.QueryEach(q,func(r Row) error {
+        err := o.FromRow(r)
         if err != nil {
-            return _modelSlice,err
+            return err
         }
-        _modelSlice = append(_modelSlice,ro)
-    }
+        return f(o)
+    })
+}
 
-    if len(_modelSlice) == 0 {
-        // there was an error!
-        return nil, o._adapter.Oops(`no results`)
+// transaction runs f with the model bound to a transaction of its
+// Adapter, see Adapter.Transaction
+func (o *Note) transaction(f func() error) error {
+    _adapter := o._adapter
+    defer func() { o._adapter = _adapter }()
+    return _adapter.Transaction(func(a Adapter) error {
+        o._adapter = a
+        return f()
+    })
+}
+// Save is a dynamic saver 'inherited' by all models
+func (o *Note) Save() error {
+    if o._new == true {
+        return o.Create()
     }
-    return _modelSlice,nil
-
+    return o.Update()
 }
-// FindByLow searchs against the database table field low and will return []*Play,error
-// This method is a programatically generated finder for Play
-//
-//```go  
-//    m := NewPlay(a)
-//    results,err := m.FindByLow(...)
-//    // handle err
-//    for i,r := results {
-//      // now r is an instance of Play
-//    }
-//```  
-//
-func (o *Play) FindByLow(_findByLow int) ([]*Play,error) {
-
-    var _modelSlice []*Play
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "low", _findByLow)
-    results, err := o._adapter.Query(q)
-    if err != nil {
-        return _modelSlice,err
+// Update is a dynamic updater, it considers whether or not
+// a field is 'dirty' and needs to be updated. Will only work
+// if you use the Getters and Setters, a model with nothing dirty
+// is not written at all
+func (o *Note) Update() error {
+    if o.IsDirty() == false {
+        return nil
     }
-    
-    for _,result := range results {
-        ro := NewPlay(o._adapter)
-        err = ro.FromDBValueMap(result)
+    err := o.transaction(func() error {
+        err := runCallbacks(o._adapter,`Note`,BeforeSave,o)
         if err != nil {
-            return _modelSlice,err
+            return err
+        }
+        err = o.Validate()
+        if err != nil {
+            return err
+        }
+        var sets []string
+        changes := make(map[string]string)
+        
+        if o.IsValueDirty == true {
+            sets = append(sets,fmt.Sprintf(`value = '%s'`,o._adapter.SafeString(o.Value)))
+            changes[`value`] = auditValue(o.Value)
         }
-        _modelSlice = append(_modelSlice,ro)
-    }
 
-    if len(_modelSlice) == 0 {
-        // there was an error!
-        return nil, o._adapter.Oops(`no results`)
-    }
-    return _modelSlice,nil
+        if o.IsPortfolioIdDirty == true {
+            sets = append(sets,fmt.Sprintf(`portfolio_id = '%d'`,o.PortfolioId))
+            changes[`portfolio_id`] = auditValue(o.PortfolioId)
+        }
 
-}
-// FindByPvolume searchs against the database table field pvolume and will return []*Play,error
-// This method is a programatically generated finder for Play
-//
-//```go  
-//    m := NewPlay(a)
-//    results,err := m.FindByPvolume(...)
-//    // handle err
-//    for i,r := results {
-//      // now r is an instance of Play
-//    }
-//```  
-//
-func (o *Play) FindByPvolume(_findByPvolume int) ([]*Play,error) {
+        if o.IsPositionIdDirty == true {
+            sets = append(sets,fmt.Sprintf(`position_id = '%d'`,o.PositionId))
+            changes[`position_id`] = auditValue(o.PositionId)
+        }
 
-    var _modelSlice []*Play
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "pvolume", _findByPvolume)
-    results, err := o._adapter.Query(q)
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,changes)
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s = '%d'",o._table,strings.Join(sets,`,`),o._pkey, o.Id)
+        _, err = o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        return runCallbacks(o._adapter,`Note`,AfterSave,o)
+    })
     if err != nil {
-        return _modelSlice,err
+        return err
     }
-    
-    for _,result := range results {
-        ro := NewPlay(o._adapter)
-        err = ro.FromDBValueMap(result)
+    o.ResetDirty()
+    return nil
+}
+// Create inserts the model. Calling Save will call this function
+// automatically for new models
+func (o *Note) Create() error {
+    err := o.transaction(func() error {
+        err := runCallbacks(o._adapter,`Note`,BeforeSave,o)
         if err != nil {
-            return _modelSlice,err
+            return err
         }
-        _modelSlice = append(_modelSlice,ro)
+        err = runCallbacks(o._adapter,`Note`,BeforeCreate,o)
+        if err != nil {
+            return err
+        }
+        err = o.Validate()
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("INSERT INTO %s (`value`, `portfolio_id`, `position_id`) VALUES ('%s', '%d', '%d')",o._table,o.Value, o.PortfolioId, o.PositionId)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return o._adapter.Oops(fmt.Sprintf(`%s led to %s`,frmt,err))
+        }
+        o.Id = res.LastInsertId
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditCreate,map[string]string{
+            `value`: auditValue(o.Value),
+            `portfolio_id`: auditValue(o.PortfolioId),
+            `position_id`: auditValue(o.PositionId),
+        })
+        if err != nil {
+            return err
+        }
+        err = runCallbacks(o._adapter,`Note`,AfterCreate,o)
+        if err != nil {
+            return err
+        }
+        return runCallbacks(o._adapter,`Note`,AfterSave,o)
+    })
+    if err != nil {
+        return err
     }
+    o._new = false
+    o.ResetDirty()
+    return nil
+}
+// Delete removes the model from the database
+func (o *Note) Delete() error {
+    return o.transaction(func() error {
+        err := runCallbacks(o._adapter,`Note`,BeforeDelete,o)
+        if err != nil {
+            return err
+        }
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditDelete,nil)
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("DELETE FROM %s WHERE %s = '%d'",o._table,o._pkey, o.Id)
+        _, err = o._adapter.Execute(frmt)
+        return err
+    })
+}
 
-    if len(_modelSlice) == 0 {
-        // there was an error!
-        return nil, o._adapter.Oops(`no results`)
+// Validate checks the model against the constraints of the
+// notes table and then runs the validators registered for
+// Note. Create and Save call it before writing.
+func (o *Note) Validate() error {
+    errs := make(ValidationErrors)
+    if o.PortfolioId == 0 {
+        errs.Add(`portfolio_id`,`is required`)
     }
-    return _modelSlice,nil
-
+    if o.PositionId == 0 {
+        errs.Add(`position_id`,`is required`)
+    }
+    return runValidators(o._adapter,`Note`,o,errs)
 }
-// FindByPchange searchs against the database table field pchange and will return []*Play,error
-// This method is a programatically generated finder for Play
-//
-//```go  
-//    m := NewPlay(a)
-//    results,err := m.FindByPchange(...)
-//    // handle err
-//    for i,r := results {
-//      // now r is an instance of Play
-//    }
-//```  
-//
-func (o *Play) FindByPchange(_findByPchange int) ([]*Play,error) {
 
-    var _modelSlice []*Play
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "pchange", _findByPchange)
-    results, err := o._adapter.Query(q)
-    if err != nil {
-        return _modelSlice,err
-    }
-    
-    for _,result := range results {
-        ro := NewPlay(o._adapter)
-        err = ro.FromDBValueMap(result)
+
+// UpdateValue an immediate DB Query to update a single column, in this
+// case value. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Note) UpdateValue(_updValue string) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Value, o.IsValueDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`value`: auditValue(_updValue)})
         if err != nil {
-            return _modelSlice,err
+            return err
         }
-        _modelSlice = append(_modelSlice,ro)
+        frmt := fmt.Sprintf("UPDATE %s SET `value` = '%s' WHERE `id` = '%d'",o._table,_updValue,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.Value = _updValue
+        o.IsValueDirty = true
+        return runCallbacks(o._adapter,`Note`,AfterSave,o)
+    })
+    if err != nil {
+        o.Value, o.IsValueDirty = prev, prevDirty
+        return 0,err
     }
-
-    if len(_modelSlice) == 0 {
-        // there was an error!
-        return nil, o._adapter.Oops(`no results`)
+    o.IsValueDirty = false
+    if o._orig != nil {
+        o._orig.Value = _updValue
     }
-    return _modelSlice,nil
-
+    return affected,nil
 }
-// FindByPchangePercent searchs against the database table field pchange_percent and will return []*Play,error
-// This method is a programatically generated finder for Play
-//
-//```go  
-//    m := NewPlay(a)
-//    results,err := m.FindByPchangePercent(...)
-//    // handle err
-//    for i,r := results {
-//      // now r is an instance of Play
-//    }
-//```  
-//
-func (o *Play) FindByPchangePercent(_findByPchangePercent int) ([]*Play,error) {
 
-    var _modelSlice []*Play
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "pchange_percent", _findByPchangePercent)
-    results, err := o._adapter.Query(q)
-    if err != nil {
-        return _modelSlice,err
-    }
-    
-    for _,result := range results {
-        ro := NewPlay(o._adapter)
-        err = ro.FromDBValueMap(result)
+// UpdatePortfolioId an immediate DB Query to update a single column, in this
+// case portfolio_id. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Note) UpdatePortfolioId(_updPortfolioId int64) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.PortfolioId, o.IsPortfolioIdDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`portfolio_id`: auditValue(_updPortfolioId)})
         if err != nil {
-            return _modelSlice,err
+            return err
         }
-        _modelSlice = append(_modelSlice,ro)
+        frmt := fmt.Sprintf("UPDATE %s SET `portfolio_id` = '%d' WHERE `id` = '%d'",o._table,_updPortfolioId,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.PortfolioId = _updPortfolioId
+        o.IsPortfolioIdDirty = true
+        return runCallbacks(o._adapter,`Note`,AfterSave,o)
+    })
+    if err != nil {
+        o.PortfolioId, o.IsPortfolioIdDirty = prev, prevDirty
+        return 0,err
     }
-
-    if len(_modelSlice) == 0 {
-        // there was an error!
-        return nil, o._adapter.Oops(`no results`)
+    o.IsPortfolioIdDirty = false
+    if o._orig != nil {
+        o._orig.PortfolioId = _updPortfolioId
     }
-    return _modelSlice,nil
-
+    return affected,nil
 }
-// FindByAdjClose searchs against the database table field adj_close and will return []*Play,error
-// This method is a programatically generated finder for Play
-//
-//```go  
-//    m := NewPlay(a)
-//    results,err := m.FindByAdjClose(...)
-//    // handle err
-//    for i,r := results {
-//      // now r is an instance of Play
-//    }
-//```  
-//
-func (o *Play) FindByAdjClose(_findByAdjClose int) ([]*Play,error) {
 
-    var _modelSlice []*Play
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "adj_close", _findByAdjClose)
-    results, err := o._adapter.Query(q)
-    if err != nil {
-        return _modelSlice,err
-    }
-    
-    for _,result := range results {
-        ro := NewPlay(o._adapter)
-        err = ro.FromDBValueMap(result)
+// UpdatePositionId an immediate DB Query to update a single column, in this
+// case position_id. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Note) UpdatePositionId(_updPositionId int64) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.PositionId, o.IsPositionIdDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`position_id`: auditValue(_updPositionId)})
         if err != nil {
-            return _modelSlice,err
+            return err
         }
-        _modelSlice = append(_modelSlice,ro)
+        frmt := fmt.Sprintf("UPDATE %s SET `position_id` = '%d' WHERE `id` = '%d'",o._table,_updPositionId,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.PositionId = _updPositionId
+        o.IsPositionIdDirty = true
+        return runCallbacks(o._adapter,`Note`,AfterSave,o)
+    })
+    if err != nil {
+        o.PositionId, o.IsPositionIdDirty = prev, prevDirty
+        return 0,err
     }
-
-    if len(_modelSlice) == 0 {
-        // there was an error!
-        return nil, o._adapter.Oops(`no results`)
+    o.IsPositionIdDirty = false
+    if o._orig != nil {
+        o._orig.PositionId = _updPositionId
     }
-    return _modelSlice,nil
-
+    return affected,nil
 }
-// FindByDataSource searchs against the database table field data_source and will return []*Play,error
-// This method is a programatically generated finder for Play
-//
-//```go  
-//    m := NewPlay(a)
-//    results,err := m.FindByDataSource(...)
-//    // handle err
-//    for i,r := results {
-//      // now r is an instance of Play
-//    }
-//```  
-//
-func (o *Play) FindByDataSource(_findByDataSource string) ([]*Play,error) {
 
-    var _modelSlice []*Play
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "data_source", _findByDataSource)
-    results, err := o._adapter.Query(q)
-    if err != nil {
-        return _modelSlice,err
+// IsDirty returns true when a field was set through its setter since
+// the model was loaded or last saved
+func (o *Note) IsDirty() bool {
+    return len(o.DirtyFields()) > 0
+}
+// DirtyFields returns the columns set through their setters since the
+// model was loaded or last saved, in the order of the table
+func (o *Note) DirtyFields() []string {
+    var fields []string
+    if o.IsValueDirty == true {
+        fields = append(fields,`value`)
     }
-    
-    for _,result := range results {
-        ro := NewPlay(o._adapter)
-        err = ro.FromDBValueMap(result)
-        if err != nil {
-            return _modelSlice,err
-        }
-        _modelSlice = append(_modelSlice,ro)
+    if o.IsPortfolioIdDirty == true {
+        fields = append(fields,`portfolio_id`)
     }
+    if o.IsPositionIdDirty == true {
+        fields = append(fields,`position_id`)
+    }
+    return fields
+}
+// ResetDirty clears the dirty markers and takes a snapshot of the
+// current values as the originals, it is called after loading and
+// saving. The snapshot is reused so loading rows into the same model
+// does not allocate a new one each time.
+func (o *Note) ResetDirty() {
+    o.IsValueDirty = false
+    o.IsPortfolioIdDirty = false
+    o.IsPositionIdDirty = false
+    if o._orig == nil {
+        o._orig = &Note{}
+    }
+    orig := o._orig
+    *orig = *o
+    orig._orig = nil
+}
+// Original returns a column as it was when the model was loaded or last
+// saved, formatted like the audit log, and false for new models
+func (o *Note) Original(column string) (string,bool) {
+    if o._orig == nil {
+        return ``,false
+    }
+    switch column {
+    case `value`:
+        return auditValue(o._orig.Value),true
+    case `portfolio_id`:
+        return auditValue(o._orig.PortfolioId),true
+    case `position_id`:
+        return auditValue(o._orig.PositionId),true
+    }
+    return ``,false
+}
+
+// Play is a Object Relational Mapping to
+// the database table that represents it. In this case it is
+// plays. The table name will be Sprintf'd to include
+// the prefix you define in your YAML configuration for the
+// Adapter.
+type Play struct {
+    _table string
+    _adapter Adapter
+    _pkey string // 0 The name of the primary key in this table
+    _conds []string
+    _new bool
+    _orig *Play
+
+    _select []string
+    _where []string
+    _cols []string
+    _values []string
+    _sets map[string]string
+    _limit string
+    _order string
 
-    if len(_modelSlice) == 0 {
-        // there was an error!
-        return nil, o._adapter.Oops(`no results`)
-    }
-    return _modelSlice,nil
 
+    Id int64
+    PositionId int64
+    Day *DateTime
+    Open int
+    High int
+    Low int
+    Pvolume int
+    Pchange int
+    PchangePercent int
+    AdjClose int
+    DataSource string
+	// Dirty markers for smart updates
+    IsIdDirty bool
+    IsPositionIdDirty bool
+    IsDayDirty bool
+    IsOpenDirty bool
+    IsHighDirty bool
+    IsLowDirty bool
+    IsPvolumeDirty bool
+    IsPchangeDirty bool
+    IsPchangePercentDirty bool
+    IsAdjCloseDirty bool
+    IsDataSourceDirty bool
+	// Relationships
 }
 
-// FromDBValueMap Converts a DBValueMap returned from Adapter.Query to a Play
-func (o *Play) FromDBValueMap(m map[string]DBValue) error {
-	_Id,err := m["id"].AsInt64()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.Id = _Id
-	_PositionId,err := m["position_id"].AsInt64()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.PositionId = _PositionId
-	_Day,err := m["day"].AsDateTime()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.Day = _Day
-	_Open,err := m["open"].AsInt()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.Open = _Open
-	_High,err := m["high"].AsInt()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.High = _High
-	_Low,err := m["low"].AsInt()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.Low = _Low
-	_Pvolume,err := m["pvolume"].AsInt()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.Pvolume = _Pvolume
-	_Pchange,err := m["pchange"].AsInt()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.Pchange = _Pchange
-	_PchangePercent,err := m["pchange_percent"].AsInt()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.PchangePercent = _PchangePercent
-	_AdjClose,err := m["adj_close"].AsInt()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.AdjClose = _AdjClose
-	_DataSource,err := m["data_source"].AsString()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.DataSource = _DataSource
-
- 	return nil
+// NewPlay binds an Adapter to a new instance
+// of Play and sets up the _table and primary keys
+func NewPlay(a Adapter) *Play {
+    var o Play
+    o._table = fmt.Sprintf("%splays",a.DatabasePrefix())
+    o._adapter = a
+    o._pkey = "id"
+    o._new = true
+    return &o
 }
-// FromPlay A kind of Clone function for Play
-func (o *Play) FromPlay(m *Play) {
-	o.Id = m.Id
-	o.PositionId = m.PositionId
-	o.Day = m.Day
-	o.Open = m.Open
-	o.High = m.High
-	o.Low = m.Low
-	o.Pvolume = m.Pvolume
-	o.Pchange = m.Pchange
-	o.PchangePercent = m.PchangePercent
-	o.AdjClose = m.AdjClose
-	o.DataSource = m.DataSource
 
+
+// GetPrimaryKeyValue returns the value, usually int64 of
+// the PrimaryKey
+func (o *Play) GetPrimaryKeyValue() int64 {
+    return o.Id
 }
-// Reload A function to forcibly reload Play
-func (o *Play) Reload() error {
-    _,err := o.Find(o.GetPrimaryKeyValue())
-    return err
+// GetPrimaryKeyName returns the DB field name
+func (o *Play) GetPrimaryKeyName() string {
+    return `id`
 }
 
-// Save is a dynamic saver 'inherited' by all models
-func (o *Play) Save() error {
-    if o._new == true {
-        return o.Create()
-    }
-    var sets []string
-    
-    if o.IsPositionIdDirty == true {
-        sets = append(sets,fmt.Sprintf(`position_id = '%d'`,o.PositionId))
-    }
-
-    if o.IsDayDirty == true {
-        sets = append(sets,fmt.Sprintf(`day = '%s'`,o.Day))
-    }
-
-    if o.IsOpenDirty == true {
-        sets = append(sets,fmt.Sprintf(`open = '%d'`,o.Open))
-    }
-
-    if o.IsHighDirty == true {
-        sets = append(sets,fmt.Sprintf(`high = '%d'`,o.High))
-    }
-
-    if o.IsLowDirty == true {
-        sets = append(sets,fmt.Sprintf(`low = '%d'`,o.Low))
-    }
-
-    if o.IsPvolumeDirty == true {
-        sets = append(sets,fmt.Sprintf(`pvolume = '%d'`,o.Pvolume))
-    }
-
-    if o.IsPchangeDirty == true {
-        sets = append(sets,fmt.Sprintf(`pchange = '%d'`,o.Pchange))
-    }
-
-    if o.IsPchangePercentDirty == true {
-        sets = append(sets,fmt.Sprintf(`pchange_percent = '%d'`,o.PchangePercent))
-    }
-
-    if o.IsAdjCloseDirty == true {
-        sets = append(sets,fmt.Sprintf(`adj_close = '%d'`,o.AdjClose))
-    }
-
-    if o.IsDataSourceDirty == true {
-        sets = append(sets,fmt.Sprintf(`data_source = '%s'`,o._adapter.SafeString(o.DataSource)))
-    }
-
-    frmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s = '%d'",o._table,strings.Join(sets,`,`),o._pkey, o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return err
-    }
-    return nil
+// GetId returns the value of 
+// Play.Id
+func (o *Play) GetId() int64 {
+    return o.Id
+}
+// SetId sets and marks as dirty the value of
+// Play.Id
+func (o *Play) SetId(arg int64) {
+    o.Id = arg
+    o.IsIdDirty = true
 }
-// Update is a dynamic updater, it considers whether or not
-// a field is 'dirty' and needs to be updated. Will only work
-// if you use the Getters and Setters
-func (o *Play) Update() error {
-    var sets []string
-    
-    if o.IsPositionIdDirty == true {
-        sets = append(sets,fmt.Sprintf(`position_id = '%d'`,o.PositionId))
-    }
-
-    if o.IsDayDirty == true {
-        sets = append(sets,fmt.Sprintf(`day = '%s'`,o.Day))
-    }
-
-    if o.IsOpenDirty == true {
-        sets = append(sets,fmt.Sprintf(`open = '%d'`,o.Open))
-    }
-
-    if o.IsHighDirty == true {
-        sets = append(sets,fmt.Sprintf(`high = '%d'`,o.High))
-    }
-
-    if o.IsLowDirty == true {
-        sets = append(sets,fmt.Sprintf(`low = '%d'`,o.Low))
-    }
-
-    if o.IsPvolumeDirty == true {
-        sets = append(sets,fmt.Sprintf(`pvolume = '%d'`,o.Pvolume))
-    }
-
-    if o.IsPchangeDirty == true {
-        sets = append(sets,fmt.Sprintf(`pchange = '%d'`,o.Pchange))
-    }
-
-    if o.IsPchangePercentDirty == true {
-        sets = append(sets,fmt.Sprintf(`pchange_percent = '%d'`,o.PchangePercent))
-    }
-
-    if o.IsAdjCloseDirty == true {
-        sets = append(sets,fmt.Sprintf(`adj_close = '%d'`,o.AdjClose))
-    }
-
-    if o.IsDataSourceDirty == true {
-        sets = append(sets,fmt.Sprintf(`data_source = '%s'`,o._adapter.SafeString(o.DataSource)))
-    }
 
-    frmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s = '%d'",o._table,strings.Join(sets,`,`),o._pkey, o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return err
-    }
-    return nil
+// GetPositionId returns the value of 
+// Play.PositionId
+func (o *Play) GetPositionId() int64 {
+    return o.PositionId
 }
-// Create inserts the model. Calling Save will call this function
-// automatically for new models
-func (o *Play) Create() error {
-    frmt := fmt.Sprintf("INSERT INTO %s (`position_id`, `day`, `open`, `high`, `low`, `pvolume`, `pchange`, `pchange_percent`, `adj_close`, `data_source`) VALUES ('%d', '%s', '%d', '%d', '%d', '%d', '%d', '%d', '%d', '%s')",o._table,o.PositionId, o.Day.ToString(), o.Open, o.High, o.Low, o.Pvolume, o.Pchange, o.PchangePercent, o.AdjClose, o.DataSource)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return o._adapter.Oops(fmt.Sprintf(`%s led to %s`,frmt,err))
-    }
-    o.Id = o._adapter.LastInsertedId()
-    o._new = false
-    return nil
+// SetPositionId sets and marks as dirty the value of
+// Play.PositionId
+func (o *Play) SetPositionId(arg int64) {
+    o.PositionId = arg
+    o.IsPositionIdDirty = true
 }
 
-
-// UpdatePositionId an immediate DB Query to update a single column, in this
-// case position_id
-func (o *Play) UpdatePositionId(_updPositionId int64) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `position_id` = '%d' WHERE `id` = '%d'",o._table,_updPositionId,o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return 0,err
-    }
-    o.PositionId = _updPositionId
-    return o._adapter.AffectedRows(),nil
+// GetDay returns the value of 
+// Play.Day
+func (o *Play) GetDay() *DateTime {
+    return o.Day
 }
-
-// UpdateDay an immediate DB Query to update a single column, in this
-// case day
-func (o *Play) UpdateDay(_updDay *DateTime) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `day` = '%s' WHERE `id` = '%d'",o._table,_updDay,o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return 0,err
-    }
-    o.Day = _updDay
-    return o._adapter.AffectedRows(),nil
+// SetDay sets and marks as dirty the value of
+// Play.Day
+func (o *Play) SetDay(arg *DateTime) {
+    o.Day = arg
+    o.IsDayDirty = true
 }
 
-// UpdateOpen an immediate DB Query to update a single column, in this
-// case open
-func (o *Play) UpdateOpen(_updOpen int) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `open` = '%d' WHERE `id` = '%d'",o._table,_updOpen,o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return 0,err
-    }
-    o.Open = _updOpen
-    return o._adapter.AffectedRows(),nil
+// GetOpen returns the value of 
+// Play.Open
+func (o *Play) GetOpen() int {
+    return o.Open
 }
-
-// UpdateHigh an immediate DB Query to update a single column, in this
-// case high
-func (o *Play) UpdateHigh(_updHigh int) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `high` = '%d' WHERE `id` = '%d'",o._table,_updHigh,o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return 0,err
-    }
-    o.High = _updHigh
-    return o._adapter.AffectedRows(),nil
+// SetOpen sets and marks as dirty the value of
+// Play.Open
+func (o *Play) SetOpen(arg int) {
+    o.Open = arg
+    o.IsOpenDirty = true
 }
 
-// UpdateLow an immediate DB Query to update a single column, in this
-// case low
-func (o *Play) UpdateLow(_updLow int) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `low` = '%d' WHERE `id` = '%d'",o._table,_updLow,o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return 0,err
-    }
-    o.Low = _updLow
-    return o._adapter.AffectedRows(),nil
+// GetHigh returns the value of 
+// Play.High
+func (o *Play) GetHigh() int {
+    return o.High
 }
-
-// UpdatePvolume an immediate DB Query to update a single column, in this
-// case pvolume
-func (o *Play) UpdatePvolume(_updPvolume int) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `pvolume` = '%d' WHERE `id` = '%d'",o._table,_updPvolume,o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return 0,err
-    }
-    o.Pvolume = _updPvolume
-    return o._adapter.AffectedRows(),nil
+// SetHigh sets and marks as dirty the value of
+// Play.High
+func (o *Play) SetHigh(arg int) {
+    o.High = arg
+    o.IsHighDirty = true
 }
 
-// UpdatePchange an immediate DB Query to update a single column, in this
-// case pchange
-func (o *Play) UpdatePchange(_updPchange int) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `pchange` = '%d' WHERE `id` = '%d'",o._table,_updPchange,o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return 0,err
-    }
-    o.Pchange = _updPchange
-    return o._adapter.AffectedRows(),nil
+// GetLow returns the value of 
+// Play.Low
+func (o *Play) GetLow() int {
+    return o.Low
+}
+// SetLow sets and marks as dirty the value of
+// Play.Low
+func (o *Play) SetLow(arg int) {
+    o.Low = arg
+    o.IsLowDirty = true
 }
 
-// UpdatePchangePercent an immediate DB Query to update a single column, in this
-// case pchange_percent
-func (o *Play) UpdatePchangePercent(_updPchangePercent int) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `pchange_percent` = '%d' WHERE `id` = '%d'",o._table,_updPchangePercent,o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return 0,err
-    }
-    o.PchangePercent = _updPchangePercent
-    return o._adapter.AffectedRows(),nil
-}
-
-// UpdateAdjClose an immediate DB Query to update a single column, in this
-// case adj_close
-func (o *Play) UpdateAdjClose(_updAdjClose int) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `adj_close` = '%d' WHERE `id` = '%d'",o._table,_updAdjClose,o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return 0,err
-    }
-    o.AdjClose = _updAdjClose
-    return o._adapter.AffectedRows(),nil
-}
-
-// UpdateDataSource an immediate DB Query to update a single column, in this
-// case data_source
-func (o *Play) UpdateDataSource(_updDataSource string) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `data_source` = '%s' WHERE `id` = '%d'",o._table,_updDataSource,o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return 0,err
-    }
-    o.DataSource = _updDataSource
-    return o._adapter.AffectedRows(),nil
-}
-
-// Portfolio is a Object Relational Mapping to
-// the database table that represents it. In this case it is
-// portfolios. The table name will be Sprintf'd to include
-// the prefix you define in your YAML configuration for the
-// Adapter.
-type Portfolio struct {
-    _table string
-    _adapter Adapter
-    _pkey string // 0 The name of the primary key in this table
-    _conds []string
-    _new bool
-
-    _select []string
-    _where []string
-    _cols []string
-    _values []string
-    _sets map[string]string
-    _limit string
-    _order string
-
-
-    Id int64
-    Name string
-    Description string
-    Value int
-	// Dirty markers for smart updates
-    IsIdDirty bool
-    IsNameDirty bool
-    IsDescriptionDirty bool
-    IsValueDirty bool
-	// Relationships
-}
-
-// NewPortfolio binds an Adapter to a new instance
-// of Portfolio and sets up the _table and primary keys
-func NewPortfolio(a Adapter) *Portfolio {
-    var o Portfolio
-    o._table = fmt.Sprintf("%sportfolios",a.DatabasePrefix())
-    o._adapter = a
-    o._pkey = "id"
-    o._new = false
-    return &o
-}
-
-
-// GetPrimaryKeyValue returns the value, usually int64 of
-// the PrimaryKey
-func (o *Portfolio) GetPrimaryKeyValue() int64 {
-    return o.Id
+// GetPvolume returns the value of 
+// Play.Pvolume
+func (o *Play) GetPvolume() int {
+    return o.Pvolume
 }
-// GetPrimaryKeyName returns the DB field name
-func (o *Portfolio) GetPrimaryKeyName() string {
-    return `id`
+// SetPvolume sets and marks as dirty the value of
+// Play.Pvolume
+func (o *Play) SetPvolume(arg int) {
+    o.Pvolume = arg
+    o.IsPvolumeDirty = true
 }
 
-// GetId returns the value of 
-// Portfolio.Id
-func (o *Portfolio) GetId() int64 {
-    return o.Id
+// GetPchange returns the value of 
+// Play.Pchange
+func (o *Play) GetPchange() int {
+    return o.Pchange
 }
-// SetId sets and marks as dirty the value of
-// Portfolio.Id
-func (o *Portfolio) SetId(arg int64) {
-    o.Id = arg
-    o.IsIdDirty = true
+// SetPchange sets and marks as dirty the value of
+// Play.Pchange
+func (o *Play) SetPchange(arg int) {
+    o.Pchange = arg
+    o.IsPchangeDirty = true
 }
 
-// GetName returns the value of 
-// Portfolio.Name
-func (o *Portfolio) GetName() string {
-    return o.Name
+// GetPchangePercent returns the value of 
+// Play.PchangePercent
+func (o *Play) GetPchangePercent() int {
+    return o.PchangePercent
 }
-// SetName sets and marks as dirty the value of
-// Portfolio.Name
-func (o *Portfolio) SetName(arg string) {
-    o.Name = arg
-    o.IsNameDirty = true
+// SetPchangePercent sets and marks as dirty the value of
+// Play.PchangePercent
+func (o *Play) SetPchangePercent(arg int) {
+    o.PchangePercent = arg
+    o.IsPchangePercentDirty = true
 }
 
-// GetDescription returns the value of 
-// Portfolio.Description
-func (o *Portfolio) GetDescription() string {
-    return o.Description
+// GetAdjClose returns the value of 
+// Play.AdjClose
+func (o *Play) GetAdjClose() int {
+    return o.AdjClose
 }
-// SetDescription sets and marks as dirty the value of
-// Portfolio.Description
-func (o *Portfolio) SetDescription(arg string) {
-    o.Description = arg
-    o.IsDescriptionDirty = true
+// SetAdjClose sets and marks as dirty the value of
+// Play.AdjClose
+func (o *Play) SetAdjClose(arg int) {
+    o.AdjClose = arg
+    o.IsAdjCloseDirty = true
 }
 
-// GetValue returns the value of 
-// Portfolio.Value
-func (o *Portfolio) GetValue() int {
-    return o.Value
+// GetDataSource returns the value of 
+// Play.DataSource
+func (o *Play) GetDataSource() string {
+    return o.DataSource
 }
-// SetValue sets and marks as dirty the value of
-// Portfolio.Value
-func (o *Portfolio) SetValue(arg int) {
-    o.Value = arg
-    o.IsValueDirty = true
+// SetDataSource sets and marks as dirty the value of
+// Play.DataSource
+func (o *Play) SetDataSource(arg string) {
+    o.DataSource = arg
+    o.IsDataSourceDirty = true
 }
 
 // Find searchs against the database table field id and will return bool,error
-// This method is a programatically generated finder for Portfolio
+// This method is a programatically generated finder for Play
 //  
 // Note that Find returns a bool of true|false if found or not, not err, in the case of
 // found == true, the instance data will be filled out!
@@ -1938,7 +3642,7 @@ func (o *Portfolio) SetValue(arg int) {
 // i.e. receiver is a pointer!
 //
 //```go
-//      m := NewPortfolio(a)
+//      m := NewPlay(a)
 //      found,err := m.Find(23)
 //      .. handle err
 //      if found == false {
@@ -1947,9 +3651,9 @@ func (o *Portfolio) SetValue(arg int) {
 //      ... do what you want with m here
 //```
 //
-func (o *Portfolio) Find(_findById int64) (bool,error) {
+func (o *Play) Find(_findById int64) (bool,error) {
 
-    var _modelSlice []*Portfolio
+    var _modelSlice []*Play
     q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "id", _findById)
     results, err := o._adapter.Query(q)
     if err != nil {
@@ -1957,7 +3661,7 @@ func (o *Portfolio) Find(_findById int64) (bool,error) {
     }
     
     for _,result := range results {
-        ro := NewPortfolio(o._adapter)
+        ro := NewPlay(o._adapter)
         err = ro.FromDBValueMap(result)
         if err != nil {
             return false,o._adapter.Oops(fmt.Sprintf(`%s`,err))
@@ -1969,33 +3673,35 @@ func (o *Portfolio) Find(_findById int64) (bool,error) {
         // there was an error!
         return false, o._adapter.Oops(`not found`)
     }
-    o.FromPortfolio(_modelSlice[0])
+    o.FromPlay(_modelSlice[0])
+    o._new = false
+    o.ResetDirty()
     return true,nil
 
 }
-// FindByName searchs against the database table field name and will return []*Portfolio,error
-// This method is a programatically generated finder for Portfolio
+// FindByPositionId searchs against the database table field position_id and will return []*Play,error
+// This method is a programatically generated finder for Play
 //
 //```go  
-//    m := NewPortfolio(a)
-//    results,err := m.FindByName(...)
+//    m := NewPlay(a)
+//    results,err := m.FindByPositionId(...)
 //    // handle err
 //    for i,r := results {
-//      // now r is an instance of Portfolio
+//      // now r is an instance of Play
 //    }
 //```  
 //
-func (o *Portfolio) FindByName(_findByName string) ([]*Portfolio,error) {
+func (o *Play) FindByPositionId(_findByPositionId int64) ([]*Play,error) {
 
-    var _modelSlice []*Portfolio
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "name", _findByName)
+    var _modelSlice []*Play
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "position_id", _findByPositionId)
     results, err := o._adapter.Query(q)
     if err != nil {
         return _modelSlice,err
     }
     
     for _,result := range results {
-        ro := NewPortfolio(o._adapter)
+        ro := NewPlay(o._adapter)
         err = ro.FromDBValueMap(result)
         if err != nil {
             return _modelSlice,err
@@ -2010,29 +3716,29 @@ func (o *Portfolio) FindByName(_findByName string) ([]*Portfolio,error) {
     return _modelSlice,nil
 
 }
-// FindByDescription searchs against the database table field description and will return []*Portfolio,error
-// This method is a programatically generated finder for Portfolio
+// FindByDay searchs against the database table field day and will return []*Play,error
+// This method is a programatically generated finder for Play
 //
 //```go  
-//    m := NewPortfolio(a)
-//    results,err := m.FindByDescription(...)
+//    m := NewPlay(a)
+//    results,err := m.FindByDay(...)
 //    // handle err
 //    for i,r := results {
-//      // now r is an instance of Portfolio
+//      // now r is an instance of Play
 //    }
 //```  
 //
-func (o *Portfolio) FindByDescription(_findByDescription string) ([]*Portfolio,error) {
+func (o *Play) FindByDay(_findByDay *DateTime) ([]*Play,error) {
 
-    var _modelSlice []*Portfolio
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "description", _findByDescription)
+    var _modelSlice []*Play
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "day", _findByDay)
     results, err := o._adapter.Query(q)
     if err != nil {
         return _modelSlice,err
     }
     
     for _,result := range results {
-        ro := NewPortfolio(o._adapter)
+        ro := NewPlay(o._adapter)
         err = ro.FromDBValueMap(result)
         if err != nil {
             return _modelSlice,err
@@ -2047,29 +3753,29 @@ func (o *Portfolio) FindByDescription(_findByDescription string) ([]*Portfolio,e
     return _modelSlice,nil
 
 }
-// FindByValue searchs against the database table field value and will return []*Portfolio,error
-// This method is a programatically generated finder for Portfolio
+// FindByOpen searchs against the database table field open and will return []*Play,error
+// This method is a programatically generated finder for Play
 //
 //```go  
-//    m := NewPortfolio(a)
-//    results,err := m.FindByValue(...)
+//    m := NewPlay(a)
+//    results,err := m.FindByOpen(...)
 //    // handle err
 //    for i,r := results {
-//      // now r is an instance of Portfolio
+//      // now r is an instance of Play
 //    }
 //```  
 //
-func (o *Portfolio) FindByValue(_findByValue int) ([]*Portfolio,error) {
+func (o *Play) FindByOpen(_findByOpen int) ([]*Play,error) {
 
-    var _modelSlice []*Portfolio
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "value", _findByValue)
+    var _modelSlice []*Play
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "open", _findByOpen)
     results, err := o._adapter.Query(q)
     if err != nil {
         return _modelSlice,err
     }
     
     for _,result := range results {
-        ro := NewPortfolio(o._adapter)
+        ro := NewPlay(o._adapter)
         err = ro.FromDBValueMap(result)
         if err != nil {
             return _modelSlice,err
@@ -2084,288 +3790,2557 @@ func (o *Portfolio) FindByValue(_findByValue int) ([]*Portfolio,error) {
     return _modelSlice,nil
 
 }
+// FindByHigh searchs against the database table field high and will return []*Play,error
+// This method is a programatically generated finder for Play
+//
+//```go  
+//    m := NewPlay(a)
+//    results,err := m.FindByHigh(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Play
+//    }
+//```  
+//
+func (o *Play) FindByHigh(_findByHigh int) ([]*Play,error) {
 
-// FromDBValueMap Converts a DBValueMap returned from Adapter.Query to a Portfolio
-func (o *Portfolio) FromDBValueMap(m map[string]DBValue) error {
-	_Id,err := m["id"].AsInt64()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.Id = _Id
-	_Name,err := m["name"].AsString()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.Name = _Name
-	_Description,err := m["description"].AsString()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.Description = _Description
-	_Value,err := m["value"].AsInt()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.Value = _Value
-
- 	return nil
-}
-// FromPortfolio A kind of Clone function for Portfolio
-func (o *Portfolio) FromPortfolio(m *Portfolio) {
-	o.Id = m.Id
-	o.Name = m.Name
-	o.Description = m.Description
-	o.Value = m.Value
+    var _modelSlice []*Play
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "high", _findByHigh)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPlay(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
 
 }
-// Reload A function to forcibly reload Portfolio
-func (o *Portfolio) Reload() error {
-    _,err := o.Find(o.GetPrimaryKeyValue())
-    return err
-}
+// FindByLow searchs against the database table field low and will return []*Play,error
+// This method is a programatically generated finder for Play
+//
+//```go  
+//    m := NewPlay(a)
+//    results,err := m.FindByLow(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Play
+//    }
+//```  
+//
+func (o *Play) FindByLow(_findByLow int) ([]*Play,error) {
 
-// Save is a dynamic saver 'inherited' by all models
-func (o *Portfolio) Save() error {
-    if o._new == true {
-        return o.Create()
+    var _modelSlice []*Play
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "low", _findByLow)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
     }
-    var sets []string
     
-    if o.IsNameDirty == true {
-        sets = append(sets,fmt.Sprintf(`name = '%s'`,o._adapter.SafeString(o.Name)))
+    for _,result := range results {
+        ro := NewPlay(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
     }
 
-    if o.IsDescriptionDirty == true {
-        sets = append(sets,fmt.Sprintf(`description = '%s'`,o._adapter.SafeString(o.Description)))
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
     }
+    return _modelSlice,nil
 
-    if o.IsValueDirty == true {
-        sets = append(sets,fmt.Sprintf(`value = '%d'`,o.Value))
-    }
+}
+// FindByPvolume searchs against the database table field pvolume and will return []*Play,error
+// This method is a programatically generated finder for Play
+//
+//```go  
+//    m := NewPlay(a)
+//    results,err := m.FindByPvolume(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Play
+//    }
+//```  
+//
+func (o *Play) FindByPvolume(_findByPvolume int) ([]*Play,error) {
 
-    frmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s = '%d'",o._table,strings.Join(sets,`,`),o._pkey, o.Id)
-    err := o._adapter.Execute(frmt)
+    var _modelSlice []*Play
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "pvolume", _findByPvolume)
+    results, err := o._adapter.Query(q)
     if err != nil {
-        return err
+        return _modelSlice,err
     }
-    return nil
-}
-// Update is a dynamic updater, it considers whether or not
-// a field is 'dirty' and needs to be updated. Will only work
-// if you use the Getters and Setters
-func (o *Portfolio) Update() error {
-    var sets []string
     
-    if o.IsNameDirty == true {
-        sets = append(sets,fmt.Sprintf(`name = '%s'`,o._adapter.SafeString(o.Name)))
+    for _,result := range results {
+        ro := NewPlay(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
     }
 
-    if o.IsDescriptionDirty == true {
-        sets = append(sets,fmt.Sprintf(`description = '%s'`,o._adapter.SafeString(o.Description)))
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
     }
+    return _modelSlice,nil
 
-    if o.IsValueDirty == true {
-        sets = append(sets,fmt.Sprintf(`value = '%d'`,o.Value))
-    }
+}
+// FindByPchange searchs against the database table field pchange and will return []*Play,error
+// This method is a programatically generated finder for Play
+//
+//```go  
+//    m := NewPlay(a)
+//    results,err := m.FindByPchange(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Play
+//    }
+//```  
+//
+func (o *Play) FindByPchange(_findByPchange int) ([]*Play,error) {
 
-    frmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s = '%d'",o._table,strings.Join(sets,`,`),o._pkey, o.Id)
-    err := o._adapter.Execute(frmt)
+    var _modelSlice []*Play
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "pchange", _findByPchange)
+    results, err := o._adapter.Query(q)
     if err != nil {
-        return err
+        return _modelSlice,err
     }
-    return nil
-}
-// Create inserts the model. Calling Save will call this function
-// automatically for new models
-func (o *Portfolio) Create() error {
-    frmt := fmt.Sprintf("INSERT INTO %s (`name`, `description`, `value`) VALUES ('%s', '%s', '%d')",o._table,o.Name, o.Description, o.Value)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return o._adapter.Oops(fmt.Sprintf(`%s led to %s`,frmt,err))
+    
+    for _,result := range results {
+        ro := NewPlay(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
     }
-    o.Id = o._adapter.LastInsertedId()
-    o._new = false
-    return nil
-}
-
 
-// UpdateName an immediate DB Query to update a single column, in this
-// case name
-func (o *Portfolio) UpdateName(_updName string) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `name` = '%s' WHERE `id` = '%d'",o._table,_updName,o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return 0,err
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
     }
-    o.Name = _updName
-    return o._adapter.AffectedRows(),nil
+    return _modelSlice,nil
+
 }
+// FindByPchangePercent searchs against the database table field pchange_percent and will return []*Play,error
+// This method is a programatically generated finder for Play
+//
+//```go  
+//    m := NewPlay(a)
+//    results,err := m.FindByPchangePercent(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Play
+//    }
+//```  
+//
+func (o *Play) FindByPchangePercent(_findByPchangePercent int) ([]*Play,error) {
 
-// UpdateDescription an immediate DB Query to update a single column, in this
-// case description
-func (o *Portfolio) UpdateDescription(_updDescription string) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `description` = '%s' WHERE `id` = '%d'",o._table,_updDescription,o.Id)
-    err := o._adapter.Execute(frmt)
+    var _modelSlice []*Play
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "pchange_percent", _findByPchangePercent)
+    results, err := o._adapter.Query(q)
     if err != nil {
-        return 0,err
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPlay(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
     }
-    o.Description = _updDescription
-    return o._adapter.AffectedRows(),nil
+    return _modelSlice,nil
+
 }
+// FindByAdjClose searchs against the database table field adj_close and will return []*Play,error
+// This method is a programatically generated finder for Play
+//
+//```go  
+//    m := NewPlay(a)
+//    results,err := m.FindByAdjClose(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Play
+//    }
+//```  
+//
+func (o *Play) FindByAdjClose(_findByAdjClose int) ([]*Play,error) {
 
-// UpdateValue an immediate DB Query to update a single column, in this
-// case value
-func (o *Portfolio) UpdateValue(_updValue int) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `value` = '%d' WHERE `id` = '%d'",o._table,_updValue,o.Id)
-    err := o._adapter.Execute(frmt)
+    var _modelSlice []*Play
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "adj_close", _findByAdjClose)
+    results, err := o._adapter.Query(q)
     if err != nil {
-        return 0,err
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPlay(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
     }
-    o.Value = _updValue
-    return o._adapter.AffectedRows(),nil
-}
 
-// Position is a Object Relational Mapping to
-// the database table that represents it. In this case it is
-// positions. The table name will be Sprintf'd to include
-// the prefix you define in your YAML configuration for the
-// Adapter.
-type Position struct {
-    _table string
-    _adapter Adapter
-    _pkey string // 0 The name of the primary key in this table
-    _conds []string
-    _new bool
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
 
-    _select []string
-    _where []string
-    _cols []string
-    _values []string
-    _sets map[string]string
-    _limit string
-    _order string
+}
+// FindByDataSource searchs against the database table field data_source and will return []*Play,error
+// This method is a programatically generated finder for Play
+//
+//```go  
+//    m := NewPlay(a)
+//    results,err := m.FindByDataSource(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Play
+//    }
+//```  
+//
+func (o *Play) FindByDataSource(_findByDataSource string) ([]*Play,error) {
 
+    var _modelSlice []*Play
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "data_source", _findByDataSource)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPlay(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
 
-    Id int64
-    PortfolioId int64
-    StartedAt *DateTime
-    ClosedAt *DateTime
-    Ptype string
-    Buy int
-    Sell int
-    StopLoss int
-    Quantity int
-	// Dirty markers for smart updates
-    IsIdDirty bool
-    IsPortfolioIdDirty bool
-    IsStartedAtDirty bool
-    IsClosedAtDirty bool
-    IsPtypeDirty bool
-    IsBuyDirty bool
-    IsSellDirty bool
-    IsStopLossDirty bool
-    IsQuantityDirty bool
-	// Relationships
-}
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
 
-// NewPosition binds an Adapter to a new instance
-// of Position and sets up the _table and primary keys
-func NewPosition(a Adapter) *Position {
-    var o Position
-    o._table = fmt.Sprintf("%spositions",a.DatabasePrefix())
-    o._adapter = a
-    o._pkey = "id"
-    o._new = false
-    return &o
 }
 
-
-// GetPrimaryKeyValue returns the value, usually int64 of
-// the PrimaryKey
-func (o *Position) GetPrimaryKeyValue() int64 {
-    return o.Id
-}
-// GetPrimaryKeyName returns the DB field name
-func (o *Position) GetPrimaryKeyName() string {
-    return `id`
+// FromDBValueMap Converts a DBValueMap returned from Adapter.Query to a Play
+func (o *Play) FromDBValueMap(m map[string]DBValue) error {
+    return o.FromRow(DBValueMap(m))
 }
+// FromRow Converts a Row, e.g. from Adapter.QueryEach, to a Play
+func (o *Play) FromRow(r Row) error {
+	_Id,err := r.Get("id").AsInt64()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Id = _Id
+	_PositionId,err := r.Get("position_id").AsInt64()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.PositionId = _PositionId
+	_Day,err := r.Get("day").AsDateTime()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Day = _Day
+	_Open,err := r.Get("open").AsInt()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Open = _Open
+	_High,err := r.Get("high").AsInt()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.High = _High
+	_Low,err := r.Get("low").AsInt()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Low = _Low
+	_Pvolume,err := r.Get("pvolume").AsInt()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Pvolume = _Pvolume
+	_Pchange,err := r.Get("pchange").AsInt()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Pchange = _Pchange
+	_PchangePercent,err := r.Get("pchange_percent").AsInt()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.PchangePercent = _PchangePercent
+	_AdjClose,err := r.Get("adj_close").AsInt()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.AdjClose = _AdjClose
+	_DataSource,err := r.Get("data_source").AsString()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.DataSource = _DataSource
 
-// GetId returns the value of 
-// Position.Id
-func (o *Position) GetId() int64 {
-    return o.Id
-}
-// SetId sets and marks as dirty the value of
-// Position.Id
-func (o *Position) SetId(arg int64) {
-    o.Id = arg
-    o.IsIdDirty = true
+    o._new = false
+    o.ResetDirty()
+ 	return nil
 }
+// FromPlay A kind of Clone function for Play
+func (o *Play) FromPlay(m *Play) {
+	o.Id = m.Id
+	o.PositionId = m.PositionId
+	o.Day = m.Day
+	o.Open = m.Open
+	o.High = m.High
+	o.Low = m.Low
+	o.Pvolume = m.Pvolume
+	o.Pchange = m.Pchange
+	o.PchangePercent = m.PchangePercent
+	o.AdjClose = m.AdjClose
+	o.DataSource = m.DataSource
 
-// GetPortfolioId returns the value of 
-// Position.PortfolioId
-func (o *Position) GetPortfolioId() int64 {
-    return o.PortfolioId
-}
-// SetPortfolioId sets and marks as dirty the value of
-// Position.PortfolioId
-func (o *Position) SetPortfolioId(arg int64) {
-    o.PortfolioId = arg
-    o.IsPortfolioIdDirty = true
 }
-
-// GetStartedAt returns the value of 
-// Position.StartedAt
-func (o *Position) GetStartedAt() *DateTime {
-    return o.StartedAt
+// Reload A function to forcibly reload Play
+func (o *Play) Reload() error {
+    _,err := o.Find(o.GetPrimaryKeyValue())
+    return err
 }
-// SetStartedAt sets and marks as dirty the value of
-// Position.StartedAt
-func (o *Position) SetStartedAt(arg *DateTime) {
-    o.StartedAt = arg
-    o.IsStartedAtDirty = true
+// EachPlay runs f for every Play q selects, reading the rows
+// one at a time with Adapter.QueryEach. The Play given to f is
+// reused for the next row, copy it with FromPlay to keep it.
+func EachPlay(a Adapter, q string, f func(*Play) error) error {
+    o := NewPlay(a)
+    return a.QueryEach(q,func(r Row) error {
+        err := o.FromRow(r)
+        if err != nil {
+            return err
+        }
+        return f(o)
+    })
 }
 
-// GetClosedAt returns the value of 
-// Position.ClosedAt
-func (o *Position) GetClosedAt() *DateTime {
-    return o.ClosedAt
+// transaction runs f with the model bound to a transaction of its
+// Adapter, see Adapter.Transaction
+func (o *Play) transaction(f func() error) error {
+    _adapter := o._adapter
+    defer func() { o._adapter = _adapter }()
+    return _adapter.Transaction(func(a Adapter) error {
+        o._adapter = a
+        return f()
+    })
 }
-// SetClosedAt sets and marks as dirty the value of
-// Position.ClosedAt
-func (o *Position) SetClosedAt(arg *DateTime) {
-    o.ClosedAt = arg
-    o.IsClosedAtDirty = true
+// Save is a dynamic saver 'inherited' by all models
+func (o *Play) Save() error {
+    if o._new == true {
+        return o.Create()
+    }
+    return o.Update()
 }
+// Update is a dynamic updater, it considers whether or not
+// a field is 'dirty' and needs to be updated. Will only work
+// if you use the Getters and Setters, a model with nothing dirty
+// is not written at all
+func (o *Play) Update() error {
+    if o.IsDirty() == false {
+        return nil
+    }
+    err := o.transaction(func() error {
+        err := runCallbacks(o._adapter,`Play`,BeforeSave,o)
+        if err != nil {
+            return err
+        }
+        err = o.Validate()
+        if err != nil {
+            return err
+        }
+        var sets []string
+        changes := make(map[string]string)
+        
+        if o.IsPositionIdDirty == true {
+            sets = append(sets,fmt.Sprintf(`position_id = '%d'`,o.PositionId))
+            changes[`position_id`] = auditValue(o.PositionId)
+        }
 
-// GetPtype returns the value of 
-// Position.Ptype
-func (o *Position) GetPtype() string {
-    return o.Ptype
-}
-// SetPtype sets and marks as dirty the value of
-// Position.Ptype
-func (o *Position) SetPtype(arg string) {
-    o.Ptype = arg
-    o.IsPtypeDirty = true
-}
+        if o.IsDayDirty == true {
+            sets = append(sets,fmt.Sprintf(`day = %s`,dateTimeSQL(o.Day)))
+            changes[`day`] = auditValue(o.Day)
+        }
 
-// GetBuy returns the value of 
-// Position.Buy
-func (o *Position) GetBuy() int {
-    return o.Buy
+        if o.IsOpenDirty == true {
+            sets = append(sets,fmt.Sprintf(`open = '%d'`,o.Open))
+            changes[`open`] = auditValue(o.Open)
+        }
+
+        if o.IsHighDirty == true {
+            sets = append(sets,fmt.Sprintf(`high = '%d'`,o.High))
+            changes[`high`] = auditValue(o.High)
+        }
+
+        if o.IsLowDirty == true {
+            sets = append(sets,fmt.Sprintf(`low = '%d'`,o.Low))
+            changes[`low`] = auditValue(o.Low)
+        }
+
+        if o.IsPvolumeDirty == true {
+            sets = append(sets,fmt.Sprintf(`pvolume = '%d'`,o.Pvolume))
+            changes[`pvolume`] = auditValue(o.Pvolume)
+        }
+
+        if o.IsPchangeDirty == true {
+            sets = append(sets,fmt.Sprintf(`pchange = '%d'`,o.Pchange))
+            changes[`pchange`] = auditValue(o.Pchange)
+        }
+
+        if o.IsPchangePercentDirty == true {
+            sets = append(sets,fmt.Sprintf(`pchange_percent = '%d'`,o.PchangePercent))
+            changes[`pchange_percent`] = auditValue(o.PchangePercent)
+        }
+
+        if o.IsAdjCloseDirty == true {
+            sets = append(sets,fmt.Sprintf(`adj_close = '%d'`,o.AdjClose))
+            changes[`adj_close`] = auditValue(o.AdjClose)
+        }
+
+        if o.IsDataSourceDirty == true {
+            sets = append(sets,fmt.Sprintf(`data_source = '%s'`,o._adapter.SafeString(o.DataSource)))
+            changes[`data_source`] = auditValue(o.DataSource)
+        }
+
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,changes)
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s = '%d'",o._table,strings.Join(sets,`,`),o._pkey, o.Id)
+        _, err = o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        return runCallbacks(o._adapter,`Play`,AfterSave,o)
+    })
+    if err != nil {
+        return err
+    }
+    o.ResetDirty()
+    return nil
 }
-// SetBuy sets and marks as dirty the value of
-// Position.Buy
-func (o *Position) SetBuy(arg int) {
-    o.Buy = arg
-    o.IsBuyDirty = true
+// Create inserts the model. Calling Save will call this function
+// automatically for new models
+func (o *Play) Create() error {
+    err := o.transaction(func() error {
+        err := runCallbacks(o._adapter,`Play`,BeforeSave,o)
+        if err != nil {
+            return err
+        }
+        err = runCallbacks(o._adapter,`Play`,BeforeCreate,o)
+        if err != nil {
+            return err
+        }
+        err = o.Validate()
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("INSERT INTO %s (`position_id`, `day`, `open`, `high`, `low`, `pvolume`, `pchange`, `pchange_percent`, `adj_close`, `data_source`) VALUES ('%d', %s, '%d', '%d', '%d', '%d', '%d', '%d', '%d', '%s')",o._table,o.PositionId, dateTimeSQL(o.Day), o.Open, o.High, o.Low, o.Pvolume, o.Pchange, o.PchangePercent, o.AdjClose, o.DataSource)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return o._adapter.Oops(fmt.Sprintf(`%s led to %s`,frmt,err))
+        }
+        o.Id = res.LastInsertId
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditCreate,map[string]string{
+            `position_id`: auditValue(o.PositionId),
+            `day`: auditValue(o.Day),
+            `open`: auditValue(o.Open),
+            `high`: auditValue(o.High),
+            `low`: auditValue(o.Low),
+            `pvolume`: auditValue(o.Pvolume),
+            `pchange`: auditValue(o.Pchange),
+            `pchange_percent`: auditValue(o.PchangePercent),
+            `adj_close`: auditValue(o.AdjClose),
+            `data_source`: auditValue(o.DataSource),
+        })
+        if err != nil {
+            return err
+        }
+        err = runCallbacks(o._adapter,`Play`,AfterCreate,o)
+        if err != nil {
+            return err
+        }
+        return runCallbacks(o._adapter,`Play`,AfterSave,o)
+    })
+    if err != nil {
+        return err
+    }
+    o._new = false
+    o.ResetDirty()
+    return nil
+}
+// Delete removes the model from the database
+func (o *Play) Delete() error {
+    return o.transaction(func() error {
+        err := runCallbacks(o._adapter,`Play`,BeforeDelete,o)
+        if err != nil {
+            return err
+        }
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditDelete,nil)
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("DELETE FROM %s WHERE %s = '%d'",o._table,o._pkey, o.Id)
+        _, err = o._adapter.Execute(frmt)
+        return err
+    })
 }
 
-// GetSell returns the value of 
-// Position.Sell
-func (o *Position) GetSell() int {
-    return o.Sell
+// Validate checks the model against the constraints of the
+// plays table and then runs the validators registered for
+// Play. Create and Save call it before writing.
+func (o *Play) Validate() error {
+    errs := make(ValidationErrors)
+    if o.PositionId == 0 {
+        errs.Add(`position_id`,`is required`)
+    }
+    if len(o.DataSource) > 255 {
+        errs.Add(`data_source`,`is longer than 255`)
+    }
+    return runValidators(o._adapter,`Play`,o,errs)
+}
+
+
+// UpdatePositionId an immediate DB Query to update a single column, in this
+// case position_id. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Play) UpdatePositionId(_updPositionId int64) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.PositionId, o.IsPositionIdDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`position_id`: auditValue(_updPositionId)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `position_id` = '%d' WHERE `id` = '%d'",o._table,_updPositionId,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.PositionId = _updPositionId
+        o.IsPositionIdDirty = true
+        return runCallbacks(o._adapter,`Play`,AfterSave,o)
+    })
+    if err != nil {
+        o.PositionId, o.IsPositionIdDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsPositionIdDirty = false
+    if o._orig != nil {
+        o._orig.PositionId = _updPositionId
+    }
+    return affected,nil
+}
+
+// UpdateDay an immediate DB Query to update a single column, in this
+// case day. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Play) UpdateDay(_updDay *DateTime) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Day, o.IsDayDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`day`: auditValue(_updDay)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `day` = %s WHERE `id` = '%d'",o._table,dateTimeSQL(_updDay),o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.Day = _updDay
+        o.IsDayDirty = true
+        return runCallbacks(o._adapter,`Play`,AfterSave,o)
+    })
+    if err != nil {
+        o.Day, o.IsDayDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsDayDirty = false
+    if o._orig != nil {
+        o._orig.Day = _updDay
+    }
+    return affected,nil
+}
+
+// UpdateOpen an immediate DB Query to update a single column, in this
+// case open. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Play) UpdateOpen(_updOpen int) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Open, o.IsOpenDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`open`: auditValue(_updOpen)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `open` = '%d' WHERE `id` = '%d'",o._table,_updOpen,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.Open = _updOpen
+        o.IsOpenDirty = true
+        return runCallbacks(o._adapter,`Play`,AfterSave,o)
+    })
+    if err != nil {
+        o.Open, o.IsOpenDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsOpenDirty = false
+    if o._orig != nil {
+        o._orig.Open = _updOpen
+    }
+    return affected,nil
+}
+
+// UpdateHigh an immediate DB Query to update a single column, in this
+// case high. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Play) UpdateHigh(_updHigh int) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.High, o.IsHighDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`high`: auditValue(_updHigh)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `high` = '%d' WHERE `id` = '%d'",o._table,_updHigh,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.High = _updHigh
+        o.IsHighDirty = true
+        return runCallbacks(o._adapter,`Play`,AfterSave,o)
+    })
+    if err != nil {
+        o.High, o.IsHighDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsHighDirty = false
+    if o._orig != nil {
+        o._orig.High = _updHigh
+    }
+    return affected,nil
+}
+
+// UpdateLow an immediate DB Query to update a single column, in this
+// case low. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Play) UpdateLow(_updLow int) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Low, o.IsLowDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`low`: auditValue(_updLow)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `low` = '%d' WHERE `id` = '%d'",o._table,_updLow,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.Low = _updLow
+        o.IsLowDirty = true
+        return runCallbacks(o._adapter,`Play`,AfterSave,o)
+    })
+    if err != nil {
+        o.Low, o.IsLowDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsLowDirty = false
+    if o._orig != nil {
+        o._orig.Low = _updLow
+    }
+    return affected,nil
+}
+
+// UpdatePvolume an immediate DB Query to update a single column, in this
+// case pvolume. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Play) UpdatePvolume(_updPvolume int) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Pvolume, o.IsPvolumeDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`pvolume`: auditValue(_updPvolume)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `pvolume` = '%d' WHERE `id` = '%d'",o._table,_updPvolume,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.Pvolume = _updPvolume
+        o.IsPvolumeDirty = true
+        return runCallbacks(o._adapter,`Play`,AfterSave,o)
+    })
+    if err != nil {
+        o.Pvolume, o.IsPvolumeDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsPvolumeDirty = false
+    if o._orig != nil {
+        o._orig.Pvolume = _updPvolume
+    }
+    return affected,nil
+}
+
+// UpdatePchange an immediate DB Query to update a single column, in this
+// case pchange. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Play) UpdatePchange(_updPchange int) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Pchange, o.IsPchangeDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`pchange`: auditValue(_updPchange)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `pchange` = '%d' WHERE `id` = '%d'",o._table,_updPchange,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.Pchange = _updPchange
+        o.IsPchangeDirty = true
+        return runCallbacks(o._adapter,`Play`,AfterSave,o)
+    })
+    if err != nil {
+        o.Pchange, o.IsPchangeDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsPchangeDirty = false
+    if o._orig != nil {
+        o._orig.Pchange = _updPchange
+    }
+    return affected,nil
+}
+
+// UpdatePchangePercent an immediate DB Query to update a single column, in this
+// case pchange_percent. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Play) UpdatePchangePercent(_updPchangePercent int) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.PchangePercent, o.IsPchangePercentDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`pchange_percent`: auditValue(_updPchangePercent)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `pchange_percent` = '%d' WHERE `id` = '%d'",o._table,_updPchangePercent,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.PchangePercent = _updPchangePercent
+        o.IsPchangePercentDirty = true
+        return runCallbacks(o._adapter,`Play`,AfterSave,o)
+    })
+    if err != nil {
+        o.PchangePercent, o.IsPchangePercentDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsPchangePercentDirty = false
+    if o._orig != nil {
+        o._orig.PchangePercent = _updPchangePercent
+    }
+    return affected,nil
+}
+
+// UpdateAdjClose an immediate DB Query to update a single column, in this
+// case adj_close. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Play) UpdateAdjClose(_updAdjClose int) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.AdjClose, o.IsAdjCloseDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`adj_close`: auditValue(_updAdjClose)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `adj_close` = '%d' WHERE `id` = '%d'",o._table,_updAdjClose,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.AdjClose = _updAdjClose
+        o.IsAdjCloseDirty = true
+        return runCallbacks(o._adapter,`Play`,AfterSave,o)
+    })
+    if err != nil {
+        o.AdjClose, o.IsAdjCloseDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsAdjCloseDirty = false
+    if o._orig != nil {
+        o._orig.AdjClose = _updAdjClose
+    }
+    return affected,nil
+}
+
+// UpdateDataSource an immediate DB Query to update a single column, in this
+// case data_source. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Play) UpdateDataSource(_updDataSource string) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.DataSource, o.IsDataSourceDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`data_source`: auditValue(_updDataSource)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `data_source` = '%s' WHERE `id` = '%d'",o._table,_updDataSource,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.DataSource = _updDataSource
+        o.IsDataSourceDirty = true
+        return runCallbacks(o._adapter,`Play`,AfterSave,o)
+    })
+    if err != nil {
+        o.DataSource, o.IsDataSourceDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsDataSourceDirty = false
+    if o._orig != nil {
+        o._orig.DataSource = _updDataSource
+    }
+    return affected,nil
+}
+
+// IsDirty returns true when a field was set through its setter since
+// the model was loaded or last saved
+func (o *Play) IsDirty() bool {
+    return len(o.DirtyFields()) > 0
+}
+// DirtyFields returns the columns set through their setters since the
+// model was loaded or last saved, in the order of the table
+func (o *Play) DirtyFields() []string {
+    var fields []string
+    if o.IsPositionIdDirty == true {
+        fields = append(fields,`position_id`)
+    }
+    if o.IsDayDirty == true {
+        fields = append(fields,`day`)
+    }
+    if o.IsOpenDirty == true {
+        fields = append(fields,`open`)
+    }
+    if o.IsHighDirty == true {
+        fields = append(fields,`high`)
+    }
+    if o.IsLowDirty == true {
+        fields = append(fields,`low`)
+    }
+    if o.IsPvolumeDirty == true {
+        fields = append(fields,`pvolume`)
+    }
+    if o.IsPchangeDirty == true {
+        fields = append(fields,`pchange`)
+    }
+    if o.IsPchangePercentDirty == true {
+        fields = append(fields,`pchange_percent`)
+    }
+    if o.IsAdjCloseDirty == true {
+        fields = append(fields,`adj_close`)
+    }
+    if o.IsDataSourceDirty == true {
+        fields = append(fields,`data_source`)
+    }
+    return fields
+}
+// ResetDirty clears the dirty markers and takes a snapshot of the
+// current values as the originals, it is called after loading and
+// saving. The snapshot is reused so loading rows into the same model
+// does not allocate a new one each time.
+func (o *Play) ResetDirty() {
+    o.IsPositionIdDirty = false
+    o.IsDayDirty = false
+    o.IsOpenDirty = false
+    o.IsHighDirty = false
+    o.IsLowDirty = false
+    o.IsPvolumeDirty = false
+    o.IsPchangeDirty = false
+    o.IsPchangePercentDirty = false
+    o.IsAdjCloseDirty = false
+    o.IsDataSourceDirty = false
+    if o._orig == nil {
+        o._orig = &Play{}
+    }
+    orig := o._orig
+    *orig = *o
+    orig._orig = nil
+    if o.Day != nil {
+        _Day := *o.Day
+        orig.Day = &_Day
+    }
+}
+// Original returns a column as it was when the model was loaded or last
+// saved, formatted like the audit log, and false for new models
+func (o *Play) Original(column string) (string,bool) {
+    if o._orig == nil {
+        return ``,false
+    }
+    switch column {
+    case `position_id`:
+        return auditValue(o._orig.PositionId),true
+    case `day`:
+        return auditValue(o._orig.Day),true
+    case `open`:
+        return auditValue(o._orig.Open),true
+    case `high`:
+        return auditValue(o._orig.High),true
+    case `low`:
+        return auditValue(o._orig.Low),true
+    case `pvolume`:
+        return auditValue(o._orig.Pvolume),true
+    case `pchange`:
+        return auditValue(o._orig.Pchange),true
+    case `pchange_percent`:
+        return auditValue(o._orig.PchangePercent),true
+    case `adj_close`:
+        return auditValue(o._orig.AdjClose),true
+    case `data_source`:
+        return auditValue(o._orig.DataSource),true
+    }
+    return ``,false
+}
+
+// PortfolioSnapshot is a Object Relational Mapping to
+// the database table that represents it. In this case it is
+// portfolio_snapshots. The table name will be Sprintf'd to include
+// the prefix you define in your YAML configuration for the
+// Adapter.
+type PortfolioSnapshot struct {
+    _table string
+    _adapter Adapter
+    _pkey string // 0 The name of the primary key in this table
+    _conds []string
+    _new bool
+    _orig *PortfolioSnapshot
+
+    _select []string
+    _where []string
+    _cols []string
+    _values []string
+    _sets map[string]string
+    _limit string
+    _order string
+
+
+    Id int64
+    PortfolioId int64
+    Day *DateTime
+    Cash int
+    MarketValue int
+    OpenPositions int
+	// Dirty markers for smart updates
+    IsIdDirty bool
+    IsPortfolioIdDirty bool
+    IsDayDirty bool
+    IsCashDirty bool
+    IsMarketValueDirty bool
+    IsOpenPositionsDirty bool
+	// Relationships
+}
+
+// NewPortfolioSnapshot binds an Adapter to a new instance
+// of PortfolioSnapshot and sets up the _table and primary keys
+func NewPortfolioSnapshot(a Adapter) *PortfolioSnapshot {
+    var o PortfolioSnapshot
+    o._table = fmt.Sprintf("%sportfolio_snapshots",a.DatabasePrefix())
+    o._adapter = a
+    o._pkey = "id"
+    o._new = true
+    return &o
+}
+
+
+// GetPrimaryKeyValue returns the value, usually int64 of
+// the PrimaryKey
+func (o *PortfolioSnapshot) GetPrimaryKeyValue() int64 {
+    return o.Id
+}
+// GetPrimaryKeyName returns the DB field name
+func (o *PortfolioSnapshot) GetPrimaryKeyName() string {
+    return `id`
+}
+
+// GetId returns the value of 
+// PortfolioSnapshot.Id
+func (o *PortfolioSnapshot) GetId() int64 {
+    return o.Id
+}
+// SetId sets and marks as dirty the value of
+// PortfolioSnapshot.Id
+func (o *PortfolioSnapshot) SetId(arg int64) {
+    o.Id = arg
+    o.IsIdDirty = true
+}
+
+// GetPortfolioId returns the value of 
+// PortfolioSnapshot.PortfolioId
+func (o *PortfolioSnapshot) GetPortfolioId() int64 {
+    return o.PortfolioId
+}
+// SetPortfolioId sets and marks as dirty the value of
+// PortfolioSnapshot.PortfolioId
+func (o *PortfolioSnapshot) SetPortfolioId(arg int64) {
+    o.PortfolioId = arg
+    o.IsPortfolioIdDirty = true
+}
+
+// GetDay returns the value of 
+// PortfolioSnapshot.Day
+func (o *PortfolioSnapshot) GetDay() *DateTime {
+    return o.Day
+}
+// SetDay sets and marks as dirty the value of
+// PortfolioSnapshot.Day
+func (o *PortfolioSnapshot) SetDay(arg *DateTime) {
+    o.Day = arg
+    o.IsDayDirty = true
+}
+
+// GetCash returns the value of 
+// PortfolioSnapshot.Cash
+func (o *PortfolioSnapshot) GetCash() int {
+    return o.Cash
+}
+// SetCash sets and marks as dirty the value of
+// PortfolioSnapshot.Cash
+func (o *PortfolioSnapshot) SetCash(arg int) {
+    o.Cash = arg
+    o.IsCashDirty = true
+}
+
+// GetMarketValue returns the value of 
+// PortfolioSnapshot.MarketValue
+func (o *PortfolioSnapshot) GetMarketValue() int {
+    return o.MarketValue
+}
+// SetMarketValue sets and marks as dirty the value of
+// PortfolioSnapshot.MarketValue
+func (o *PortfolioSnapshot) SetMarketValue(arg int) {
+    o.MarketValue = arg
+    o.IsMarketValueDirty = true
+}
+
+// GetOpenPositions returns the value of 
+// PortfolioSnapshot.OpenPositions
+func (o *PortfolioSnapshot) GetOpenPositions() int {
+    return o.OpenPositions
+}
+// SetOpenPositions sets and marks as dirty the value of
+// PortfolioSnapshot.OpenPositions
+func (o *PortfolioSnapshot) SetOpenPositions(arg int) {
+    o.OpenPositions = arg
+    o.IsOpenPositionsDirty = true
+}
+
+// Find searchs against the database table field id and will return bool,error
+// This method is a programatically generated finder for PortfolioSnapshot
+//  
+// Note that Find returns a bool of true|false if found or not, not err, in the case of
+// found == true, the instance data will be filled out!
+//
+// A call to find ALWAYS overwrites the model you call Find on
+// i.e. receiver is a pointer!
+//
+//```go
+//      m := NewPortfolioSnapshot(a)
+//      found,err := m.Find(23)
+//      .. handle err
+//      if found == false {
+//          // handle found
+//      }
+//      ... do what you want with m here
+//```
+//
+func (o *PortfolioSnapshot) Find(_findById int64) (bool,error) {
+
+    var _modelSlice []*PortfolioSnapshot
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "id", _findById)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return false,o._adapter.Oops(fmt.Sprintf(`%s`,err))
+    }
+    
+    for _,result := range results {
+        ro := NewPortfolioSnapshot(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return false,o._adapter.Oops(fmt.Sprintf(`%s`,err))
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return false, o._adapter.Oops(`not found`)
+    }
+    o.FromPortfolioSnapshot(_modelSlice[0])
+    o._new = false
+    o.ResetDirty()
+    return true,nil
+
+}
+// FindByPortfolioId searchs against the database table field portfolio_id and will return []*PortfolioSnapshot,error
+// This method is a programatically generated finder for PortfolioSnapshot
+//
+//```go  
+//    m := NewPortfolioSnapshot(a)
+//    results,err := m.FindByPortfolioId(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of PortfolioSnapshot
+//    }
+//```  
+//
+func (o *PortfolioSnapshot) FindByPortfolioId(_findByPortfolioId int64) ([]*PortfolioSnapshot,error) {
+
+    var _modelSlice []*PortfolioSnapshot
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "portfolio_id", _findByPortfolioId)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPortfolioSnapshot(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+// FindByDay searchs against the database table field day and will return []*PortfolioSnapshot,error
+// This method is a programatically generated finder for PortfolioSnapshot
+//
+//```go  
+//    m := NewPortfolioSnapshot(a)
+//    results,err := m.FindByDay(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of PortfolioSnapshot
+//    }
+//```  
+//
+func (o *PortfolioSnapshot) FindByDay(_findByDay *DateTime) ([]*PortfolioSnapshot,error) {
+
+    var _modelSlice []*PortfolioSnapshot
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "day", _findByDay)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPortfolioSnapshot(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+// FindByCash searchs against the database table field cash and will return []*PortfolioSnapshot,error
+// This method is a programatically generated finder for PortfolioSnapshot
+//
+//```go  
+//    m := NewPortfolioSnapshot(a)
+//    results,err := m.FindByCash(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of PortfolioSnapshot
+//    }
+//```  
+//
+func (o *PortfolioSnapshot) FindByCash(_findByCash int) ([]*PortfolioSnapshot,error) {
+
+    var _modelSlice []*PortfolioSnapshot
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "cash", _findByCash)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPortfolioSnapshot(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+// FindByMarketValue searchs against the database table field market_value and will return []*PortfolioSnapshot,error
+// This method is a programatically generated finder for PortfolioSnapshot
+//
+//```go  
+//    m := NewPortfolioSnapshot(a)
+//    results,err := m.FindByMarketValue(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of PortfolioSnapshot
+//    }
+//```  
+//
+func (o *PortfolioSnapshot) FindByMarketValue(_findByMarketValue int) ([]*PortfolioSnapshot,error) {
+
+    var _modelSlice []*PortfolioSnapshot
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "market_value", _findByMarketValue)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPortfolioSnapshot(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+// FindByOpenPositions searchs against the database table field open_positions and will return []*PortfolioSnapshot,error
+// This method is a programatically generated finder for PortfolioSnapshot
+//
+//```go  
+//    m := NewPortfolioSnapshot(a)
+//    results,err := m.FindByOpenPositions(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of PortfolioSnapshot
+//    }
+//```  
+//
+func (o *PortfolioSnapshot) FindByOpenPositions(_findByOpenPositions int) ([]*PortfolioSnapshot,error) {
+
+    var _modelSlice []*PortfolioSnapshot
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "open_positions", _findByOpenPositions)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPortfolioSnapshot(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+
+// FromDBValueMap Converts a DBValueMap returned from Adapter.Query to a PortfolioSnapshot
+func (o *PortfolioSnapshot) FromDBValueMap(m map[string]DBValue) error {
+    return o.FromRow(DBValueMap(m))
+}
+// FromRow Converts a Row, e.g. from Adapter.QueryEach, to a PortfolioSnapshot
+func (o *PortfolioSnapshot) FromRow(r Row) error {
+	_Id,err := r.Get("id").AsInt64()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Id = _Id
+	_PortfolioId,err := r.Get("portfolio_id").AsInt64()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.PortfolioId = _PortfolioId
+	_Day,err := r.Get("day").AsDateTime()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Day = _Day
+	_Cash,err := r.Get("cash").AsInt()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Cash = _Cash
+	_MarketValue,err := r.Get("market_value").AsInt()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.MarketValue = _MarketValue
+	_OpenPositions,err := r.Get("open_positions").AsInt()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.OpenPositions = _OpenPositions
+
+    o._new = false
+    o.ResetDirty()
+ 	return nil
+}
+// FromPortfolioSnapshot A kind of Clone function for PortfolioSnapshot
+func (o *PortfolioSnapshot) FromPortfolioSnapshot(m *PortfolioSnapshot) {
+	o.Id = m.Id
+	o.PortfolioId = m.PortfolioId
+	o.Day = m.Day
+	o.Cash = m.Cash
+	o.MarketValue = m.MarketValue
+	o.OpenPositions = m.OpenPositions
+
+}
+// Reload A function to forcibly reload PortfolioSnapshot
+func (o *PortfolioSnapshot) Reload() error {
+    _,err := o.Find(o.GetPrimaryKeyValue())
+    return err
+}
+// EachPortfolioSnapshot runs f for every PortfolioSnapshot q selects, reading the rows
+// one at a time with Adapter.QueryEach. The PortfolioSnapshot given to f is
+// reused for the next row, copy it with FromPortfolioSnapshot to keep it.
+func EachPortfolioSnapshot(a Adapter, q string, f func(*PortfolioSnapshot) error) error {
+    o := NewPortfolioSnapshot(a)
+    return a.QueryEach(q,func(r Row) error {
+        err := o.FromRow(r)
+        if err != nil {
+            return err
+        }
+        return f(o)
+    })
+}
+
+// transaction runs f with the model bound to a transaction of its
+// Adapter, see Adapter.Transaction
+func (o *PortfolioSnapshot) transaction(f func() error) error {
+    _adapter := o._adapter
+    defer func() { o._adapter = _adapter }()
+    return _adapter.Transaction(func(a Adapter) error {
+        o._adapter = a
+        return f()
+    })
+}
+// Save is a dynamic saver 'inherited' by all models
+func (o *PortfolioSnapshot) Save() error {
+    if o._new == true {
+        return o.Create()
+    }
+    return o.Update()
+}
+// Update is a dynamic updater, it considers whether or not
+// a field is 'dirty' and needs to be updated. Will only work
+// if you use the Getters and Setters, a model with nothing dirty
+// is not written at all
+func (o *PortfolioSnapshot) Update() error {
+    if o.IsDirty() == false {
+        return nil
+    }
+    err := o.transaction(func() error {
+        err := runCallbacks(o._adapter,`PortfolioSnapshot`,BeforeSave,o)
+        if err != nil {
+            return err
+        }
+        err = o.Validate()
+        if err != nil {
+            return err
+        }
+        var sets []string
+        changes := make(map[string]string)
+        
+        if o.IsPortfolioIdDirty == true {
+            sets = append(sets,fmt.Sprintf(`portfolio_id = '%d'`,o.PortfolioId))
+            changes[`portfolio_id`] = auditValue(o.PortfolioId)
+        }
+
+        if o.IsDayDirty == true {
+            sets = append(sets,fmt.Sprintf(`day = %s`,dateTimeSQL(o.Day)))
+            changes[`day`] = auditValue(o.Day)
+        }
+
+        if o.IsCashDirty == true {
+            sets = append(sets,fmt.Sprintf(`cash = '%d'`,o.Cash))
+            changes[`cash`] = auditValue(o.Cash)
+        }
+
+        if o.IsMarketValueDirty == true {
+            sets = append(sets,fmt.Sprintf(`market_value = '%d'`,o.MarketValue))
+            changes[`market_value`] = auditValue(o.MarketValue)
+        }
+
+        if o.IsOpenPositionsDirty == true {
+            sets = append(sets,fmt.Sprintf(`open_positions = '%d'`,o.OpenPositions))
+            changes[`open_positions`] = auditValue(o.OpenPositions)
+        }
+
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,changes)
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s = '%d'",o._table,strings.Join(sets,`,`),o._pkey, o.Id)
+        _, err = o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        return runCallbacks(o._adapter,`PortfolioSnapshot`,AfterSave,o)
+    })
+    if err != nil {
+        return err
+    }
+    o.ResetDirty()
+    return nil
+}
+// Create inserts the model. Calling Save will call this function
+// automatically for new models
+func (o *PortfolioSnapshot) Create() error {
+    err := o.transaction(func() error {
+        err := runCallbacks(o._adapter,`PortfolioSnapshot`,BeforeSave,o)
+        if err != nil {
+            return err
+        }
+        err = runCallbacks(o._adapter,`PortfolioSnapshot`,BeforeCreate,o)
+        if err != nil {
+            return err
+        }
+        err = o.Validate()
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("INSERT INTO %s (`portfolio_id`, `day`, `cash`, `market_value`, `open_positions`) VALUES ('%d', %s, '%d', '%d', '%d')",o._table,o.PortfolioId, dateTimeSQL(o.Day), o.Cash, o.MarketValue, o.OpenPositions)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return o._adapter.Oops(fmt.Sprintf(`%s led to %s`,frmt,err))
+        }
+        o.Id = res.LastInsertId
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditCreate,map[string]string{
+            `portfolio_id`: auditValue(o.PortfolioId),
+            `day`: auditValue(o.Day),
+            `cash`: auditValue(o.Cash),
+            `market_value`: auditValue(o.MarketValue),
+            `open_positions`: auditValue(o.OpenPositions),
+        })
+        if err != nil {
+            return err
+        }
+        err = runCallbacks(o._adapter,`PortfolioSnapshot`,AfterCreate,o)
+        if err != nil {
+            return err
+        }
+        return runCallbacks(o._adapter,`PortfolioSnapshot`,AfterSave,o)
+    })
+    if err != nil {
+        return err
+    }
+    o._new = false
+    o.ResetDirty()
+    return nil
+}
+// Delete removes the model from the database
+func (o *PortfolioSnapshot) Delete() error {
+    return o.transaction(func() error {
+        err := runCallbacks(o._adapter,`PortfolioSnapshot`,BeforeDelete,o)
+        if err != nil {
+            return err
+        }
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditDelete,nil)
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("DELETE FROM %s WHERE %s = '%d'",o._table,o._pkey, o.Id)
+        _, err = o._adapter.Execute(frmt)
+        return err
+    })
+}
+
+// Validate checks the model against the constraints of the
+// portfolio_snapshots table and then runs the validators registered for
+// PortfolioSnapshot. Create and Save call it before writing.
+func (o *PortfolioSnapshot) Validate() error {
+    errs := make(ValidationErrors)
+    if o.PortfolioId == 0 {
+        errs.Add(`portfolio_id`,`is required`)
+    }
+    return runValidators(o._adapter,`PortfolioSnapshot`,o,errs)
+}
+
+
+// UpdatePortfolioId an immediate DB Query to update a single column, in this
+// case portfolio_id. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *PortfolioSnapshot) UpdatePortfolioId(_updPortfolioId int64) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.PortfolioId, o.IsPortfolioIdDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`portfolio_id`: auditValue(_updPortfolioId)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `portfolio_id` = '%d' WHERE `id` = '%d'",o._table,_updPortfolioId,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.PortfolioId = _updPortfolioId
+        o.IsPortfolioIdDirty = true
+        return runCallbacks(o._adapter,`PortfolioSnapshot`,AfterSave,o)
+    })
+    if err != nil {
+        o.PortfolioId, o.IsPortfolioIdDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsPortfolioIdDirty = false
+    if o._orig != nil {
+        o._orig.PortfolioId = _updPortfolioId
+    }
+    return affected,nil
+}
+
+// UpdateDay an immediate DB Query to update a single column, in this
+// case day. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *PortfolioSnapshot) UpdateDay(_updDay *DateTime) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Day, o.IsDayDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`day`: auditValue(_updDay)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `day` = %s WHERE `id` = '%d'",o._table,dateTimeSQL(_updDay),o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.Day = _updDay
+        o.IsDayDirty = true
+        return runCallbacks(o._adapter,`PortfolioSnapshot`,AfterSave,o)
+    })
+    if err != nil {
+        o.Day, o.IsDayDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsDayDirty = false
+    if o._orig != nil {
+        o._orig.Day = _updDay
+    }
+    return affected,nil
+}
+
+// UpdateCash an immediate DB Query to update a single column, in this
+// case cash. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *PortfolioSnapshot) UpdateCash(_updCash int) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Cash, o.IsCashDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`cash`: auditValue(_updCash)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `cash` = '%d' WHERE `id` = '%d'",o._table,_updCash,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.Cash = _updCash
+        o.IsCashDirty = true
+        return runCallbacks(o._adapter,`PortfolioSnapshot`,AfterSave,o)
+    })
+    if err != nil {
+        o.Cash, o.IsCashDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsCashDirty = false
+    if o._orig != nil {
+        o._orig.Cash = _updCash
+    }
+    return affected,nil
+}
+
+// UpdateMarketValue an immediate DB Query to update a single column, in this
+// case market_value. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *PortfolioSnapshot) UpdateMarketValue(_updMarketValue int) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.MarketValue, o.IsMarketValueDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`market_value`: auditValue(_updMarketValue)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `market_value` = '%d' WHERE `id` = '%d'",o._table,_updMarketValue,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.MarketValue = _updMarketValue
+        o.IsMarketValueDirty = true
+        return runCallbacks(o._adapter,`PortfolioSnapshot`,AfterSave,o)
+    })
+    if err != nil {
+        o.MarketValue, o.IsMarketValueDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsMarketValueDirty = false
+    if o._orig != nil {
+        o._orig.MarketValue = _updMarketValue
+    }
+    return affected,nil
+}
+
+// UpdateOpenPositions an immediate DB Query to update a single column, in this
+// case open_positions. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *PortfolioSnapshot) UpdateOpenPositions(_updOpenPositions int) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.OpenPositions, o.IsOpenPositionsDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`open_positions`: auditValue(_updOpenPositions)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `open_positions` = '%d' WHERE `id` = '%d'",o._table,_updOpenPositions,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.OpenPositions = _updOpenPositions
+        o.IsOpenPositionsDirty = true
+        return runCallbacks(o._adapter,`PortfolioSnapshot`,AfterSave,o)
+    })
+    if err != nil {
+        o.OpenPositions, o.IsOpenPositionsDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsOpenPositionsDirty = false
+    if o._orig != nil {
+        o._orig.OpenPositions = _updOpenPositions
+    }
+    return affected,nil
+}
+
+// IsDirty returns true when a field was set through its setter since
+// the model was loaded or last saved
+func (o *PortfolioSnapshot) IsDirty() bool {
+    return len(o.DirtyFields()) > 0
+}
+// DirtyFields returns the columns set through their setters since the
+// model was loaded or last saved, in the order of the table
+func (o *PortfolioSnapshot) DirtyFields() []string {
+    var fields []string
+    if o.IsPortfolioIdDirty == true {
+        fields = append(fields,`portfolio_id`)
+    }
+    if o.IsDayDirty == true {
+        fields = append(fields,`day`)
+    }
+    if o.IsCashDirty == true {
+        fields = append(fields,`cash`)
+    }
+    if o.IsMarketValueDirty == true {
+        fields = append(fields,`market_value`)
+    }
+    if o.IsOpenPositionsDirty == true {
+        fields = append(fields,`open_positions`)
+    }
+    return fields
+}
+// ResetDirty clears the dirty markers and takes a snapshot of the
+// current values as the originals, it is called after loading and
+// saving. The snapshot is reused so loading rows into the same model
+// does not allocate a new one each time.
+func (o *PortfolioSnapshot) ResetDirty() {
+    o.IsPortfolioIdDirty = false
+    o.IsDayDirty = false
+    o.IsCashDirty = false
+    o.IsMarketValueDirty = false
+    o.IsOpenPositionsDirty = false
+    if o._orig == nil {
+        o._orig = &PortfolioSnapshot{}
+    }
+    orig := o._orig
+    *orig = *o
+    orig._orig = nil
+    if o.Day != nil {
+        _Day := *o.Day
+        orig.Day = &_Day
+    }
+}
+// Original returns a column as it was when the model was loaded or last
+// saved, formatted like the audit log, and false for new models
+func (o *PortfolioSnapshot) Original(column string) (string,bool) {
+    if o._orig == nil {
+        return ``,false
+    }
+    switch column {
+    case `portfolio_id`:
+        return auditValue(o._orig.PortfolioId),true
+    case `day`:
+        return auditValue(o._orig.Day),true
+    case `cash`:
+        return auditValue(o._orig.Cash),true
+    case `market_value`:
+        return auditValue(o._orig.MarketValue),true
+    case `open_positions`:
+        return auditValue(o._orig.OpenPositions),true
+    }
+    return ``,false
+}
+
+// Portfolio is a Object Relational Mapping to
+// the database table that represents it. In this case it is
+// portfolios. The table name will be Sprintf'd to include
+// the prefix you define in your YAML configuration for the
+// Adapter.
+type Portfolio struct {
+    _table string
+    _adapter Adapter
+    _pkey string // 0 The name of the primary key in this table
+    _conds []string
+    _new bool
+    _orig *Portfolio
+
+    _select []string
+    _where []string
+    _cols []string
+    _values []string
+    _sets map[string]string
+    _limit string
+    _order string
+
+
+    Id int64
+    Name string
+    Description string
+    Value int
+	// Dirty markers for smart updates
+    IsIdDirty bool
+    IsNameDirty bool
+    IsDescriptionDirty bool
+    IsValueDirty bool
+	// Relationships
+}
+
+// NewPortfolio binds an Adapter to a new instance
+// of Portfolio and sets up the _table and primary keys
+func NewPortfolio(a Adapter) *Portfolio {
+    var o Portfolio
+    o._table = fmt.Sprintf("%sportfolios",a.DatabasePrefix())
+    o._adapter = a
+    o._pkey = "id"
+    o._new = true
+    return &o
+}
+
+
+// GetPrimaryKeyValue returns the value, usually int64 of
+// the PrimaryKey
+func (o *Portfolio) GetPrimaryKeyValue() int64 {
+    return o.Id
+}
+// GetPrimaryKeyName returns the DB field name
+func (o *Portfolio) GetPrimaryKeyName() string {
+    return `id`
+}
+
+// GetId returns the value of 
+// Portfolio.Id
+func (o *Portfolio) GetId() int64 {
+    return o.Id
+}
+// SetId sets and marks as dirty the value of
+// Portfolio.Id
+func (o *Portfolio) SetId(arg int64) {
+    o.Id = arg
+    o.IsIdDirty = true
+}
+
+// GetName returns the value of 
+// Portfolio.Name
+func (o *Portfolio) GetName() string {
+    return o.Name
+}
+// SetName sets and marks as dirty the value of
+// Portfolio.Name
+func (o *Portfolio) SetName(arg string) {
+    o.Name = arg
+    o.IsNameDirty = true
+}
+
+// GetDescription returns the value of 
+// Portfolio.Description
+func (o *Portfolio) GetDescription() string {
+    return o.Description
+}
+// SetDescription sets and marks as dirty the value of
+// Portfolio.Description
+func (o *Portfolio) SetDescription(arg string) {
+    o.Description = arg
+    o.IsDescriptionDirty = true
+}
+
+// GetValue returns the value of 
+// Portfolio.Value
+func (o *Portfolio) GetValue() int {
+    return o.Value
+}
+// SetValue sets and marks as dirty the value of
+// Portfolio.Value
+func (o *Portfolio) SetValue(arg int) {
+    o.Value = arg
+    o.IsValueDirty = true
+}
+
+// Find searchs against the database table field id and will return bool,error
+// This method is a programatically generated finder for Portfolio
+//  
+// Note that Find returns a bool of true|false if found or not, not err, in the case of
+// found == true, the instance data will be filled out!
+//
+// A call to find ALWAYS overwrites the model you call Find on
+// i.e. receiver is a pointer!
+//
+//```go
+//      m := NewPortfolio(a)
+//      found,err := m.Find(23)
+//      .. handle err
+//      if found == false {
+//          // handle found
+//      }
+//      ... do what you want with m here
+//```
+//
+func (o *Portfolio) Find(_findById int64) (bool,error) {
+
+    var _modelSlice []*Portfolio
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "id", _findById)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return false,o._adapter.Oops(fmt.Sprintf(`%s`,err))
+    }
+    
+    for _,result := range results {
+        ro := NewPortfolio(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return false,o._adapter.Oops(fmt.Sprintf(`%s`,err))
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return false, o._adapter.Oops(`not found`)
+    }
+    o.FromPortfolio(_modelSlice[0])
+    o._new = false
+    o.ResetDirty()
+    return true,nil
+
+}
+// FindByName searchs against the database table field name and will return []*Portfolio,error
+// This method is a programatically generated finder for Portfolio
+//
+//```go  
+//    m := NewPortfolio(a)
+//    results,err := m.FindByName(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Portfolio
+//    }
+//```  
+//
+func (o *Portfolio) FindByName(_findByName string) ([]*Portfolio,error) {
+
+    var _modelSlice []*Portfolio
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "name", _findByName)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPortfolio(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+// FindByDescription searchs against the database table field description and will return []*Portfolio,error
+// This method is a programatically generated finder for Portfolio
+//
+//```go  
+//    m := NewPortfolio(a)
+//    results,err := m.FindByDescription(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Portfolio
+//    }
+//```  
+//
+func (o *Portfolio) FindByDescription(_findByDescription string) ([]*Portfolio,error) {
+
+    var _modelSlice []*Portfolio
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "description", _findByDescription)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPortfolio(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+// FindByValue searchs against the database table field value and will return []*Portfolio,error
+// This method is a programatically generated finder for Portfolio
+//
+//```go  
+//    m := NewPortfolio(a)
+//    results,err := m.FindByValue(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Portfolio
+//    }
+//```  
+//
+func (o *Portfolio) FindByValue(_findByValue int) ([]*Portfolio,error) {
+
+    var _modelSlice []*Portfolio
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "value", _findByValue)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPortfolio(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+
+// FromDBValueMap Converts a DBValueMap returned from Adapter.Query to a Portfolio
+func (o *Portfolio) FromDBValueMap(m map[string]DBValue) error {
+    return o.FromRow(DBValueMap(m))
+}
+// FromRow Converts a Row, e.g. from Adapter.QueryEach, to a Portfolio
+func (o *Portfolio) FromRow(r Row) error {
+	_Id,err := r.Get("id").AsInt64()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Id = _Id
+	_Name,err := r.Get("name").AsString()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Name = _Name
+	_Description,err := r.Get("description").AsString()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Description = _Description
+	_Value,err := r.Get("value").AsInt()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Value = _Value
+
+    o._new = false
+    o.ResetDirty()
+ 	return nil
+}
+// FromPortfolio A kind of Clone function for Portfolio
+func (o *Portfolio) FromPortfolio(m *Portfolio) {
+	o.Id = m.Id
+	o.Name = m.Name
+	o.Description = m.Description
+	o.Value = m.Value
+
+}
+// Reload A function to forcibly reload Portfolio
+func (o *Portfolio) Reload() error {
+    _,err := o.Find(o.GetPrimaryKeyValue())
+    return err
+}
+// EachPortfolio runs f for every Portfolio q selects, reading the rows
+// one at a time with Adapter.QueryEach. The Portfolio given to f is
+// reused for the next row, copy it with FromPortfolio to keep it.
+func EachPortfolio(a Adapter, q string, f func(*Portfolio) error) error {
+    o := NewPortfolio(a)
+    return a.QueryEach(q,func(r Row) error {
+        err := o.FromRow(r)
+        if err != nil {
+            return err
+        }
+        return f(o)
+    })
+}
+
+// transaction runs f with the model bound to a transaction of its
+// Adapter, see Adapter.Transaction
+func (o *Portfolio) transaction(f func() error) error {
+    _adapter := o._adapter
+    defer func() { o._adapter = _adapter }()
+    return _adapter.Transaction(func(a Adapter) error {
+        o._adapter = a
+        return f()
+    })
+}
+// Save is a dynamic saver 'inherited' by all models
+func (o *Portfolio) Save() error {
+    if o._new == true {
+        return o.Create()
+    }
+    return o.Update()
+}
+// Update is a dynamic updater, it considers whether or not
+// a field is 'dirty' and needs to be updated. Will only work
+// if you use the Getters and Setters, a model with nothing dirty
+// is not written at all
+func (o *Portfolio) Update() error {
+    if o.IsDirty() == false {
+        return nil
+    }
+    err := o.transaction(func() error {
+        err := runCallbacks(o._adapter,`Portfolio`,BeforeSave,o)
+        if err != nil {
+            return err
+        }
+        err = o.Validate()
+        if err != nil {
+            return err
+        }
+        var sets []string
+        changes := make(map[string]string)
+        
+        if o.IsNameDirty == true {
+            sets = append(sets,fmt.Sprintf(`name = '%s'`,o._adapter.SafeString(o.Name)))
+            changes[`name`] = auditValue(o.Name)
+        }
+
+        if o.IsDescriptionDirty == true {
+            sets = append(sets,fmt.Sprintf(`description = '%s'`,o._adapter.SafeString(o.Description)))
+            changes[`description`] = auditValue(o.Description)
+        }
+
+        if o.IsValueDirty == true {
+            sets = append(sets,fmt.Sprintf(`value = '%d'`,o.Value))
+            changes[`value`] = auditValue(o.Value)
+        }
+
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,changes)
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s = '%d'",o._table,strings.Join(sets,`,`),o._pkey, o.Id)
+        _, err = o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        return runCallbacks(o._adapter,`Portfolio`,AfterSave,o)
+    })
+    if err != nil {
+        return err
+    }
+    o.ResetDirty()
+    return nil
+}
+// Create inserts the model. Calling Save will call this function
+// automatically for new models
+func (o *Portfolio) Create() error {
+    err := o.transaction(func() error {
+        err := runCallbacks(o._adapter,`Portfolio`,BeforeSave,o)
+        if err != nil {
+            return err
+        }
+        err = runCallbacks(o._adapter,`Portfolio`,BeforeCreate,o)
+        if err != nil {
+            return err
+        }
+        err = o.Validate()
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("INSERT INTO %s (`name`, `description`, `value`) VALUES ('%s', '%s', '%d')",o._table,o.Name, o.Description, o.Value)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return o._adapter.Oops(fmt.Sprintf(`%s led to %s`,frmt,err))
+        }
+        o.Id = res.LastInsertId
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditCreate,map[string]string{
+            `name`: auditValue(o.Name),
+            `description`: auditValue(o.Description),
+            `value`: auditValue(o.Value),
+        })
+        if err != nil {
+            return err
+        }
+        err = runCallbacks(o._adapter,`Portfolio`,AfterCreate,o)
+        if err != nil {
+            return err
+        }
+        return runCallbacks(o._adapter,`Portfolio`,AfterSave,o)
+    })
+    if err != nil {
+        return err
+    }
+    o._new = false
+    o.ResetDirty()
+    return nil
+}
+// Delete removes the model from the database
+func (o *Portfolio) Delete() error {
+    return o.transaction(func() error {
+        err := runCallbacks(o._adapter,`Portfolio`,BeforeDelete,o)
+        if err != nil {
+            return err
+        }
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditDelete,nil)
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("DELETE FROM %s WHERE %s = '%d'",o._table,o._pkey, o.Id)
+        _, err = o._adapter.Execute(frmt)
+        return err
+    })
+}
+
+// Validate checks the model against the constraints of the
+// portfolios table and then runs the validators registered for
+// Portfolio. Create and Save call it before writing.
+func (o *Portfolio) Validate() error {
+    errs := make(ValidationErrors)
+    if o.Name == "" {
+        errs.Add(`name`,`is required`)
+    }
+    if len(o.Name) > 255 {
+        errs.Add(`name`,`is longer than 255`)
+    }
+    return runValidators(o._adapter,`Portfolio`,o,errs)
+}
+
+
+// UpdateName an immediate DB Query to update a single column, in this
+// case name. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Portfolio) UpdateName(_updName string) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Name, o.IsNameDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`name`: auditValue(_updName)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `name` = '%s' WHERE `id` = '%d'",o._table,_updName,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.Name = _updName
+        o.IsNameDirty = true
+        return runCallbacks(o._adapter,`Portfolio`,AfterSave,o)
+    })
+    if err != nil {
+        o.Name, o.IsNameDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsNameDirty = false
+    if o._orig != nil {
+        o._orig.Name = _updName
+    }
+    return affected,nil
+}
+
+// UpdateDescription an immediate DB Query to update a single column, in this
+// case description. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Portfolio) UpdateDescription(_updDescription string) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Description, o.IsDescriptionDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`description`: auditValue(_updDescription)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `description` = '%s' WHERE `id` = '%d'",o._table,_updDescription,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.Description = _updDescription
+        o.IsDescriptionDirty = true
+        return runCallbacks(o._adapter,`Portfolio`,AfterSave,o)
+    })
+    if err != nil {
+        o.Description, o.IsDescriptionDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsDescriptionDirty = false
+    if o._orig != nil {
+        o._orig.Description = _updDescription
+    }
+    return affected,nil
+}
+
+// UpdateValue an immediate DB Query to update a single column, in this
+// case value. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Portfolio) UpdateValue(_updValue int) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Value, o.IsValueDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`value`: auditValue(_updValue)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `value` = '%d' WHERE `id` = '%d'",o._table,_updValue,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.Value = _updValue
+        o.IsValueDirty = true
+        return runCallbacks(o._adapter,`Portfolio`,AfterSave,o)
+    })
+    if err != nil {
+        o.Value, o.IsValueDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsValueDirty = false
+    if o._orig != nil {
+        o._orig.Value = _updValue
+    }
+    return affected,nil
+}
+
+// IsDirty returns true when a field was set through its setter since
+// the model was loaded or last saved
+func (o *Portfolio) IsDirty() bool {
+    return len(o.DirtyFields()) > 0
+}
+// DirtyFields returns the columns set through their setters since the
+// model was loaded or last saved, in the order of the table
+func (o *Portfolio) DirtyFields() []string {
+    var fields []string
+    if o.IsNameDirty == true {
+        fields = append(fields,`name`)
+    }
+    if o.IsDescriptionDirty == true {
+        fields = append(fields,`description`)
+    }
+    if o.IsValueDirty == true {
+        fields = append(fields,`value`)
+    }
+    return fields
+}
+// ResetDirty clears the dirty markers and takes a snapshot of the
+// current values as the originals, it is called after loading and
+// saving. The snapshot is reused so loading rows into the same model
+// does not allocate a new one each time.
+func (o *Portfolio) ResetDirty() {
+    o.IsNameDirty = false
+    o.IsDescriptionDirty = false
+    o.IsValueDirty = false
+    if o._orig == nil {
+        o._orig = &Portfolio{}
+    }
+    orig := o._orig
+    *orig = *o
+    orig._orig = nil
+}
+// Original returns a column as it was when the model was loaded or last
+// saved, formatted like the audit log, and false for new models
+func (o *Portfolio) Original(column string) (string,bool) {
+    if o._orig == nil {
+        return ``,false
+    }
+    switch column {
+    case `name`:
+        return auditValue(o._orig.Name),true
+    case `description`:
+        return auditValue(o._orig.Description),true
+    case `value`:
+        return auditValue(o._orig.Value),true
+    }
+    return ``,false
+}
+
+// Position is a Object Relational Mapping to
+// the database table that represents it. In this case it is
+// positions. The table name will be Sprintf'd to include
+// the prefix you define in your YAML configuration for the
+// Adapter.
+type Position struct {
+    _table string
+    _adapter Adapter
+    _pkey string // 0 The name of the primary key in this table
+    _conds []string
+    _new bool
+    _orig *Position
+
+    _select []string
+    _where []string
+    _cols []string
+    _values []string
+    _sets map[string]string
+    _limit string
+    _order string
+
+
+    Id int64
+    PortfolioId int64
+    Symbol string
+    StartedAt *DateTime
+    ClosedAt *DateTime
+    Ptype string
+    Buy int
+    Sell int
+    StopLoss int
+    Quantity int
+    LockVersion int
+	// Dirty markers for smart updates
+    IsIdDirty bool
+    IsPortfolioIdDirty bool
+    IsSymbolDirty bool
+    IsStartedAtDirty bool
+    IsClosedAtDirty bool
+    IsPtypeDirty bool
+    IsBuyDirty bool
+    IsSellDirty bool
+    IsStopLossDirty bool
+    IsQuantityDirty bool
+    IsLockVersionDirty bool
+	// Relationships
+}
+
+// NewPosition binds an Adapter to a new instance
+// of Position and sets up the _table and primary keys
+func NewPosition(a Adapter) *Position {
+    var o Position
+    o._table = fmt.Sprintf("%spositions",a.DatabasePrefix())
+    o._adapter = a
+    o._pkey = "id"
+    o._new = true
+    return &o
+}
+
+
+// GetPrimaryKeyValue returns the value, usually int64 of
+// the PrimaryKey
+func (o *Position) GetPrimaryKeyValue() int64 {
+    return o.Id
+}
+// GetPrimaryKeyName returns the DB field name
+func (o *Position) GetPrimaryKeyName() string {
+    return `id`
+}
+
+// GetId returns the value of 
+// Position.Id
+func (o *Position) GetId() int64 {
+    return o.Id
+}
+// SetId sets and marks as dirty the value of
+// Position.Id
+func (o *Position) SetId(arg int64) {
+    o.Id = arg
+    o.IsIdDirty = true
+}
+
+// GetPortfolioId returns the value of 
+// Position.PortfolioId
+func (o *Position) GetPortfolioId() int64 {
+    return o.PortfolioId
+}
+// SetPortfolioId sets and marks as dirty the value of
+// Position.PortfolioId
+func (o *Position) SetPortfolioId(arg int64) {
+    o.PortfolioId = arg
+    o.IsPortfolioIdDirty = true
+}
+
+// GetSymbol returns the value of 
+// Position.Symbol
+func (o *Position) GetSymbol() string {
+    return o.Symbol
+}
+// SetSymbol sets and marks as dirty the value of
+// Position.Symbol
+func (o *Position) SetSymbol(arg string) {
+    o.Symbol = arg
+    o.IsSymbolDirty = true
+}
+
+// GetStartedAt returns the value of 
+// Position.StartedAt
+func (o *Position) GetStartedAt() *DateTime {
+    return o.StartedAt
+}
+// SetStartedAt sets and marks as dirty the value of
+// Position.StartedAt
+func (o *Position) SetStartedAt(arg *DateTime) {
+    o.StartedAt = arg
+    o.IsStartedAtDirty = true
+}
+
+// GetClosedAt returns the value of 
+// Position.ClosedAt
+func (o *Position) GetClosedAt() *DateTime {
+    return o.ClosedAt
+}
+// SetClosedAt sets and marks as dirty the value of
+// Position.ClosedAt
+func (o *Position) SetClosedAt(arg *DateTime) {
+    o.ClosedAt = arg
+    o.IsClosedAtDirty = true
+}
+
+// GetPtype returns the value of 
+// Position.Ptype
+func (o *Position) GetPtype() string {
+    return o.Ptype
+}
+// SetPtype sets and marks as dirty the value of
+// Position.Ptype
+func (o *Position) SetPtype(arg string) {
+    o.Ptype = arg
+    o.IsPtypeDirty = true
+}
+
+// GetBuy returns the value of 
+// Position.Buy
+func (o *Position) GetBuy() int {
+    return o.Buy
+}
+// SetBuy sets and marks as dirty the value of
+// Position.Buy
+func (o *Position) SetBuy(arg int) {
+    o.Buy = arg
+    o.IsBuyDirty = true
+}
+
+// GetSell returns the value of 
+// Position.Sell
+func (o *Position) GetSell() int {
+    return o.Sell
 }
 // SetSell sets and marks as dirty the value of
 // Position.Sell
@@ -2374,320 +6349,1376 @@ func (o *Position) SetSell(arg int) {
     o.IsSellDirty = true
 }
 
-// GetStopLoss returns the value of 
-// Position.StopLoss
-func (o *Position) GetStopLoss() int {
-    return o.StopLoss
-}
-// SetStopLoss sets and marks as dirty the value of
-// Position.StopLoss
-func (o *Position) SetStopLoss(arg int) {
-    o.StopLoss = arg
-    o.IsStopLossDirty = true
-}
+// GetStopLoss returns the value of 
+// Position.StopLoss
+func (o *Position) GetStopLoss() int {
+    return o.StopLoss
+}
+// SetStopLoss sets and marks as dirty the value of
+// Position.StopLoss
+func (o *Position) SetStopLoss(arg int) {
+    o.StopLoss = arg
+    o.IsStopLossDirty = true
+}
+
+// GetQuantity returns the value of 
+// Position.Quantity
+func (o *Position) GetQuantity() int {
+    return o.Quantity
+}
+// SetQuantity sets and marks as dirty the value of
+// Position.Quantity
+func (o *Position) SetQuantity(arg int) {
+    o.Quantity = arg
+    o.IsQuantityDirty = true
+}
+
+// GetLockVersion returns the value of 
+// Position.LockVersion
+func (o *Position) GetLockVersion() int {
+    return o.LockVersion
+}
+// SetLockVersion sets and marks as dirty the value of
+// Position.LockVersion
+func (o *Position) SetLockVersion(arg int) {
+    o.LockVersion = arg
+    o.IsLockVersionDirty = true
+}
+
+// Find searchs against the database table field id and will return bool,error
+// This method is a programatically generated finder for Position
+//  
+// Note that Find returns a bool of true|false if found or not, not err, in the case of
+// found == true, the instance data will be filled out!
+//
+// A call to find ALWAYS overwrites the model you call Find on
+// i.e. receiver is a pointer!
+//
+//```go
+//      m := NewPosition(a)
+//      found,err := m.Find(23)
+//      .. handle err
+//      if found == false {
+//          // handle found
+//      }
+//      ... do what you want with m here
+//```
+//
+func (o *Position) Find(_findById int64) (bool,error) {
+
+    var _modelSlice []*Position
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "id", _findById)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return false,o._adapter.Oops(fmt.Sprintf(`%s`,err))
+    }
+    
+    for _,result := range results {
+        ro := NewPosition(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return false,o._adapter.Oops(fmt.Sprintf(`%s`,err))
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return false, o._adapter.Oops(`not found`)
+    }
+    o.FromPosition(_modelSlice[0])
+    o._new = false
+    o.ResetDirty()
+    return true,nil
+
+}
+// FindByPortfolioId searchs against the database table field portfolio_id and will return []*Position,error
+// This method is a programatically generated finder for Position
+//
+//```go  
+//    m := NewPosition(a)
+//    results,err := m.FindByPortfolioId(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Position
+//    }
+//```  
+//
+func (o *Position) FindByPortfolioId(_findByPortfolioId int64) ([]*Position,error) {
+
+    var _modelSlice []*Position
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "portfolio_id", _findByPortfolioId)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPosition(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+// FindBySymbol searchs against the database table field symbol and will return []*Position,error
+// This method is a programatically generated finder for Position
+//
+//```go  
+//    m := NewPosition(a)
+//    results,err := m.FindBySymbol(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Position
+//    }
+//```  
+//
+func (o *Position) FindBySymbol(_findBySymbol string) ([]*Position,error) {
+
+    var _modelSlice []*Position
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "symbol", _findBySymbol)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPosition(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+// FindByStartedAt searchs against the database table field started_at and will return []*Position,error
+// This method is a programatically generated finder for Position
+//
+//```go  
+//    m := NewPosition(a)
+//    results,err := m.FindByStartedAt(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Position
+//    }
+//```  
+//
+func (o *Position) FindByStartedAt(_findByStartedAt *DateTime) ([]*Position,error) {
+
+    var _modelSlice []*Position
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "started_at", _findByStartedAt)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPosition(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+// FindByClosedAt searchs against the database table field closed_at and will return []*Position,error
+// This method is a programatically generated finder for Position
+//
+//```go  
+//    m := NewPosition(a)
+//    results,err := m.FindByClosedAt(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Position
+//    }
+//```  
+//
+func (o *Position) FindByClosedAt(_findByClosedAt *DateTime) ([]*Position,error) {
+
+    var _modelSlice []*Position
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "closed_at", _findByClosedAt)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPosition(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+// FindByPtype searchs against the database table field ptype and will return []*Position,error
+// This method is a programatically generated finder for Position
+//
+//```go  
+//    m := NewPosition(a)
+//    results,err := m.FindByPtype(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Position
+//    }
+//```  
+//
+func (o *Position) FindByPtype(_findByPtype string) ([]*Position,error) {
+
+    var _modelSlice []*Position
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "ptype", _findByPtype)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPosition(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+// FindByBuy searchs against the database table field buy and will return []*Position,error
+// This method is a programatically generated finder for Position
+//
+//```go  
+//    m := NewPosition(a)
+//    results,err := m.FindByBuy(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Position
+//    }
+//```  
+//
+func (o *Position) FindByBuy(_findByBuy int) ([]*Position,error) {
+
+    var _modelSlice []*Position
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "buy", _findByBuy)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPosition(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+// FindBySell searchs against the database table field sell and will return []*Position,error
+// This method is a programatically generated finder for Position
+//
+//```go  
+//    m := NewPosition(a)
+//    results,err := m.FindBySell(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Position
+//    }
+//```  
+//
+func (o *Position) FindBySell(_findBySell int) ([]*Position,error) {
+
+    var _modelSlice []*Position
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "sell", _findBySell)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPosition(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+// FindByStopLoss searchs against the database table field stop_loss and will return []*Position,error
+// This method is a programatically generated finder for Position
+//
+//```go  
+//    m := NewPosition(a)
+//    results,err := m.FindByStopLoss(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Position
+//    }
+//```  
+//
+func (o *Position) FindByStopLoss(_findByStopLoss int) ([]*Position,error) {
+
+    var _modelSlice []*Position
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "stop_loss", _findByStopLoss)
+    results, err := o._adapter.Query(q)
+    if err != nil {
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPosition(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
 
-// GetQuantity returns the value of 
-// Position.Quantity
-func (o *Position) GetQuantity() int {
-    return o.Quantity
-}
-// SetQuantity sets and marks as dirty the value of
-// Position.Quantity
-func (o *Position) SetQuantity(arg int) {
-    o.Quantity = arg
-    o.IsQuantityDirty = true
 }
-
-// Find searchs against the database table field id and will return bool,error
+// FindByQuantity searchs against the database table field quantity and will return []*Position,error
 // This method is a programatically generated finder for Position
-//  
-// Note that Find returns a bool of true|false if found or not, not err, in the case of
-// found == true, the instance data will be filled out!
-//
-// A call to find ALWAYS overwrites the model you call Find on
-// i.e. receiver is a pointer!
 //
-//```go
-//      m := NewPosition(a)
-//      found,err := m.Find(23)
-//      .. handle err
-//      if found == false {
-//          // handle found
-//      }
-//      ... do what you want with m here
-//```
+//```go  
+//    m := NewPosition(a)
+//    results,err := m.FindByQuantity(...)
+//    // handle err
+//    for i,r := results {
+//      // now r is an instance of Position
+//    }
+//```  
 //
-func (o *Position) Find(_findById int64) (bool,error) {
+func (o *Position) FindByQuantity(_findByQuantity int) ([]*Position,error) {
 
     var _modelSlice []*Position
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "id", _findById)
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "quantity", _findByQuantity)
     results, err := o._adapter.Query(q)
     if err != nil {
-        return false,o._adapter.Oops(fmt.Sprintf(`%s`,err))
+        return _modelSlice,err
     }
     
     for _,result := range results {
         ro := NewPosition(o._adapter)
         err = ro.FromDBValueMap(result)
         if err != nil {
-            return false,o._adapter.Oops(fmt.Sprintf(`%s`,err))
+            return _modelSlice,err
         }
         _modelSlice = append(_modelSlice,ro)
     }
 
     if len(_modelSlice) == 0 {
         // there was an error!
-        return false, o._adapter.Oops(`not found`)
+        return nil, o._adapter.Oops(`no results`)
     }
-    o.FromPosition(_modelSlice[0])
-    return true,nil
+    return _modelSlice,nil
 
 }
-// FindByPortfolioId searchs against the database table field portfolio_id and will return []*Position,error
+// FindByLockVersion searchs against the database table field lock_version and will return []*Position,error
 // This method is a programatically generated finder for Position
 //
 //```go  
 //    m := NewPosition(a)
-//    results,err := m.FindByPortfolioId(...)
+//    results,err := m.FindByLockVersion(...)
 //    // handle err
 //    for i,r := results {
 //      // now r is an instance of Position
 //    }
 //```  
 //
-func (o *Position) FindByPortfolioId(_findByPortfolioId int64) ([]*Position,error) {
+func (o *Position) FindByLockVersion(_findByLockVersion int) ([]*Position,error) {
 
     var _modelSlice []*Position
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "portfolio_id", _findByPortfolioId)
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "lock_version", _findByLockVersion)
     results, err := o._adapter.Query(q)
     if err != nil {
-        return _modelSlice,err
+        return _modelSlice,err
+    }
+    
+    for _,result := range results {
+        ro := NewPosition(o._adapter)
+        err = ro.FromDBValueMap(result)
+        if err != nil {
+            return _modelSlice,err
+        }
+        _modelSlice = append(_modelSlice,ro)
+    }
+
+    if len(_modelSlice) == 0 {
+        // there was an error!
+        return nil, o._adapter.Oops(`no results`)
+    }
+    return _modelSlice,nil
+
+}
+
+// FromDBValueMap Converts a DBValueMap returned from Adapter.Query to a Position
+func (o *Position) FromDBValueMap(m map[string]DBValue) error {
+    return o.FromRow(DBValueMap(m))
+}
+// FromRow Converts a Row, e.g. from Adapter.QueryEach, to a Position
+func (o *Position) FromRow(r Row) error {
+	_Id,err := r.Get("id").AsInt64()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Id = _Id
+	_PortfolioId,err := r.Get("portfolio_id").AsInt64()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.PortfolioId = _PortfolioId
+	_Symbol,err := r.Get("symbol").AsString()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Symbol = _Symbol
+	_StartedAt,err := r.Get("started_at").AsDateTime()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.StartedAt = _StartedAt
+	_ClosedAt,err := r.Get("closed_at").AsDateTime()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.ClosedAt = _ClosedAt
+	_Ptype,err := r.Get("ptype").AsString()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Ptype = _Ptype
+	_Buy,err := r.Get("buy").AsInt()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Buy = _Buy
+	_Sell,err := r.Get("sell").AsInt()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Sell = _Sell
+	_StopLoss,err := r.Get("stop_loss").AsInt()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.StopLoss = _StopLoss
+	_Quantity,err := r.Get("quantity").AsInt()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.Quantity = _Quantity
+	_LockVersion,err := r.Get("lock_version").AsInt()
+	if err != nil {
+ 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
+	}
+	o.LockVersion = _LockVersion
+
+    o._new = false
+    o.ResetDirty()
+ 	return nil
+}
+// FromPosition A kind of Clone function for Position
+func (o *Position) FromPosition(m *Position) {
+	o.Id = m.Id
+	o.PortfolioId = m.PortfolioId
+	o.Symbol = m.Symbol
+	o.StartedAt = m.StartedAt
+	o.ClosedAt = m.ClosedAt
+	o.Ptype = m.Ptype
+	o.Buy = m.Buy
+	o.Sell = m.Sell
+	o.StopLoss = m.StopLoss
+	o.Quantity = m.Quantity
+	o.LockVersion = m.LockVersion
+
+}
+// Reload A function to forcibly reload Position
+func (o *Position) Reload() error {
+    _,err := o.Find(o.GetPrimaryKeyValue())
+    return err
+}
+// EachPosition runs f for every Position q selects, reading the rows
+// one at a time with Adapter.QueryEach. The Position given to f is
+// reused for the next row, copy it with FromPosition to keep it.
+func EachPosition(a Adapter, q string, f func(*Position) error) error {
+    o := NewPosition(a)
+    return a.QueryEach(q,func(r Row) error {
+        err := o.FromRow(r)
+        if err != nil {
+            return err
+        }
+        return f(o)
+    })
+}
+
+// transaction runs f with the model bound to a transaction of its
+// Adapter, see Adapter.Transaction
+func (o *Position) transaction(f func() error) error {
+    _adapter := o._adapter
+    defer func() { o._adapter = _adapter }()
+    return _adapter.Transaction(func(a Adapter) error {
+        o._adapter = a
+        return f()
+    })
+}
+// Save is a dynamic saver 'inherited' by all models
+func (o *Position) Save() error {
+    if o._new == true {
+        return o.Create()
+    }
+    return o.Update()
+}
+// Update is a dynamic updater, it considers whether or not
+// a field is 'dirty' and needs to be updated. Will only work
+// if you use the Getters and Setters, a model with nothing dirty
+// is not written at all
+func (o *Position) Update() error {
+    if o.IsDirty() == false {
+        return nil
+    }
+    err := o.transaction(func() error {
+        err := runCallbacks(o._adapter,`Position`,BeforeSave,o)
+        if err != nil {
+            return err
+        }
+        err = o.Validate()
+        if err != nil {
+            return err
+        }
+        var sets []string
+        changes := make(map[string]string)
+        
+        if o.IsPortfolioIdDirty == true {
+            sets = append(sets,fmt.Sprintf(`portfolio_id = '%d'`,o.PortfolioId))
+            changes[`portfolio_id`] = auditValue(o.PortfolioId)
+        }
+
+        if o.IsSymbolDirty == true {
+            sets = append(sets,fmt.Sprintf(`symbol = '%s'`,o._adapter.SafeString(o.Symbol)))
+            changes[`symbol`] = auditValue(o.Symbol)
+        }
+
+        if o.IsStartedAtDirty == true {
+            sets = append(sets,fmt.Sprintf(`started_at = %s`,dateTimeSQL(o.StartedAt)))
+            changes[`started_at`] = auditValue(o.StartedAt)
+        }
+
+        if o.IsClosedAtDirty == true {
+            sets = append(sets,fmt.Sprintf(`closed_at = %s`,dateTimeSQL(o.ClosedAt)))
+            changes[`closed_at`] = auditValue(o.ClosedAt)
+        }
+
+        if o.IsPtypeDirty == true {
+            sets = append(sets,fmt.Sprintf(`ptype = '%s'`,o._adapter.SafeString(o.Ptype)))
+            changes[`ptype`] = auditValue(o.Ptype)
+        }
+
+        if o.IsBuyDirty == true {
+            sets = append(sets,fmt.Sprintf(`buy = '%d'`,o.Buy))
+            changes[`buy`] = auditValue(o.Buy)
+        }
+
+        if o.IsSellDirty == true {
+            sets = append(sets,fmt.Sprintf(`sell = '%d'`,o.Sell))
+            changes[`sell`] = auditValue(o.Sell)
+        }
+
+        if o.IsStopLossDirty == true {
+            sets = append(sets,fmt.Sprintf(`stop_loss = '%d'`,o.StopLoss))
+            changes[`stop_loss`] = auditValue(o.StopLoss)
+        }
+
+        if o.IsQuantityDirty == true {
+            sets = append(sets,fmt.Sprintf(`quantity = '%d'`,o.Quantity))
+            changes[`quantity`] = auditValue(o.Quantity)
+        }
+
+        sets = append(sets,`lock_version = lock_version + 1`)
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,changes)
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s = '%d' AND lock_version = '%d'",o._table,strings.Join(sets,`,`),o._pkey, o.Id, o.LockVersion)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        if res.RowsAffected == 0 {
+            return ErrStaleObject
+        }
+        return runCallbacks(o._adapter,`Position`,AfterSave,o)
+    })
+    if err != nil {
+        return err
+    }
+    o.LockVersion++
+    o.ResetDirty()
+    return nil
+}
+// Create inserts the model. Calling Save will call this function
+// automatically for new models
+func (o *Position) Create() error {
+    err := o.transaction(func() error {
+        err := runCallbacks(o._adapter,`Position`,BeforeSave,o)
+        if err != nil {
+            return err
+        }
+        err = runCallbacks(o._adapter,`Position`,BeforeCreate,o)
+        if err != nil {
+            return err
+        }
+        err = o.Validate()
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("INSERT INTO %s (`portfolio_id`, `symbol`, `started_at`, `closed_at`, `ptype`, `buy`, `sell`, `stop_loss`, `quantity`, `lock_version`) VALUES ('%d', '%s', %s, %s, '%s', '%d', '%d', '%d', '%d', '%d')",o._table,o.PortfolioId, o.Symbol, dateTimeSQL(o.StartedAt), dateTimeSQL(o.ClosedAt), o.Ptype, o.Buy, o.Sell, o.StopLoss, o.Quantity, o.LockVersion)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return o._adapter.Oops(fmt.Sprintf(`%s led to %s`,frmt,err))
+        }
+        o.Id = res.LastInsertId
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditCreate,map[string]string{
+            `portfolio_id`: auditValue(o.PortfolioId),
+            `symbol`: auditValue(o.Symbol),
+            `started_at`: auditValue(o.StartedAt),
+            `closed_at`: auditValue(o.ClosedAt),
+            `ptype`: auditValue(o.Ptype),
+            `buy`: auditValue(o.Buy),
+            `sell`: auditValue(o.Sell),
+            `stop_loss`: auditValue(o.StopLoss),
+            `quantity`: auditValue(o.Quantity),
+            `lock_version`: auditValue(o.LockVersion),
+        })
+        if err != nil {
+            return err
+        }
+        err = runCallbacks(o._adapter,`Position`,AfterCreate,o)
+        if err != nil {
+            return err
+        }
+        return runCallbacks(o._adapter,`Position`,AfterSave,o)
+    })
+    if err != nil {
+        return err
+    }
+    o._new = false
+    o.ResetDirty()
+    return nil
+}
+// Delete removes the model from the database
+func (o *Position) Delete() error {
+    return o.transaction(func() error {
+        err := runCallbacks(o._adapter,`Position`,BeforeDelete,o)
+        if err != nil {
+            return err
+        }
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditDelete,nil)
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("DELETE FROM %s WHERE %s = '%d'",o._table,o._pkey, o.Id)
+        _, err = o._adapter.Execute(frmt)
+        return err
+    })
+}
+
+// Validate checks the model against the constraints of the
+// positions table and then runs the validators registered for
+// Position. Create and Save call it before writing.
+func (o *Position) Validate() error {
+    errs := make(ValidationErrors)
+    if o.PortfolioId == 0 {
+        errs.Add(`portfolio_id`,`is required`)
+    }
+    if len(o.Symbol) > 255 {
+        errs.Add(`symbol`,`is longer than 255`)
+    }
+    if len(o.Ptype) > 255 {
+        errs.Add(`ptype`,`is longer than 255`)
+    }
+    return runValidators(o._adapter,`Position`,o,errs)
+}
+
+
+// UpdatePortfolioId an immediate DB Query to update a single column, in this
+// case portfolio_id. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Position) UpdatePortfolioId(_updPortfolioId int64) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.PortfolioId, o.IsPortfolioIdDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`portfolio_id`: auditValue(_updPortfolioId)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `portfolio_id` = '%d', lock_version = lock_version + 1 WHERE `id` = '%d' AND lock_version = '%d'",o._table,_updPortfolioId,o.Id,o.LockVersion)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        if affected == 0 {
+            return ErrStaleObject
+        }
+        o.PortfolioId = _updPortfolioId
+        o.IsPortfolioIdDirty = true
+        return runCallbacks(o._adapter,`Position`,AfterSave,o)
+    })
+    if err != nil {
+        o.PortfolioId, o.IsPortfolioIdDirty = prev, prevDirty
+        return 0,err
     }
-    
-    for _,result := range results {
-        ro := NewPosition(o._adapter)
-        err = ro.FromDBValueMap(result)
+    o.IsPortfolioIdDirty = false
+    o.LockVersion++
+    if o._orig != nil {
+        o._orig.PortfolioId = _updPortfolioId
+        o._orig.LockVersion = o.LockVersion
+    }
+    return affected,nil
+}
+
+// UpdateSymbol an immediate DB Query to update a single column, in this
+// case symbol. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Position) UpdateSymbol(_updSymbol string) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Symbol, o.IsSymbolDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`symbol`: auditValue(_updSymbol)})
         if err != nil {
-            return _modelSlice,err
+            return err
         }
-        _modelSlice = append(_modelSlice,ro)
+        frmt := fmt.Sprintf("UPDATE %s SET `symbol` = '%s', lock_version = lock_version + 1 WHERE `id` = '%d' AND lock_version = '%d'",o._table,_updSymbol,o.Id,o.LockVersion)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        if affected == 0 {
+            return ErrStaleObject
+        }
+        o.Symbol = _updSymbol
+        o.IsSymbolDirty = true
+        return runCallbacks(o._adapter,`Position`,AfterSave,o)
+    })
+    if err != nil {
+        o.Symbol, o.IsSymbolDirty = prev, prevDirty
+        return 0,err
     }
-
-    if len(_modelSlice) == 0 {
-        // there was an error!
-        return nil, o._adapter.Oops(`no results`)
+    o.IsSymbolDirty = false
+    o.LockVersion++
+    if o._orig != nil {
+        o._orig.Symbol = _updSymbol
+        o._orig.LockVersion = o.LockVersion
     }
-    return _modelSlice,nil
-
+    return affected,nil
 }
-// FindByStartedAt searchs against the database table field started_at and will return []*Position,error
-// This method is a programatically generated finder for Position
-//
-//```go  
-//    m := NewPosition(a)
-//    results,err := m.FindByStartedAt(...)
-//    // handle err
-//    for i,r := results {
-//      // now r is an instance of Position
-//    }
-//```  
-//
-func (o *Position) FindByStartedAt(_findByStartedAt *DateTime) ([]*Position,error) {
 
-    var _modelSlice []*Position
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "started_at", _findByStartedAt)
-    results, err := o._adapter.Query(q)
+// UpdateStartedAt an immediate DB Query to update a single column, in this
+// case started_at. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Position) UpdateStartedAt(_updStartedAt *DateTime) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.StartedAt, o.IsStartedAtDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`started_at`: auditValue(_updStartedAt)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `started_at` = %s, lock_version = lock_version + 1 WHERE `id` = '%d' AND lock_version = '%d'",o._table,dateTimeSQL(_updStartedAt),o.Id,o.LockVersion)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        if affected == 0 {
+            return ErrStaleObject
+        }
+        o.StartedAt = _updStartedAt
+        o.IsStartedAtDirty = true
+        return runCallbacks(o._adapter,`Position`,AfterSave,o)
+    })
     if err != nil {
-        return _modelSlice,err
+        o.StartedAt, o.IsStartedAtDirty = prev, prevDirty
+        return 0,err
     }
-    
-    for _,result := range results {
-        ro := NewPosition(o._adapter)
-        err = ro.FromDBValueMap(result)
+    o.IsStartedAtDirty = false
+    o.LockVersion++
+    if o._orig != nil {
+        o._orig.StartedAt = _updStartedAt
+        o._orig.LockVersion = o.LockVersion
+    }
+    return affected,nil
+}
+
+// UpdateClosedAt an immediate DB Query to update a single column, in this
+// case closed_at. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Position) UpdateClosedAt(_updClosedAt *DateTime) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.ClosedAt, o.IsClosedAtDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`closed_at`: auditValue(_updClosedAt)})
         if err != nil {
-            return _modelSlice,err
+            return err
         }
-        _modelSlice = append(_modelSlice,ro)
+        frmt := fmt.Sprintf("UPDATE %s SET `closed_at` = %s, lock_version = lock_version + 1 WHERE `id` = '%d' AND lock_version = '%d'",o._table,dateTimeSQL(_updClosedAt),o.Id,o.LockVersion)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        if affected == 0 {
+            return ErrStaleObject
+        }
+        o.ClosedAt = _updClosedAt
+        o.IsClosedAtDirty = true
+        return runCallbacks(o._adapter,`Position`,AfterSave,o)
+    })
+    if err != nil {
+        o.ClosedAt, o.IsClosedAtDirty = prev, prevDirty
+        return 0,err
     }
-
-    if len(_modelSlice) == 0 {
-        // there was an error!
-        return nil, o._adapter.Oops(`no results`)
+    o.IsClosedAtDirty = false
+    o.LockVersion++
+    if o._orig != nil {
+        o._orig.ClosedAt = _updClosedAt
+        o._orig.LockVersion = o.LockVersion
     }
-    return _modelSlice,nil
-
+    return affected,nil
 }
-// FindByClosedAt searchs against the database table field closed_at and will return []*Position,error
-// This method is a programatically generated finder for Position
-//
-//```go  
-//    m := NewPosition(a)
-//    results,err := m.FindByClosedAt(...)
-//    // handle err
-//    for i,r := results {
-//      // now r is an instance of Position
-//    }
-//```  
-//
-func (o *Position) FindByClosedAt(_findByClosedAt *DateTime) ([]*Position,error) {
 
-    var _modelSlice []*Position
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "closed_at", _findByClosedAt)
-    results, err := o._adapter.Query(q)
+// UpdatePtype an immediate DB Query to update a single column, in this
+// case ptype. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Position) UpdatePtype(_updPtype string) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Ptype, o.IsPtypeDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`ptype`: auditValue(_updPtype)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `ptype` = '%s', lock_version = lock_version + 1 WHERE `id` = '%d' AND lock_version = '%d'",o._table,_updPtype,o.Id,o.LockVersion)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        if affected == 0 {
+            return ErrStaleObject
+        }
+        o.Ptype = _updPtype
+        o.IsPtypeDirty = true
+        return runCallbacks(o._adapter,`Position`,AfterSave,o)
+    })
     if err != nil {
-        return _modelSlice,err
+        o.Ptype, o.IsPtypeDirty = prev, prevDirty
+        return 0,err
     }
-    
-    for _,result := range results {
-        ro := NewPosition(o._adapter)
-        err = ro.FromDBValueMap(result)
+    o.IsPtypeDirty = false
+    o.LockVersion++
+    if o._orig != nil {
+        o._orig.Ptype = _updPtype
+        o._orig.LockVersion = o.LockVersion
+    }
+    return affected,nil
+}
+
+// UpdateBuy an immediate DB Query to update a single column, in this
+// case buy. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Position) UpdateBuy(_updBuy int) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Buy, o.IsBuyDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`buy`: auditValue(_updBuy)})
         if err != nil {
-            return _modelSlice,err
+            return err
         }
-        _modelSlice = append(_modelSlice,ro)
+        frmt := fmt.Sprintf("UPDATE %s SET `buy` = '%d', lock_version = lock_version + 1 WHERE `id` = '%d' AND lock_version = '%d'",o._table,_updBuy,o.Id,o.LockVersion)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        if affected == 0 {
+            return ErrStaleObject
+        }
+        o.Buy = _updBuy
+        o.IsBuyDirty = true
+        return runCallbacks(o._adapter,`Position`,AfterSave,o)
+    })
+    if err != nil {
+        o.Buy, o.IsBuyDirty = prev, prevDirty
+        return 0,err
     }
-
-    if len(_modelSlice) == 0 {
-        // there was an error!
-        return nil, o._adapter.Oops(`no results`)
+    o.IsBuyDirty = false
+    o.LockVersion++
+    if o._orig != nil {
+        o._orig.Buy = _updBuy
+        o._orig.LockVersion = o.LockVersion
     }
-    return _modelSlice,nil
-
+    return affected,nil
 }
-// FindByPtype searchs against the database table field ptype and will return []*Position,error
-// This method is a programatically generated finder for Position
-//
-//```go  
-//    m := NewPosition(a)
-//    results,err := m.FindByPtype(...)
-//    // handle err
-//    for i,r := results {
-//      // now r is an instance of Position
-//    }
-//```  
-//
-func (o *Position) FindByPtype(_findByPtype string) ([]*Position,error) {
 
-    var _modelSlice []*Position
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "ptype", _findByPtype)
-    results, err := o._adapter.Query(q)
+// UpdateSell an immediate DB Query to update a single column, in this
+// case sell. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Position) UpdateSell(_updSell int) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Sell, o.IsSellDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`sell`: auditValue(_updSell)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `sell` = '%d', lock_version = lock_version + 1 WHERE `id` = '%d' AND lock_version = '%d'",o._table,_updSell,o.Id,o.LockVersion)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        if affected == 0 {
+            return ErrStaleObject
+        }
+        o.Sell = _updSell
+        o.IsSellDirty = true
+        return runCallbacks(o._adapter,`Position`,AfterSave,o)
+    })
     if err != nil {
-        return _modelSlice,err
+        o.Sell, o.IsSellDirty = prev, prevDirty
+        return 0,err
     }
-    
-    for _,result := range results {
-        ro := NewPosition(o._adapter)
-        err = ro.FromDBValueMap(result)
+    o.IsSellDirty = false
+    o.LockVersion++
+    if o._orig != nil {
+        o._orig.Sell = _updSell
+        o._orig.LockVersion = o.LockVersion
+    }
+    return affected,nil
+}
+
+// UpdateStopLoss an immediate DB Query to update a single column, in this
+// case stop_loss. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Position) UpdateStopLoss(_updStopLoss int) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.StopLoss, o.IsStopLossDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`stop_loss`: auditValue(_updStopLoss)})
         if err != nil {
-            return _modelSlice,err
+            return err
         }
-        _modelSlice = append(_modelSlice,ro)
+        frmt := fmt.Sprintf("UPDATE %s SET `stop_loss` = '%d', lock_version = lock_version + 1 WHERE `id` = '%d' AND lock_version = '%d'",o._table,_updStopLoss,o.Id,o.LockVersion)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        if affected == 0 {
+            return ErrStaleObject
+        }
+        o.StopLoss = _updStopLoss
+        o.IsStopLossDirty = true
+        return runCallbacks(o._adapter,`Position`,AfterSave,o)
+    })
+    if err != nil {
+        o.StopLoss, o.IsStopLossDirty = prev, prevDirty
+        return 0,err
     }
-
-    if len(_modelSlice) == 0 {
-        // there was an error!
-        return nil, o._adapter.Oops(`no results`)
+    o.IsStopLossDirty = false
+    o.LockVersion++
+    if o._orig != nil {
+        o._orig.StopLoss = _updStopLoss
+        o._orig.LockVersion = o.LockVersion
     }
-    return _modelSlice,nil
-
+    return affected,nil
 }
-// FindByBuy searchs against the database table field buy and will return []*Position,error
-// This method is a programatically generated finder for Position
-//
-//```go  
-//    m := NewPosition(a)
-//    results,err := m.FindByBuy(...)
-//    // handle err
-//    for i,r := results {
-//      // now r is an instance of Position
-//    }
-//```  
-//
-func (o *Position) FindByBuy(_findByBuy int) ([]*Position,error) {
 
-    var _modelSlice []*Position
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "buy", _findByBuy)
-    results, err := o._adapter.Query(q)
+// UpdateQuantity an immediate DB Query to update a single column, in this
+// case quantity. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Position) UpdateQuantity(_updQuantity int) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Quantity, o.IsQuantityDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`quantity`: auditValue(_updQuantity)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `quantity` = '%d', lock_version = lock_version + 1 WHERE `id` = '%d' AND lock_version = '%d'",o._table,_updQuantity,o.Id,o.LockVersion)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        if affected == 0 {
+            return ErrStaleObject
+        }
+        o.Quantity = _updQuantity
+        o.IsQuantityDirty = true
+        return runCallbacks(o._adapter,`Position`,AfterSave,o)
+    })
     if err != nil {
-        return _modelSlice,err
+        o.Quantity, o.IsQuantityDirty = prev, prevDirty
+        return 0,err
     }
-    
-    for _,result := range results {
-        ro := NewPosition(o._adapter)
-        err = ro.FromDBValueMap(result)
+    o.IsQuantityDirty = false
+    o.LockVersion++
+    if o._orig != nil {
+        o._orig.Quantity = _updQuantity
+        o._orig.LockVersion = o.LockVersion
+    }
+    return affected,nil
+}
+
+// UpdateLockVersion an immediate DB Query to update a single column, in this
+// case lock_version. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Position) UpdateLockVersion(_updLockVersion int) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.LockVersion, o.IsLockVersionDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`lock_version`: auditValue(_updLockVersion)})
         if err != nil {
-            return _modelSlice,err
+            return err
         }
-        _modelSlice = append(_modelSlice,ro)
+        frmt := fmt.Sprintf("UPDATE %s SET `lock_version` = '%d' WHERE `id` = '%d'",o._table,_updLockVersion,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.LockVersion = _updLockVersion
+        o.IsLockVersionDirty = true
+        return runCallbacks(o._adapter,`Position`,AfterSave,o)
+    })
+    if err != nil {
+        o.LockVersion, o.IsLockVersionDirty = prev, prevDirty
+        return 0,err
+    }
+    o.IsLockVersionDirty = false
+    if o._orig != nil {
+        o._orig.LockVersion = _updLockVersion
+    }
+    return affected,nil
+}
+
+// IsDirty returns true when a field was set through its setter since
+// the model was loaded or last saved
+func (o *Position) IsDirty() bool {
+    return len(o.DirtyFields()) > 0
+}
+// DirtyFields returns the columns set through their setters since the
+// model was loaded or last saved, in the order of the table
+func (o *Position) DirtyFields() []string {
+    var fields []string
+    if o.IsPortfolioIdDirty == true {
+        fields = append(fields,`portfolio_id`)
+    }
+    if o.IsSymbolDirty == true {
+        fields = append(fields,`symbol`)
+    }
+    if o.IsStartedAtDirty == true {
+        fields = append(fields,`started_at`)
+    }
+    if o.IsClosedAtDirty == true {
+        fields = append(fields,`closed_at`)
+    }
+    if o.IsPtypeDirty == true {
+        fields = append(fields,`ptype`)
     }
+    if o.IsBuyDirty == true {
+        fields = append(fields,`buy`)
+    }
+    if o.IsSellDirty == true {
+        fields = append(fields,`sell`)
+    }
+    if o.IsStopLossDirty == true {
+        fields = append(fields,`stop_loss`)
+    }
+    if o.IsQuantityDirty == true {
+        fields = append(fields,`quantity`)
+    }
+    if o.IsLockVersionDirty == true {
+        fields = append(fields,`lock_version`)
+    }
+    return fields
+}
+// ResetDirty clears the dirty markers and takes a snapshot of the
+// current values as the originals, it is called after loading and
+// saving. The snapshot is reused so loading rows into the same model
+// does not allocate a new one each time.
+func (o *Position) ResetDirty() {
+    o.IsPortfolioIdDirty = false
+    o.IsSymbolDirty = false
+    o.IsStartedAtDirty = false
+    o.IsClosedAtDirty = false
+    o.IsPtypeDirty = false
+    o.IsBuyDirty = false
+    o.IsSellDirty = false
+    o.IsStopLossDirty = false
+    o.IsQuantityDirty = false
+    o.IsLockVersionDirty = false
+    if o._orig == nil {
+        o._orig = &Position{}
+    }
+    orig := o._orig
+    *orig = *o
+    orig._orig = nil
+    if o.StartedAt != nil {
+        _StartedAt := *o.StartedAt
+        orig.StartedAt = &_StartedAt
+    }
+    if o.ClosedAt != nil {
+        _ClosedAt := *o.ClosedAt
+        orig.ClosedAt = &_ClosedAt
+    }
+}
+// Original returns a column as it was when the model was loaded or last
+// saved, formatted like the audit log, and false for new models
+func (o *Position) Original(column string) (string,bool) {
+    if o._orig == nil {
+        return ``,false
+    }
+    switch column {
+    case `portfolio_id`:
+        return auditValue(o._orig.PortfolioId),true
+    case `symbol`:
+        return auditValue(o._orig.Symbol),true
+    case `started_at`:
+        return auditValue(o._orig.StartedAt),true
+    case `closed_at`:
+        return auditValue(o._orig.ClosedAt),true
+    case `ptype`:
+        return auditValue(o._orig.Ptype),true
+    case `buy`:
+        return auditValue(o._orig.Buy),true
+    case `sell`:
+        return auditValue(o._orig.Sell),true
+    case `stop_loss`:
+        return auditValue(o._orig.StopLoss),true
+    case `quantity`:
+        return auditValue(o._orig.Quantity),true
+    case `lock_version`:
+        return auditValue(o._orig.LockVersion),true
+    }
+    return ``,false
+}
+
+// Setting is a Object Relational Mapping to
+// the database table that represents it. In this case it is
+// settings. The table name will be Sprintf'd to include
+// the prefix you define in your YAML configuration for the
+// Adapter.
+type Setting struct {
+    _table string
+    _adapter Adapter
+    _pkey string // 0 The name of the primary key in this table
+    _conds []string
+    _new bool
+    _orig *Setting
+
+    _select []string
+    _where []string
+    _cols []string
+    _values []string
+    _sets map[string]string
+    _limit string
+    _order string
+
+
+    Id int64
+    Skey string
+    Svalue string
+	// Dirty markers for smart updates
+    IsIdDirty bool
+    IsSkeyDirty bool
+    IsSvalueDirty bool
+	// Relationships
+}
+
+// NewSetting binds an Adapter to a new instance
+// of Setting and sets up the _table and primary keys
+func NewSetting(a Adapter) *Setting {
+    var o Setting
+    o._table = fmt.Sprintf("%ssettings",a.DatabasePrefix())
+    o._adapter = a
+    o._pkey = "id"
+    o._new = true
+    return &o
+}
+
+
+// GetPrimaryKeyValue returns the value, usually int64 of
+// the PrimaryKey
+func (o *Setting) GetPrimaryKeyValue() int64 {
+    return o.Id
+}
+// GetPrimaryKeyName returns the DB field name
+func (o *Setting) GetPrimaryKeyName() string {
+    return `id`
+}
 
-    if len(_modelSlice) == 0 {
-        // there was an error!
-        return nil, o._adapter.Oops(`no results`)
-    }
-    return _modelSlice,nil
+// GetId returns the value of 
+// Setting.Id
+func (o *Setting) GetId() int64 {
+    return o.Id
+}
+// SetId sets and marks as dirty the value of
+// Setting.Id
+func (o *Setting) SetId(arg int64) {
+    o.Id = arg
+    o.IsIdDirty = true
+}
 
+// GetSkey returns the value of 
+// Setting.Skey
+func (o *Setting) GetSkey() string {
+    return o.Skey
 }
-// FindBySell searchs against the database table field sell and will return []*Position,error
-// This method is a programatically generated finder for Position
+// SetSkey sets and marks as dirty the value of
+// Setting.Skey
+func (o *Setting) SetSkey(arg string) {
+    o.Skey = arg
+    o.IsSkeyDirty = true
+}
+
+// GetSvalue returns the value of 
+// Setting.Svalue
+func (o *Setting) GetSvalue() string {
+    return o.Svalue
+}
+// SetSvalue sets and marks as dirty the value of
+// Setting.Svalue
+func (o *Setting) SetSvalue(arg string) {
+    o.Svalue = arg
+    o.IsSvalueDirty = true
+}
+
+// Find searchs against the database table field id and will return bool,error
+// This method is a programatically generated finder for Setting
+//  
+// Note that Find returns a bool of true|false if found or not, not err, in the case of
+// found == true, the instance data will be filled out!
 //
-//```go  
-//    m := NewPosition(a)
-//    results,err := m.FindBySell(...)
-//    // handle err
-//    for i,r := results {
-//      // now r is an instance of Position
-//    }
-//```  
+// A call to find ALWAYS overwrites the model you call Find on
+// i.e. receiver is a pointer!
 //
-func (o *Position) FindBySell(_findBySell int) ([]*Position,error) {
+//```go
+//      m := NewSetting(a)
+//      found,err := m.Find(23)
+//      .. handle err
+//      if found == false {
+//          // handle found
+//      }
+//      ... do what you want with m here
+//```
+//
+func (o *Setting) Find(_findById int64) (bool,error) {
 
-    var _modelSlice []*Position
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "sell", _findBySell)
+    var _modelSlice []*Setting
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "id", _findById)
     results, err := o._adapter.Query(q)
     if err != nil {
-        return _modelSlice,err
+        return false,o._adapter.Oops(fmt.Sprintf(`%s`,err))
     }
     
     for _,result := range results {
-        ro := NewPosition(o._adapter)
+        ro := NewSetting(o._adapter)
         err = ro.FromDBValueMap(result)
         if err != nil {
-            return _modelSlice,err
+            return false,o._adapter.Oops(fmt.Sprintf(`%s`,err))
         }
         _modelSlice = append(_modelSlice,ro)
     }
 
     if len(_modelSlice) == 0 {
         // there was an error!
-        return nil, o._adapter.Oops(`no results`)
+        return false, o._adapter.Oops(`not found`)
     }
-    return _modelSlice,nil
+    o.FromSetting(_modelSlice[0])
+    o._new = false
+    o.ResetDirty()
+    return true,nil
 
 }
-// FindByStopLoss searchs against the database table field stop_loss and will return []*Position,error
-// This method is a programatically generated finder for Position
+// FindBySkey searchs against the database table field skey and will return []*Setting,error
+// This method is a programatically generated finder for Setting
 //
 //```go  
-//    m := NewPosition(a)
-//    results,err := m.FindByStopLoss(...)
+//    m := NewSetting(a)
+//    results,err := m.FindBySkey(...)
 //    // handle err
 //    for i,r := results {
-//      // now r is an instance of Position
+//      // now r is an instance of Setting
 //    }
 //```  
 //
-func (o *Position) FindByStopLoss(_findByStopLoss int) ([]*Position,error) {
+func (o *Setting) FindBySkey(_findBySkey string) ([]*Setting,error) {
 
-    var _modelSlice []*Position
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "stop_loss", _findByStopLoss)
+    var _modelSlice []*Setting
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "skey", _findBySkey)
     results, err := o._adapter.Query(q)
     if err != nil {
         return _modelSlice,err
     }
     
     for _,result := range results {
-        ro := NewPosition(o._adapter)
+        ro := NewSetting(o._adapter)
         err = ro.FromDBValueMap(result)
         if err != nil {
             return _modelSlice,err
@@ -2702,29 +7733,29 @@ func (o *Position) FindByStopLoss(_findByStopLoss int) ([]*Position,error) {
     return _modelSlice,nil
 
 }
-// FindByQuantity searchs against the database table field quantity and will return []*Position,error
-// This method is a programatically generated finder for Position
+// FindBySvalue searchs against the database table field svalue and will return []*Setting,error
+// This method is a programatically generated finder for Setting
 //
 //```go  
-//    m := NewPosition(a)
-//    results,err := m.FindByQuantity(...)
+//    m := NewSetting(a)
+//    results,err := m.FindBySvalue(...)
 //    // handle err
 //    for i,r := results {
-//      // now r is an instance of Position
+//      // now r is an instance of Setting
 //    }
 //```  
 //
-func (o *Position) FindByQuantity(_findByQuantity int) ([]*Position,error) {
+func (o *Setting) FindBySvalue(_findBySvalue string) ([]*Setting,error) {
 
-    var _modelSlice []*Position
-    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%d'",o._table, "quantity", _findByQuantity)
+    var _modelSlice []*Setting
+    q := fmt.Sprintf("SELECT * FROM %s WHERE `%s` = '%s'",o._table, "svalue", _findBySvalue)
     results, err := o._adapter.Query(q)
     if err != nil {
         return _modelSlice,err
     }
     
     for _,result := range results {
-        ro := NewPosition(o._adapter)
+        ro := NewSetting(o._adapter)
         err = ro.FromDBValueMap(result)
         if err != nil {
             return _modelSlice,err
@@ -2740,273 +7771,300 @@ func (o *Position) FindByQuantity(_findByQuantity int) ([]*Position,error) {
 
 }
 
-// FromDBValueMap Converts a DBValueMap returned from Adapter.Query to a Position
-func (o *Position) FromDBValueMap(m map[string]DBValue) error {
-	_Id,err := m["id"].AsInt64()
+// FromDBValueMap Converts a DBValueMap returned from Adapter.Query to a Setting
+func (o *Setting) FromDBValueMap(m map[string]DBValue) error {
+    return o.FromRow(DBValueMap(m))
+}
+// FromRow Converts a Row, e.g. from Adapter.QueryEach, to a Setting
+func (o *Setting) FromRow(r Row) error {
+	_Id,err := r.Get("id").AsInt64()
 	if err != nil {
  		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
 	}
 	o.Id = _Id
-	_PortfolioId,err := m["portfolio_id"].AsInt64()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.PortfolioId = _PortfolioId
-	_StartedAt,err := m["started_at"].AsDateTime()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.StartedAt = _StartedAt
-	_ClosedAt,err := m["closed_at"].AsDateTime()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.ClosedAt = _ClosedAt
-	_Ptype,err := m["ptype"].AsString()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.Ptype = _Ptype
-	_Buy,err := m["buy"].AsInt()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.Buy = _Buy
-	_Sell,err := m["sell"].AsInt()
-	if err != nil {
- 		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
-	}
-	o.Sell = _Sell
-	_StopLoss,err := m["stop_loss"].AsInt()
+	_Skey,err := r.Get("skey").AsString()
 	if err != nil {
  		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
 	}
-	o.StopLoss = _StopLoss
-	_Quantity,err := m["quantity"].AsInt()
+	o.Skey = _Skey
+	_Svalue,err := r.Get("svalue").AsString()
 	if err != nil {
  		return o._adapter.Oops(fmt.Sprintf(`%s`,err))
 	}
-	o.Quantity = _Quantity
+	o.Svalue = _Svalue
 
+    o._new = false
+    o.ResetDirty()
  	return nil
 }
-// FromPosition A kind of Clone function for Position
-func (o *Position) FromPosition(m *Position) {
+// FromSetting A kind of Clone function for Setting
+func (o *Setting) FromSetting(m *Setting) {
 	o.Id = m.Id
-	o.PortfolioId = m.PortfolioId
-	o.StartedAt = m.StartedAt
-	o.ClosedAt = m.ClosedAt
-	o.Ptype = m.Ptype
-	o.Buy = m.Buy
-	o.Sell = m.Sell
-	o.StopLoss = m.StopLoss
-	o.Quantity = m.Quantity
+	o.Skey = m.Skey
+	o.Svalue = m.Svalue
 
 }
-// Reload A function to forcibly reload Position
-func (o *Position) Reload() error {
+// Reload A function to forcibly reload Setting
+func (o *Setting) Reload() error {
     _,err := o.Find(o.GetPrimaryKeyValue())
     return err
 }
+// EachSetting runs f for every Setting q selects, reading the rows
+// one at a time with Adapter.QueryEach. The Setting given to f is
+// reused for the next row, copy it with FromSetting to keep it.
+func EachSetting(a Adapter, q string, f func(*Setting) error) error {
+    o := NewSetting(a)
+    return a.QueryEach(q,func(r Row) error {
+        err := o.FromRow(r)
+        if err != nil {
+            return err
+        }
+        return f(o)
+    })
+}
 
+// transaction runs f with the model bound to a transaction of its
+// Adapter, see Adapter.Transaction
+func (o *Setting) transaction(f func() error) error {
+    _adapter := o._adapter
+    defer func() { o._adapter = _adapter }()
+    return _adapter.Transaction(func(a Adapter) error {
+        o._adapter = a
+        return f()
+    })
+}
 // Save is a dynamic saver 'inherited' by all models
-func (o *Position) Save() error {
+func (o *Setting) Save() error {
     if o._new == true {
         return o.Create()
     }
-    var sets []string
-    
-    if o.IsPortfolioIdDirty == true {
-        sets = append(sets,fmt.Sprintf(`portfolio_id = '%d'`,o.PortfolioId))
-    }
-
-    if o.IsStartedAtDirty == true {
-        sets = append(sets,fmt.Sprintf(`started_at = '%s'`,o.StartedAt))
-    }
-
-    if o.IsClosedAtDirty == true {
-        sets = append(sets,fmt.Sprintf(`closed_at = '%s'`,o.ClosedAt))
-    }
-
-    if o.IsPtypeDirty == true {
-        sets = append(sets,fmt.Sprintf(`ptype = '%s'`,o._adapter.SafeString(o.Ptype)))
-    }
-
-    if o.IsBuyDirty == true {
-        sets = append(sets,fmt.Sprintf(`buy = '%d'`,o.Buy))
-    }
-
-    if o.IsSellDirty == true {
-        sets = append(sets,fmt.Sprintf(`sell = '%d'`,o.Sell))
-    }
-
-    if o.IsStopLossDirty == true {
-        sets = append(sets,fmt.Sprintf(`stop_loss = '%d'`,o.StopLoss))
-    }
-
-    if o.IsQuantityDirty == true {
-        sets = append(sets,fmt.Sprintf(`quantity = '%d'`,o.Quantity))
-    }
-
-    frmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s = '%d'",o._table,strings.Join(sets,`,`),o._pkey, o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return err
-    }
-    return nil
+    return o.Update()
 }
 // Update is a dynamic updater, it considers whether or not
 // a field is 'dirty' and needs to be updated. Will only work
-// if you use the Getters and Setters
-func (o *Position) Update() error {
-    var sets []string
-    
-    if o.IsPortfolioIdDirty == true {
-        sets = append(sets,fmt.Sprintf(`portfolio_id = '%d'`,o.PortfolioId))
-    }
-
-    if o.IsStartedAtDirty == true {
-        sets = append(sets,fmt.Sprintf(`started_at = '%s'`,o.StartedAt))
-    }
-
-    if o.IsClosedAtDirty == true {
-        sets = append(sets,fmt.Sprintf(`closed_at = '%s'`,o.ClosedAt))
-    }
-
-    if o.IsPtypeDirty == true {
-        sets = append(sets,fmt.Sprintf(`ptype = '%s'`,o._adapter.SafeString(o.Ptype)))
-    }
-
-    if o.IsBuyDirty == true {
-        sets = append(sets,fmt.Sprintf(`buy = '%d'`,o.Buy))
-    }
-
-    if o.IsSellDirty == true {
-        sets = append(sets,fmt.Sprintf(`sell = '%d'`,o.Sell))
-    }
-
-    if o.IsStopLossDirty == true {
-        sets = append(sets,fmt.Sprintf(`stop_loss = '%d'`,o.StopLoss))
-    }
+// if you use the Getters and Setters, a model with nothing dirty
+// is not written at all
+func (o *Setting) Update() error {
+    if o.IsDirty() == false {
+        return nil
+    }
+    err := o.transaction(func() error {
+        err := runCallbacks(o._adapter,`Setting`,BeforeSave,o)
+        if err != nil {
+            return err
+        }
+        err = o.Validate()
+        if err != nil {
+            return err
+        }
+        var sets []string
+        changes := make(map[string]string)
+        
+        if o.IsSkeyDirty == true {
+            sets = append(sets,fmt.Sprintf(`skey = '%s'`,o._adapter.SafeString(o.Skey)))
+            changes[`skey`] = auditValue(o.Skey)
+        }
 
-    if o.IsQuantityDirty == true {
-        sets = append(sets,fmt.Sprintf(`quantity = '%d'`,o.Quantity))
-    }
+        if o.IsSvalueDirty == true {
+            sets = append(sets,fmt.Sprintf(`svalue = '%s'`,o._adapter.SafeString(o.Svalue)))
+            changes[`svalue`] = auditValue(o.Svalue)
+        }
 
-    frmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s = '%d'",o._table,strings.Join(sets,`,`),o._pkey, o.Id)
-    err := o._adapter.Execute(frmt)
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,changes)
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s = '%d'",o._table,strings.Join(sets,`,`),o._pkey, o.Id)
+        _, err = o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        return runCallbacks(o._adapter,`Setting`,AfterSave,o)
+    })
     if err != nil {
         return err
     }
+    o.ResetDirty()
     return nil
 }
 // Create inserts the model. Calling Save will call this function
 // automatically for new models
-func (o *Position) Create() error {
-    frmt := fmt.Sprintf("INSERT INTO %s (`portfolio_id`, `started_at`, `closed_at`, `ptype`, `buy`, `sell`, `stop_loss`, `quantity`) VALUES ('%d', '%s', '%s', '%s', '%d', '%d', '%d', '%d')",o._table,o.PortfolioId, o.StartedAt.ToString(), o.ClosedAt.ToString(), o.Ptype, o.Buy, o.Sell, o.StopLoss, o.Quantity)
-    err := o._adapter.Execute(frmt)
+func (o *Setting) Create() error {
+    err := o.transaction(func() error {
+        err := runCallbacks(o._adapter,`Setting`,BeforeSave,o)
+        if err != nil {
+            return err
+        }
+        err = runCallbacks(o._adapter,`Setting`,BeforeCreate,o)
+        if err != nil {
+            return err
+        }
+        err = o.Validate()
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("INSERT INTO %s (`skey`, `svalue`) VALUES ('%s', '%s')",o._table,o.Skey, o.Svalue)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return o._adapter.Oops(fmt.Sprintf(`%s led to %s`,frmt,err))
+        }
+        o.Id = res.LastInsertId
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditCreate,map[string]string{
+            `skey`: auditValue(o.Skey),
+            `svalue`: auditValue(o.Svalue),
+        })
+        if err != nil {
+            return err
+        }
+        err = runCallbacks(o._adapter,`Setting`,AfterCreate,o)
+        if err != nil {
+            return err
+        }
+        return runCallbacks(o._adapter,`Setting`,AfterSave,o)
+    })
     if err != nil {
-        return o._adapter.Oops(fmt.Sprintf(`%s led to %s`,frmt,err))
+        return err
     }
-    o.Id = o._adapter.LastInsertedId()
     o._new = false
+    o.ResetDirty()
     return nil
 }
-
-
-// UpdatePortfolioId an immediate DB Query to update a single column, in this
-// case portfolio_id
-func (o *Position) UpdatePortfolioId(_updPortfolioId int64) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `portfolio_id` = '%d' WHERE `id` = '%d'",o._table,_updPortfolioId,o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return 0,err
-    }
-    o.PortfolioId = _updPortfolioId
-    return o._adapter.AffectedRows(),nil
-}
-
-// UpdateStartedAt an immediate DB Query to update a single column, in this
-// case started_at
-func (o *Position) UpdateStartedAt(_updStartedAt *DateTime) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `started_at` = '%s' WHERE `id` = '%d'",o._table,_updStartedAt,o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return 0,err
-    }
-    o.StartedAt = _updStartedAt
-    return o._adapter.AffectedRows(),nil
-}
-
-// UpdateClosedAt an immediate DB Query to update a single column, in this
-// case closed_at
-func (o *Position) UpdateClosedAt(_updClosedAt *DateTime) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `closed_at` = '%s' WHERE `id` = '%d'",o._table,_updClosedAt,o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return 0,err
-    }
-    o.ClosedAt = _updClosedAt
-    return o._adapter.AffectedRows(),nil
+// Delete removes the model from the database
+func (o *Setting) Delete() error {
+    return o.transaction(func() error {
+        err := runCallbacks(o._adapter,`Setting`,BeforeDelete,o)
+        if err != nil {
+            return err
+        }
+        err = recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditDelete,nil)
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("DELETE FROM %s WHERE %s = '%d'",o._table,o._pkey, o.Id)
+        _, err = o._adapter.Execute(frmt)
+        return err
+    })
 }
 
-// UpdatePtype an immediate DB Query to update a single column, in this
-// case ptype
-func (o *Position) UpdatePtype(_updPtype string) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `ptype` = '%s' WHERE `id` = '%d'",o._table,_updPtype,o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return 0,err
+// Validate checks the model against the constraints of the
+// settings table and then runs the validators registered for
+// Setting. Create and Save call it before writing.
+func (o *Setting) Validate() error {
+    errs := make(ValidationErrors)
+    if len(o.Skey) > 255 {
+        errs.Add(`skey`,`is longer than 255`)
     }
-    o.Ptype = _updPtype
-    return o._adapter.AffectedRows(),nil
+    return runValidators(o._adapter,`Setting`,o,errs)
 }
 
-// UpdateBuy an immediate DB Query to update a single column, in this
-// case buy
-func (o *Position) UpdateBuy(_updBuy int) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `buy` = '%d' WHERE `id` = '%d'",o._table,_updBuy,o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return 0,err
-    }
-    o.Buy = _updBuy
-    return o._adapter.AffectedRows(),nil
-}
 
-// UpdateSell an immediate DB Query to update a single column, in this
-// case sell
-func (o *Position) UpdateSell(_updSell int) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `sell` = '%d' WHERE `id` = '%d'",o._table,_updSell,o.Id)
-    err := o._adapter.Execute(frmt)
+// UpdateSkey an immediate DB Query to update a single column, in this
+// case skey. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Setting) UpdateSkey(_updSkey string) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Skey, o.IsSkeyDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`skey`: auditValue(_updSkey)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `skey` = '%s' WHERE `id` = '%d'",o._table,_updSkey,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.Skey = _updSkey
+        o.IsSkeyDirty = true
+        return runCallbacks(o._adapter,`Setting`,AfterSave,o)
+    })
     if err != nil {
+        o.Skey, o.IsSkeyDirty = prev, prevDirty
         return 0,err
     }
-    o.Sell = _updSell
-    return o._adapter.AffectedRows(),nil
-}
-
-// UpdateStopLoss an immediate DB Query to update a single column, in this
-// case stop_loss
-func (o *Position) UpdateStopLoss(_updStopLoss int) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `stop_loss` = '%d' WHERE `id` = '%d'",o._table,_updStopLoss,o.Id)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return 0,err
+    o.IsSkeyDirty = false
+    if o._orig != nil {
+        o._orig.Skey = _updSkey
     }
-    o.StopLoss = _updStopLoss
-    return o._adapter.AffectedRows(),nil
+    return affected,nil
 }
 
-// UpdateQuantity an immediate DB Query to update a single column, in this
-// case quantity
-func (o *Position) UpdateQuantity(_updQuantity int) (int64,error) {
-    frmt := fmt.Sprintf("UPDATE %s SET `quantity` = '%d' WHERE `id` = '%d'",o._table,_updQuantity,o.Id)
-    err := o._adapter.Execute(frmt)
+// UpdateSvalue an immediate DB Query to update a single column, in this
+// case svalue. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
+func (o *Setting) UpdateSvalue(_updSvalue string) (int64,error) {
+    var affected int64
+    prev, prevDirty := o.Svalue, o.IsSvalueDirty
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.Id,AuditUpdate,map[string]string{`svalue`: auditValue(_updSvalue)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf("UPDATE %s SET `svalue` = '%s' WHERE `id` = '%d'",o._table,_updSvalue,o.Id)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected
+        o.Svalue = _updSvalue
+        o.IsSvalueDirty = true
+        return runCallbacks(o._adapter,`Setting`,AfterSave,o)
+    })
     if err != nil {
+        o.Svalue, o.IsSvalueDirty = prev, prevDirty
         return 0,err
     }
-    o.Quantity = _updQuantity
-    return o._adapter.AffectedRows(),nil
+    o.IsSvalueDirty = false
+    if o._orig != nil {
+        o._orig.Svalue = _updSvalue
+    }
+    return affected,nil
+}
+
+// IsDirty returns true when a field was set through its setter since
+// the model was loaded or last saved
+func (o *Setting) IsDirty() bool {
+    return len(o.DirtyFields()) > 0
+}
+// DirtyFields returns the columns set through their setters since the
+// model was loaded or last saved, in the order of the table
+func (o *Setting) DirtyFields() []string {
+    var fields []string
+    if o.IsSkeyDirty == true {
+        fields = append(fields,`skey`)
+    }
+    if o.IsSvalueDirty == true {
+        fields = append(fields,`svalue`)
+    }
+    return fields
+}
+// ResetDirty clears the dirty markers and takes a snapshot of the
+// current values as the originals, it is called after loading and
+// saving. The snapshot is reused so loading rows into the same model
+// does not allocate a new one each time.
+func (o *Setting) ResetDirty() {
+    o.IsSkeyDirty = false
+    o.IsSvalueDirty = false
+    if o._orig == nil {
+        o._orig = &Setting{}
+    }
+    orig := o._orig
+    *orig = *o
+    orig._orig = nil
+}
+// Original returns a column as it was when the model was loaded or last
+// saved, formatted like the audit log, and false for new models
+func (o *Setting) Original(column string) (string,bool) {
+    if o._orig == nil {
+        return ``,false
+    }
+    switch column {
+    case `skey`:
+        return auditValue(o._orig.Skey),true
+    case `svalue`:
+        return auditValue(o._orig.Svalue),true
+    }
+    return ``,false
 }
 
@@ -48,6 +48,429 @@ func randomDateTime(a Adapter) *DateTime {
 }
 
 
+func TestNewAuditLog(t *testing.T) {
+    a := NewMysqlAdapter(``)
+    o := NewAuditLog(a)
+    if o._table != "audit_log" {
+        t.Errorf("failed creating %+v",o);
+        return
+    }
+}
+func TestAuditLogFromDBValueMap(t *testing.T) {
+    a := NewMysqlAdapter(``)
+    o := NewAuditLog(a)
+    m := make(map[string]DBValue)
+	m["id"] = a.NewDBValue()
+	m["id"].SetInternalValue("id",strconv.Itoa(999))
+	m["table_name"] = a.NewDBValue()
+	m["table_name"].SetInternalValue("table_name","AString")
+	m["row_id"] = a.NewDBValue()
+	m["row_id"].SetInternalValue("row_id",strconv.Itoa(999))
+	m["action"] = a.NewDBValue()
+	m["action"].SetInternalValue("action","AString")
+	m["changes"] = a.NewDBValue()
+	m["changes"].SetInternalValue("changes","AString")
+	m["changed_by"] = a.NewDBValue()
+	m["changed_by"].SetInternalValue("changed_by","AString")
+	m["changed_at"] = a.NewDBValue()
+	m["changed_at"].SetInternalValue("changed_at","2016-01-01 10:50:23")
+
+    err := o.FromDBValueMap(m)
+    if err != nil {
+        t.Errorf("FromDBValueMap failed %s",err)
+    }
+
+    if o.Id != 999 {
+        t.Errorf("o.Id test failed %+v",o)
+        return
+    }    
+
+    if o.TableName != "AString" {
+        t.Errorf("o.TableName test failed %+v",o)
+        return
+    }    
+
+    if o.RowId != 999 {
+        t.Errorf("o.RowId test failed %+v",o)
+        return
+    }    
+
+    if o.Action != "AString" {
+        t.Errorf("o.Action test failed %+v",o)
+        return
+    }    
+
+    if o.Changes != "AString" {
+        t.Errorf("o.Changes test failed %+v",o)
+        return
+    }    
+
+    if o.ChangedBy != "AString" {
+        t.Errorf("o.ChangedBy test failed %+v",o)
+        return
+    }    
+
+    if o.ChangedAt.Year != 2016 {
+        t.Errorf("year not set for %+v",o.ChangedAt)
+        return
+    }
+    if (o.ChangedAt.Year != 2016 || 
+        o.ChangedAt.Month != 1 ||
+        o.ChangedAt.Day != 1 ||
+        o.ChangedAt.Hours != 10 ||
+        o.ChangedAt.Minutes != 50 ||
+        o.ChangedAt.Seconds != 23 ) {
+        t.Errorf(`fields don't match up for %+v`,o.ChangedAt)
+    }
+    r6,_ := m["changed_at"].AsString()
+    if o.ChangedAt.ToString() != r6 {
+        t.Errorf(`restring of o.ChangedAt failed %s`,o.ChangedAt.ToString())
+    }
+}
+
+func TestAuditLogCreate(t *testing.T) {
+    if fileExists(`../gopaper-testing.db.yml`) {
+    a,err := NewMysqlAdapterEx(`../gopaper-testing.db.yml`)
+    defer a.Close()
+    if err != nil {
+        t.Errorf(`could not load ../gopaper-testing.db.yml %s`,err)
+        return
+    }
+    file, err := os.OpenFile("adapter.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+    if err != nil {
+        t.Errorf(" Failed to open log file %s", err)
+    }
+    a.SetLogs(file)
+    model := NewAuditLog(a)
+model.TableName = randomString(19)
+model.RowId = int64(randomInteger())
+model.Action = randomString(15)
+model.Changes = randomString(25)
+model.ChangedBy = randomString(19)
+model.ChangedAt = randomDateTime(a)
+
+    err = model.Create()
+    if err != nil {
+        t.Errorf(` failed to create model %s`,err)
+        return
+    }
+
+    model2 := NewAuditLog(a)
+    found,err := model2.Find(model.GetPrimaryKeyValue())
+    if err != nil {
+        t.Errorf(` did not find record for %s = %d because of %s`,model.GetPrimaryKeyName(),model.GetPrimaryKeyValue(),err)
+        return
+    }
+    if found == false {
+        t.Errorf(` did not find record for %s = %d because of %s`,model.GetPrimaryKeyName(),model.GetPrimaryKeyValue(),err)
+        return
+    }
+
+
+    if model.TableName != model2.TableName {
+        t.Errorf(` model.TableName[%s] != model2.TableName[%s]`,model.TableName,model2.TableName)
+        return
+    }
+
+    if model.RowId != model2.RowId {
+        t.Errorf(` model.RowId[%d] != model2.RowId[%d]`,model.RowId,model2.RowId)
+        return
+    }
+
+    if model.Action != model2.Action {
+        t.Errorf(` model.Action[%s] != model2.Action[%s]`,model.Action,model2.Action)
+        return
+    }
+
+    if model.Changes != model2.Changes {
+        t.Errorf(` model.Changes[%s] != model2.Changes[%s]`,model.Changes,model2.Changes)
+        return
+    }
+
+    if model.ChangedBy != model2.ChangedBy {
+        t.Errorf(` model.ChangedBy[%s] != model2.ChangedBy[%s]`,model.ChangedBy,model2.ChangedBy)
+        return
+    }
+
+    if (model.ChangedAt.Year != model2.ChangedAt.Year ||
+        model.ChangedAt.Month != model2.ChangedAt.Month ||
+        model.ChangedAt.Day != model2.ChangedAt.Day ||
+        model.ChangedAt.Hours != model2.ChangedAt.Hours ||
+        model.ChangedAt.Minutes != model2.ChangedAt.Minutes ||
+        model.ChangedAt.Seconds != model2.ChangedAt.Seconds ) {
+        t.Errorf(`2: model.ChangedAt != model2.ChangedAt %+v --- %+v`,model.ChangedAt,model2.ChangedAt)
+        return
+    }
+model2.SetTableName(randomString(19))
+model2.SetRowId(int64(randomInteger()))
+model2.SetAction(randomString(15))
+model2.SetChanges(randomString(25))
+model2.SetChangedBy(randomString(19))
+model2.SetChangedAt(randomDateTime(a))
+
+    err = model2.Save()
+    if err != nil {
+        t.Errorf(`failed to save model2 %s`,err)
+    }
+
+    if model.TableName == model2.TableName {
+        t.Errorf(`1: model.TableName[%s] != model2.TableName[%s]`,model.TableName,model2.TableName)
+        return
+    }
+
+    if model.RowId == model2.RowId {
+        t.Errorf(`1: model.RowId[%d] != model2.RowId[%d]`,model.RowId,model2.RowId)
+        return
+    }
+
+    if model.Action == model2.Action {
+        t.Errorf(`1: model.Action[%s] != model2.Action[%s]`,model.Action,model2.Action)
+        return
+    }
+
+    if model.Changes == model2.Changes {
+        t.Errorf(`1: model.Changes[%s] != model2.Changes[%s]`,model.Changes,model2.Changes)
+        return
+    }
+
+    if model.ChangedBy == model2.ChangedBy {
+        t.Errorf(`1: model.ChangedBy[%s] != model2.ChangedBy[%s]`,model.ChangedBy,model2.ChangedBy)
+        return
+    }
+
+    if (model.ChangedAt.Year == model2.ChangedAt.Year) {
+        t.Errorf(` model.ChangedAt.Year == model2.ChangedAt but should not!`)
+        return
+    }
+
+    res16,err := model.FindByTableName(model2.GetTableName())
+    if err != nil {
+        t.Errorf(`failed model.FindByTableName(model2.GetTableName())`)
+    }
+    if len(res16) == 0 {
+        t.Errorf(`failed to find any AuditLog`)
+    }
+
+    res17,err := model.FindByRowId(model2.GetRowId())
+    if err != nil {
+        t.Errorf(`failed model.FindByRowId(model2.GetRowId())`)
+    }
+    if len(res17) == 0 {
+        t.Errorf(`failed to find any AuditLog`)
+    }
+
+    res18,err := model.FindByAction(model2.GetAction())
+    if err != nil {
+        t.Errorf(`failed model.FindByAction(model2.GetAction())`)
+    }
+    if len(res18) == 0 {
+        t.Errorf(`failed to find any AuditLog`)
+    }
+
+    res19,err := model.FindByChanges(model2.GetChanges())
+    if err != nil {
+        t.Errorf(`failed model.FindByChanges(model2.GetChanges())`)
+    }
+    if len(res19) == 0 {
+        t.Errorf(`failed to find any AuditLog`)
+    }
+
+    res20,err := model.FindByChangedBy(model2.GetChangedBy())
+    if err != nil {
+        t.Errorf(`failed model.FindByChangedBy(model2.GetChangedBy())`)
+    }
+    if len(res20) == 0 {
+        t.Errorf(`failed to find any AuditLog`)
+    }
+
+    res21,err := model.FindByChangedAt(model2.GetChangedAt())
+    if err != nil {
+        t.Errorf(`failed model.FindByChangedAt(model2.GetChangedAt())`)
+    }
+    if len(res21) == 0 {
+        t.Errorf(`failed to find any AuditLog`)
+    }
+} // end of if fileExists
+};
+
+
+func TestAuditLogUpdaters(t *testing.T) {
+    if fileExists(`../gopaper-testing.db.yml`) == false {
+        return
+    }
+    a,err := NewMysqlAdapterEx(`../gopaper-testing.db.yml`)
+    defer a.Close()
+    if err != nil {
+        t.Errorf(`could not load ../gopaper-testing.db.yml %s`,err)
+        return
+    }
+    file, err := os.OpenFile("adapter.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+    if err != nil {
+        t.Errorf("Failed to open log file %s", err)
+        return
+    }
+    a.SetLogs(file)
+    model := NewAuditLog(a)
+
+    model.SetTableName(randomString(19))
+    if model.GetTableName() != model.TableName {
+        t.Errorf(`AuditLog.GetTableName() != AuditLog.TableName`)
+    }
+    if model.IsTableNameDirty != true {
+        t.Errorf(`AuditLog.IsTableNameDirty != true`)
+        return
+    }
+    
+    u0 := randomString(19)
+    _,err = model.UpdateTableName(u0)
+    if err != nil {
+        t.Errorf(`failed UpdateTableName(u0) %s`,err)
+        return
+    }
+
+    if model.GetTableName() != u0 {
+        t.Errorf(`AuditLog.GetTableName() != u0 after UpdateTableName`)
+        return
+    }
+    model.Reload()
+    if model.GetTableName() != u0 {
+        t.Errorf(`AuditLog.GetTableName() != u0 after Reload`)
+        return
+    }
+
+    model.SetRowId(int64(randomInteger()))
+    if model.GetRowId() != model.RowId {
+        t.Errorf(`AuditLog.GetRowId() != AuditLog.RowId`)
+    }
+    if model.IsRowIdDirty != true {
+        t.Errorf(`AuditLog.IsRowIdDirty != true`)
+        return
+    }
+    
+    u1 := int64(randomInteger())
+    _,err = model.UpdateRowId(u1)
+    if err != nil {
+        t.Errorf(`failed UpdateRowId(u1) %s`,err)
+        return
+    }
+
+    if model.GetRowId() != u1 {
+        t.Errorf(`AuditLog.GetRowId() != u1 after UpdateRowId`)
+        return
+    }
+    model.Reload()
+    if model.GetRowId() != u1 {
+        t.Errorf(`AuditLog.GetRowId() != u1 after Reload`)
+        return
+    }
+
+    model.SetAction(randomString(15))
+    if model.GetAction() != model.Action {
+        t.Errorf(`AuditLog.GetAction() != AuditLog.Action`)
+    }
+    if model.IsActionDirty != true {
+        t.Errorf(`AuditLog.IsActionDirty != true`)
+        return
+    }
+    
+    u2 := randomString(15)
+    _,err = model.UpdateAction(u2)
+    if err != nil {
+        t.Errorf(`failed UpdateAction(u2) %s`,err)
+        return
+    }
+
+    if model.GetAction() != u2 {
+        t.Errorf(`AuditLog.GetAction() != u2 after UpdateAction`)
+        return
+    }
+    model.Reload()
+    if model.GetAction() != u2 {
+        t.Errorf(`AuditLog.GetAction() != u2 after Reload`)
+        return
+    }
+
+    model.SetChanges(randomString(25))
+    if model.GetChanges() != model.Changes {
+        t.Errorf(`AuditLog.GetChanges() != AuditLog.Changes`)
+    }
+    if model.IsChangesDirty != true {
+        t.Errorf(`AuditLog.IsChangesDirty != true`)
+        return
+    }
+    
+    u3 := randomString(25)
+    _,err = model.UpdateChanges(u3)
+    if err != nil {
+        t.Errorf(`failed UpdateChanges(u3) %s`,err)
+        return
+    }
+
+    if model.GetChanges() != u3 {
+        t.Errorf(`AuditLog.GetChanges() != u3 after UpdateChanges`)
+        return
+    }
+    model.Reload()
+    if model.GetChanges() != u3 {
+        t.Errorf(`AuditLog.GetChanges() != u3 after Reload`)
+        return
+    }
+
+    model.SetChangedBy(randomString(19))
+    if model.GetChangedBy() != model.ChangedBy {
+        t.Errorf(`AuditLog.GetChangedBy() != AuditLog.ChangedBy`)
+    }
+    if model.IsChangedByDirty != true {
+        t.Errorf(`AuditLog.IsChangedByDirty != true`)
+        return
+    }
+    
+    u4 := randomString(19)
+    _,err = model.UpdateChangedBy(u4)
+    if err != nil {
+        t.Errorf(`failed UpdateChangedBy(u4) %s`,err)
+        return
+    }
+
+    if model.GetChangedBy() != u4 {
+        t.Errorf(`AuditLog.GetChangedBy() != u4 after UpdateChangedBy`)
+        return
+    }
+    model.Reload()
+    if model.GetChangedBy() != u4 {
+        t.Errorf(`AuditLog.GetChangedBy() != u4 after Reload`)
+        return
+    }
+
+    model.SetChangedAt(randomDateTime(a))
+    if model.GetChangedAt() != model.ChangedAt {
+        t.Errorf(`AuditLog.GetChangedAt() != AuditLog.ChangedAt`)
+    }
+    if model.IsChangedAtDirty != true {
+        t.Errorf(`AuditLog.IsChangedAtDirty != true`)
+        return
+    }
+    
+    u5 := randomDateTime(a)
+    _,err = model.UpdateChangedAt(u5)
+    if err != nil {
+        t.Errorf(`failed UpdateChangedAt(u5) %s`,err)
+        return
+    }
+
+    if model.GetChangedAt() != u5 {
+        t.Errorf(`AuditLog.GetChangedAt() != u5 after UpdateChangedAt`)
+        return
+    }
+    model.Reload()
+    if model.GetChangedAt() != u5 {
+        t.Errorf(`AuditLog.GetChangedAt() != u5 after Reload`)
+        return
+    }
+
+};
+
+
 func TestNewNote(t *testing.T) {
     a := NewMysqlAdapter(``)
     o := NewNote(a)
@@ -930,25 +1353,395 @@ func TestPlayUpdaters(t *testing.T) {
 };
 
 
-func TestNewPortfolio(t *testing.T) {
+func TestNewPortfolioSnapshot(t *testing.T) {
     a := NewMysqlAdapter(``)
-    o := NewPortfolio(a)
-    if o._table != "portfolios" {
+    o := NewPortfolioSnapshot(a)
+    if o._table != "portfolio_snapshots" {
         t.Errorf("failed creating %+v",o);
         return
     }
 }
-func TestPortfolioFromDBValueMap(t *testing.T) {
+func TestPortfolioSnapshotFromDBValueMap(t *testing.T) {
     a := NewMysqlAdapter(``)
-    o := NewPortfolio(a)
+    o := NewPortfolioSnapshot(a)
     m := make(map[string]DBValue)
 	m["id"] = a.NewDBValue()
 	m["id"].SetInternalValue("id",strconv.Itoa(999))
-	m["name"] = a.NewDBValue()
-	m["name"].SetInternalValue("name","AString")
-	m["description"] = a.NewDBValue()
-	m["description"].SetInternalValue("description","AString")
-	m["value"] = a.NewDBValue()
+	m["portfolio_id"] = a.NewDBValue()
+	m["portfolio_id"].SetInternalValue("portfolio_id",strconv.Itoa(999))
+	m["day"] = a.NewDBValue()
+	m["day"].SetInternalValue("day","2016-01-01 10:50:23")
+	m["cash"] = a.NewDBValue()
+	m["cash"].SetInternalValue("cash",strconv.Itoa(999))
+	m["market_value"] = a.NewDBValue()
+	m["market_value"].SetInternalValue("market_value",strconv.Itoa(999))
+	m["open_positions"] = a.NewDBValue()
+	m["open_positions"].SetInternalValue("open_positions",strconv.Itoa(999))
+
+    err := o.FromDBValueMap(m)
+    if err != nil {
+        t.Errorf("FromDBValueMap failed %s",err)
+    }
+
+    if o.Id != 999 {
+        t.Errorf("o.Id test failed %+v",o)
+        return
+    }    
+
+    if o.PortfolioId != 999 {
+        t.Errorf("o.PortfolioId test failed %+v",o)
+        return
+    }    
+
+    if o.Day.Year != 2016 {
+        t.Errorf("year not set for %+v",o.Day)
+        return
+    }
+    if (o.Day.Year != 2016 || 
+        o.Day.Month != 1 ||
+        o.Day.Day != 1 ||
+        o.Day.Hours != 10 ||
+        o.Day.Minutes != 50 ||
+        o.Day.Seconds != 23 ) {
+        t.Errorf(`fields don't match up for %+v`,o.Day)
+    }
+    r2,_ := m["day"].AsString()
+    if o.Day.ToString() != r2 {
+        t.Errorf(`restring of o.Day failed %s`,o.Day.ToString())
+    }
+
+    if o.Cash != 999 {
+        t.Errorf("o.Cash test failed %+v",o)
+        return
+    }    
+
+    if o.MarketValue != 999 {
+        t.Errorf("o.MarketValue test failed %+v",o)
+        return
+    }    
+
+    if o.OpenPositions != 999 {
+        t.Errorf("o.OpenPositions test failed %+v",o)
+        return
+    }    
+}
+
+func TestPortfolioSnapshotCreate(t *testing.T) {
+    if fileExists(`../gopaper-testing.db.yml`) {
+    a,err := NewMysqlAdapterEx(`../gopaper-testing.db.yml`)
+    defer a.Close()
+    if err != nil {
+        t.Errorf(`could not load ../gopaper-testing.db.yml %s`,err)
+        return
+    }
+    file, err := os.OpenFile("adapter.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+    if err != nil {
+        t.Errorf(" Failed to open log file %s", err)
+    }
+    a.SetLogs(file)
+    model := NewPortfolioSnapshot(a)
+model.PortfolioId = int64(randomInteger())
+model.Day = randomDateTime(a)
+model.Cash = int(randomInteger())
+model.MarketValue = int(randomInteger())
+model.OpenPositions = int(randomInteger())
+
+    err = model.Create()
+    if err != nil {
+        t.Errorf(` failed to create model %s`,err)
+        return
+    }
+
+    model2 := NewPortfolioSnapshot(a)
+    found,err := model2.Find(model.GetPrimaryKeyValue())
+    if err != nil {
+        t.Errorf(` did not find record for %s = %d because of %s`,model.GetPrimaryKeyName(),model.GetPrimaryKeyValue(),err)
+        return
+    }
+    if found == false {
+        t.Errorf(` did not find record for %s = %d because of %s`,model.GetPrimaryKeyName(),model.GetPrimaryKeyValue(),err)
+        return
+    }
+
+
+    if model.PortfolioId != model2.PortfolioId {
+        t.Errorf(` model.PortfolioId[%d] != model2.PortfolioId[%d]`,model.PortfolioId,model2.PortfolioId)
+        return
+    }
+
+    if (model.Day.Year != model2.Day.Year ||
+        model.Day.Month != model2.Day.Month ||
+        model.Day.Day != model2.Day.Day ||
+        model.Day.Hours != model2.Day.Hours ||
+        model.Day.Minutes != model2.Day.Minutes ||
+        model.Day.Seconds != model2.Day.Seconds ) {
+        t.Errorf(`2: model.Day != model2.Day %+v --- %+v`,model.Day,model2.Day)
+        return
+    }
+
+    if model.Cash != model2.Cash {
+        t.Errorf(` model.Cash[%d] != model2.Cash[%d]`,model.Cash,model2.Cash)
+        return
+    }
+
+    if model.MarketValue != model2.MarketValue {
+        t.Errorf(` model.MarketValue[%d] != model2.MarketValue[%d]`,model.MarketValue,model2.MarketValue)
+        return
+    }
+
+    if model.OpenPositions != model2.OpenPositions {
+        t.Errorf(` model.OpenPositions[%d] != model2.OpenPositions[%d]`,model.OpenPositions,model2.OpenPositions)
+        return
+    }
+model2.SetPortfolioId(int64(randomInteger()))
+model2.SetDay(randomDateTime(a))
+model2.SetCash(int(randomInteger()))
+model2.SetMarketValue(int(randomInteger()))
+model2.SetOpenPositions(int(randomInteger()))
+
+    err = model2.Save()
+    if err != nil {
+        t.Errorf(`failed to save model2 %s`,err)
+    }
+
+    if model.PortfolioId == model2.PortfolioId {
+        t.Errorf(`1: model.PortfolioId[%d] != model2.PortfolioId[%d]`,model.PortfolioId,model2.PortfolioId)
+        return
+    }
+
+    if (model.Day.Year == model2.Day.Year) {
+        t.Errorf(` model.Day.Year == model2.Day but should not!`)
+        return
+    }
+
+    if model.Cash == model2.Cash {
+        t.Errorf(`1: model.Cash[%d] != model2.Cash[%d]`,model.Cash,model2.Cash)
+        return
+    }
+
+    if model.MarketValue == model2.MarketValue {
+        t.Errorf(`1: model.MarketValue[%d] != model2.MarketValue[%d]`,model.MarketValue,model2.MarketValue)
+        return
+    }
+
+    if model.OpenPositions == model2.OpenPositions {
+        t.Errorf(`1: model.OpenPositions[%d] != model2.OpenPositions[%d]`,model.OpenPositions,model2.OpenPositions)
+        return
+    }
+
+    res13,err := model.FindByPortfolioId(model2.GetPortfolioId())
+    if err != nil {
+        t.Errorf(`failed model.FindByPortfolioId(model2.GetPortfolioId())`)
+    }
+    if len(res13) == 0 {
+        t.Errorf(`failed to find any PortfolioSnapshot`)
+    }
+
+    res14,err := model.FindByDay(model2.GetDay())
+    if err != nil {
+        t.Errorf(`failed model.FindByDay(model2.GetDay())`)
+    }
+    if len(res14) == 0 {
+        t.Errorf(`failed to find any PortfolioSnapshot`)
+    }
+
+    res15,err := model.FindByCash(model2.GetCash())
+    if err != nil {
+        t.Errorf(`failed model.FindByCash(model2.GetCash())`)
+    }
+    if len(res15) == 0 {
+        t.Errorf(`failed to find any PortfolioSnapshot`)
+    }
+
+    res16,err := model.FindByMarketValue(model2.GetMarketValue())
+    if err != nil {
+        t.Errorf(`failed model.FindByMarketValue(model2.GetMarketValue())`)
+    }
+    if len(res16) == 0 {
+        t.Errorf(`failed to find any PortfolioSnapshot`)
+    }
+
+    res17,err := model.FindByOpenPositions(model2.GetOpenPositions())
+    if err != nil {
+        t.Errorf(`failed model.FindByOpenPositions(model2.GetOpenPositions())`)
+    }
+    if len(res17) == 0 {
+        t.Errorf(`failed to find any PortfolioSnapshot`)
+    }
+} // end of if fileExists
+};
+
+
+func TestPortfolioSnapshotUpdaters(t *testing.T) {
+    if fileExists(`../gopaper-testing.db.yml`) == false {
+        return
+    }
+    a,err := NewMysqlAdapterEx(`../gopaper-testing.db.yml`)
+    defer a.Close()
+    if err != nil {
+        t.Errorf(`could not load ../gopaper-testing.db.yml %s`,err)
+        return
+    }
+    file, err := os.OpenFile("adapter.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+    if err != nil {
+        t.Errorf("Failed to open log file %s", err)
+        return
+    }
+    a.SetLogs(file)
+    model := NewPortfolioSnapshot(a)
+
+    model.SetPortfolioId(int64(randomInteger()))
+    if model.GetPortfolioId() != model.PortfolioId {
+        t.Errorf(`PortfolioSnapshot.GetPortfolioId() != PortfolioSnapshot.PortfolioId`)
+    }
+    if model.IsPortfolioIdDirty != true {
+        t.Errorf(`PortfolioSnapshot.IsPortfolioIdDirty != true`)
+        return
+    }
+    
+    u0 := int64(randomInteger())
+    _,err = model.UpdatePortfolioId(u0)
+    if err != nil {
+        t.Errorf(`failed UpdatePortfolioId(u0) %s`,err)
+        return
+    }
+
+    if model.GetPortfolioId() != u0 {
+        t.Errorf(`PortfolioSnapshot.GetPortfolioId() != u0 after UpdatePortfolioId`)
+        return
+    }
+    model.Reload()
+    if model.GetPortfolioId() != u0 {
+        t.Errorf(`PortfolioSnapshot.GetPortfolioId() != u0 after Reload`)
+        return
+    }
+
+    model.SetDay(randomDateTime(a))
+    if model.GetDay() != model.Day {
+        t.Errorf(`PortfolioSnapshot.GetDay() != PortfolioSnapshot.Day`)
+    }
+    if model.IsDayDirty != true {
+        t.Errorf(`PortfolioSnapshot.IsDayDirty != true`)
+        return
+    }
+    
+    u1 := randomDateTime(a)
+    _,err = model.UpdateDay(u1)
+    if err != nil {
+        t.Errorf(`failed UpdateDay(u1) %s`,err)
+        return
+    }
+
+    if model.GetDay() != u1 {
+        t.Errorf(`PortfolioSnapshot.GetDay() != u1 after UpdateDay`)
+        return
+    }
+    model.Reload()
+    if model.GetDay() != u1 {
+        t.Errorf(`PortfolioSnapshot.GetDay() != u1 after Reload`)
+        return
+    }
+
+    model.SetCash(int(randomInteger()))
+    if model.GetCash() != model.Cash {
+        t.Errorf(`PortfolioSnapshot.GetCash() != PortfolioSnapshot.Cash`)
+    }
+    if model.IsCashDirty != true {
+        t.Errorf(`PortfolioSnapshot.IsCashDirty != true`)
+        return
+    }
+    
+    u2 := int(randomInteger())
+    _,err = model.UpdateCash(u2)
+    if err != nil {
+        t.Errorf(`failed UpdateCash(u2) %s`,err)
+        return
+    }
+
+    if model.GetCash() != u2 {
+        t.Errorf(`PortfolioSnapshot.GetCash() != u2 after UpdateCash`)
+        return
+    }
+    model.Reload()
+    if model.GetCash() != u2 {
+        t.Errorf(`PortfolioSnapshot.GetCash() != u2 after Reload`)
+        return
+    }
+
+    model.SetMarketValue(int(randomInteger()))
+    if model.GetMarketValue() != model.MarketValue {
+        t.Errorf(`PortfolioSnapshot.GetMarketValue() != PortfolioSnapshot.MarketValue`)
+    }
+    if model.IsMarketValueDirty != true {
+        t.Errorf(`PortfolioSnapshot.IsMarketValueDirty != true`)
+        return
+    }
+    
+    u3 := int(randomInteger())
+    _,err = model.UpdateMarketValue(u3)
+    if err != nil {
+        t.Errorf(`failed UpdateMarketValue(u3) %s`,err)
+        return
+    }
+
+    if model.GetMarketValue() != u3 {
+        t.Errorf(`PortfolioSnapshot.GetMarketValue() != u3 after UpdateMarketValue`)
+        return
+    }
+    model.Reload()
+    if model.GetMarketValue() != u3 {
+        t.Errorf(`PortfolioSnapshot.GetMarketValue() != u3 after Reload`)
+        return
+    }
+
+    model.SetOpenPositions(int(randomInteger()))
+    if model.GetOpenPositions() != model.OpenPositions {
+        t.Errorf(`PortfolioSnapshot.GetOpenPositions() != PortfolioSnapshot.OpenPositions`)
+    }
+    if model.IsOpenPositionsDirty != true {
+        t.Errorf(`PortfolioSnapshot.IsOpenPositionsDirty != true`)
+        return
+    }
+    
+    u4 := int(randomInteger())
+    _,err = model.UpdateOpenPositions(u4)
+    if err != nil {
+        t.Errorf(`failed UpdateOpenPositions(u4) %s`,err)
+        return
+    }
+
+    if model.GetOpenPositions() != u4 {
+        t.Errorf(`PortfolioSnapshot.GetOpenPositions() != u4 after UpdateOpenPositions`)
+        return
+    }
+    model.Reload()
+    if model.GetOpenPositions() != u4 {
+        t.Errorf(`PortfolioSnapshot.GetOpenPositions() != u4 after Reload`)
+        return
+    }
+
+};
+
+
+func TestNewPortfolio(t *testing.T) {
+    a := NewMysqlAdapter(``)
+    o := NewPortfolio(a)
+    if o._table != "portfolios" {
+        t.Errorf("failed creating %+v",o);
+        return
+    }
+}
+func TestPortfolioFromDBValueMap(t *testing.T) {
+    a := NewMysqlAdapter(``)
+    o := NewPortfolio(a)
+    m := make(map[string]DBValue)
+	m["id"] = a.NewDBValue()
+	m["id"].SetInternalValue("id",strconv.Itoa(999))
+	m["name"] = a.NewDBValue()
+	m["name"].SetInternalValue("name","AString")
+	m["description"] = a.NewDBValue()
+	m["description"].SetInternalValue("description","AString")
+	m["value"] = a.NewDBValue()
 	m["value"].SetInternalValue("value",strconv.Itoa(999))
 
     err := o.FromDBValueMap(m)
@@ -1193,6 +1986,8 @@ func TestPositionFromDBValueMap(t *testing.T) {
 	m["id"].SetInternalValue("id",strconv.Itoa(999))
 	m["portfolio_id"] = a.NewDBValue()
 	m["portfolio_id"].SetInternalValue("portfolio_id",strconv.Itoa(999))
+	m["symbol"] = a.NewDBValue()
+	m["symbol"].SetInternalValue("symbol","AString")
 	m["started_at"] = a.NewDBValue()
 	m["started_at"].SetInternalValue("started_at","2016-01-01 10:50:23")
 	m["closed_at"] = a.NewDBValue()
@@ -1207,6 +2002,8 @@ func TestPositionFromDBValueMap(t *testing.T) {
 	m["stop_loss"].SetInternalValue("stop_loss",strconv.Itoa(999))
 	m["quantity"] = a.NewDBValue()
 	m["quantity"].SetInternalValue("quantity",strconv.Itoa(999))
+	m["lock_version"] = a.NewDBValue()
+	m["lock_version"].SetInternalValue("lock_version",strconv.Itoa(999))
 
     err := o.FromDBValueMap(m)
     if err != nil {
@@ -1223,6 +2020,11 @@ func TestPositionFromDBValueMap(t *testing.T) {
         return
     }    
 
+    if o.Symbol != "AString" {
+        t.Errorf("o.Symbol test failed %+v",o)
+        return
+    }    
+
     if o.StartedAt.Year != 2016 {
         t.Errorf("year not set for %+v",o.StartedAt)
         return
@@ -1235,8 +2037,8 @@ func TestPositionFromDBValueMap(t *testing.T) {
         o.StartedAt.Seconds != 23 ) {
         t.Errorf(`fields don't match up for %+v`,o.StartedAt)
     }
-    r2,_ := m["started_at"].AsString()
-    if o.StartedAt.ToString() != r2 {
+    r3,_ := m["started_at"].AsString()
+    if o.StartedAt.ToString() != r3 {
         t.Errorf(`restring of o.StartedAt failed %s`,o.StartedAt.ToString())
     }
 
@@ -1252,8 +2054,8 @@ func TestPositionFromDBValueMap(t *testing.T) {
         o.ClosedAt.Seconds != 23 ) {
         t.Errorf(`fields don't match up for %+v`,o.ClosedAt)
     }
-    r3,_ := m["closed_at"].AsString()
-    if o.ClosedAt.ToString() != r3 {
+    r4,_ := m["closed_at"].AsString()
+    if o.ClosedAt.ToString() != r4 {
         t.Errorf(`restring of o.ClosedAt failed %s`,o.ClosedAt.ToString())
     }
 
@@ -1281,6 +2083,11 @@ func TestPositionFromDBValueMap(t *testing.T) {
         t.Errorf("o.Quantity test failed %+v",o)
         return
     }    
+
+    if o.LockVersion != 999 {
+        t.Errorf("o.LockVersion test failed %+v",o)
+        return
+    }    
 }
 
 func TestPositionCreate(t *testing.T) {
@@ -1298,6 +2105,7 @@ func TestPositionCreate(t *testing.T) {
     a.SetLogs(file)
     model := NewPosition(a)
 model.PortfolioId = int64(randomInteger())
+model.Symbol = randomString(19)
 model.StartedAt = randomDateTime(a)
 model.ClosedAt = randomDateTime(a)
 model.Ptype = randomString(19)
@@ -1305,6 +2113,7 @@ model.Buy = int(randomInteger())
 model.Sell = int(randomInteger())
 model.StopLoss = int(randomInteger())
 model.Quantity = int(randomInteger())
+model.LockVersion = int(randomInteger())
 
     err = model.Create()
     if err != nil {
@@ -1329,6 +2138,11 @@ model.Quantity = int(randomInteger())
         return
     }
 
+    if model.Symbol != model2.Symbol {
+        t.Errorf(` model.Symbol[%s] != model2.Symbol[%s]`,model.Symbol,model2.Symbol)
+        return
+    }
+
     if (model.StartedAt.Year != model2.StartedAt.Year ||
         model.StartedAt.Month != model2.StartedAt.Month ||
         model.StartedAt.Day != model2.StartedAt.Day ||
@@ -1373,7 +2187,13 @@ model.Quantity = int(randomInteger())
         t.Errorf(` model.Quantity[%d] != model2.Quantity[%d]`,model.Quantity,model2.Quantity)
         return
     }
+
+    if model.LockVersion != model2.LockVersion {
+        t.Errorf(` model.LockVersion[%d] != model2.LockVersion[%d]`,model.LockVersion,model2.LockVersion)
+        return
+    }
 model2.SetPortfolioId(int64(randomInteger()))
+model2.SetSymbol(randomString(19))
 model2.SetStartedAt(randomDateTime(a))
 model2.SetClosedAt(randomDateTime(a))
 model2.SetPtype(randomString(19))
@@ -1381,6 +2201,7 @@ model2.SetBuy(int(randomInteger()))
 model2.SetSell(int(randomInteger()))
 model2.SetStopLoss(int(randomInteger()))
 model2.SetQuantity(int(randomInteger()))
+model2.SetLockVersion(int(randomInteger()))
 
     err = model2.Save()
     if err != nil {
@@ -1392,6 +2213,11 @@ model2.SetQuantity(int(randomInteger()))
         return
     }
 
+    if model.Symbol == model2.Symbol {
+        t.Errorf(`1: model.Symbol[%s] != model2.Symbol[%s]`,model.Symbol,model2.Symbol)
+        return
+    }
+
     if (model.StartedAt.Year == model2.StartedAt.Year) {
         t.Errorf(` model.StartedAt.Year == model2.StartedAt but should not!`)
         return
@@ -1427,67 +2253,88 @@ model2.SetQuantity(int(randomInteger()))
         return
     }
 
-    res20,err := model.FindByPortfolioId(model2.GetPortfolioId())
+    if model.LockVersion == model2.LockVersion {
+        t.Errorf(`1: model.LockVersion[%d] != model2.LockVersion[%d]`,model.LockVersion,model2.LockVersion)
+        return
+    }
+
+    res26,err := model.FindByPortfolioId(model2.GetPortfolioId())
     if err != nil {
         t.Errorf(`failed model.FindByPortfolioId(model2.GetPortfolioId())`)
     }
-    if len(res20) == 0 {
+    if len(res26) == 0 {
         t.Errorf(`failed to find any Position`)
     }
 
-    res21,err := model.FindByStartedAt(model2.GetStartedAt())
+    res27,err := model.FindBySymbol(model2.GetSymbol())
+    if err != nil {
+        t.Errorf(`failed model.FindBySymbol(model2.GetSymbol())`)
+    }
+    if len(res27) == 0 {
+        t.Errorf(`failed to find any Position`)
+    }
+
+    res28,err := model.FindByStartedAt(model2.GetStartedAt())
     if err != nil {
         t.Errorf(`failed model.FindByStartedAt(model2.GetStartedAt())`)
     }
-    if len(res21) == 0 {
+    if len(res28) == 0 {
         t.Errorf(`failed to find any Position`)
     }
 
-    res22,err := model.FindByClosedAt(model2.GetClosedAt())
+    res29,err := model.FindByClosedAt(model2.GetClosedAt())
     if err != nil {
         t.Errorf(`failed model.FindByClosedAt(model2.GetClosedAt())`)
     }
-    if len(res22) == 0 {
+    if len(res29) == 0 {
         t.Errorf(`failed to find any Position`)
     }
 
-    res23,err := model.FindByPtype(model2.GetPtype())
+    res30,err := model.FindByPtype(model2.GetPtype())
     if err != nil {
         t.Errorf(`failed model.FindByPtype(model2.GetPtype())`)
     }
-    if len(res23) == 0 {
+    if len(res30) == 0 {
         t.Errorf(`failed to find any Position`)
     }
 
-    res24,err := model.FindByBuy(model2.GetBuy())
+    res31,err := model.FindByBuy(model2.GetBuy())
     if err != nil {
         t.Errorf(`failed model.FindByBuy(model2.GetBuy())`)
     }
-    if len(res24) == 0 {
+    if len(res31) == 0 {
         t.Errorf(`failed to find any Position`)
     }
 
-    res25,err := model.FindBySell(model2.GetSell())
+    res32,err := model.FindBySell(model2.GetSell())
     if err != nil {
         t.Errorf(`failed model.FindBySell(model2.GetSell())`)
     }
-    if len(res25) == 0 {
+    if len(res32) == 0 {
         t.Errorf(`failed to find any Position`)
     }
 
-    res26,err := model.FindByStopLoss(model2.GetStopLoss())
+    res33,err := model.FindByStopLoss(model2.GetStopLoss())
     if err != nil {
         t.Errorf(`failed model.FindByStopLoss(model2.GetStopLoss())`)
     }
-    if len(res26) == 0 {
+    if len(res33) == 0 {
         t.Errorf(`failed to find any Position`)
     }
 
-    res27,err := model.FindByQuantity(model2.GetQuantity())
+    res34,err := model.FindByQuantity(model2.GetQuantity())
     if err != nil {
         t.Errorf(`failed model.FindByQuantity(model2.GetQuantity())`)
     }
-    if len(res27) == 0 {
+    if len(res34) == 0 {
+        t.Errorf(`failed to find any Position`)
+    }
+
+    res35,err := model.FindByLockVersion(model2.GetLockVersion())
+    if err != nil {
+        t.Errorf(`failed model.FindByLockVersion(model2.GetLockVersion())`)
+    }
+    if len(res35) == 0 {
         t.Errorf(`failed to find any Position`)
     }
 } // end of if fileExists
@@ -1538,6 +2385,32 @@ func TestPositionUpdaters(t *testing.T) {
         return
     }
 
+    model.SetSymbol(randomString(19))
+    if model.GetSymbol() != model.Symbol {
+        t.Errorf(`Position.GetSymbol() != Position.Symbol`)
+    }
+    if model.IsSymbolDirty != true {
+        t.Errorf(`Position.IsSymbolDirty != true`)
+        return
+    }
+    
+    u1 := randomString(19)
+    _,err = model.UpdateSymbol(u1)
+    if err != nil {
+        t.Errorf(`failed UpdateSymbol(u1) %s`,err)
+        return
+    }
+
+    if model.GetSymbol() != u1 {
+        t.Errorf(`Position.GetSymbol() != u1 after UpdateSymbol`)
+        return
+    }
+    model.Reload()
+    if model.GetSymbol() != u1 {
+        t.Errorf(`Position.GetSymbol() != u1 after Reload`)
+        return
+    }
+
     model.SetStartedAt(randomDateTime(a))
     if model.GetStartedAt() != model.StartedAt {
         t.Errorf(`Position.GetStartedAt() != Position.StartedAt`)
@@ -1547,20 +2420,20 @@ func TestPositionUpdaters(t *testing.T) {
         return
     }
     
-    u1 := randomDateTime(a)
-    _,err = model.UpdateStartedAt(u1)
+    u2 := randomDateTime(a)
+    _,err = model.UpdateStartedAt(u2)
     if err != nil {
-        t.Errorf(`failed UpdateStartedAt(u1) %s`,err)
+        t.Errorf(`failed UpdateStartedAt(u2) %s`,err)
         return
     }
 
-    if model.GetStartedAt() != u1 {
-        t.Errorf(`Position.GetStartedAt() != u1 after UpdateStartedAt`)
+    if model.GetStartedAt() != u2 {
+        t.Errorf(`Position.GetStartedAt() != u2 after UpdateStartedAt`)
         return
     }
     model.Reload()
-    if model.GetStartedAt() != u1 {
-        t.Errorf(`Position.GetStartedAt() != u1 after Reload`)
+    if model.GetStartedAt() != u2 {
+        t.Errorf(`Position.GetStartedAt() != u2 after Reload`)
         return
     }
 
@@ -1573,20 +2446,20 @@ func TestPositionUpdaters(t *testing.T) {
         return
     }
     
-    u2 := randomDateTime(a)
-    _,err = model.UpdateClosedAt(u2)
+    u3 := randomDateTime(a)
+    _,err = model.UpdateClosedAt(u3)
     if err != nil {
-        t.Errorf(`failed UpdateClosedAt(u2) %s`,err)
+        t.Errorf(`failed UpdateClosedAt(u3) %s`,err)
         return
     }
 
-    if model.GetClosedAt() != u2 {
-        t.Errorf(`Position.GetClosedAt() != u2 after UpdateClosedAt`)
+    if model.GetClosedAt() != u3 {
+        t.Errorf(`Position.GetClosedAt() != u3 after UpdateClosedAt`)
         return
     }
     model.Reload()
-    if model.GetClosedAt() != u2 {
-        t.Errorf(`Position.GetClosedAt() != u2 after Reload`)
+    if model.GetClosedAt() != u3 {
+        t.Errorf(`Position.GetClosedAt() != u3 after Reload`)
         return
     }
 
@@ -1599,20 +2472,20 @@ func TestPositionUpdaters(t *testing.T) {
         return
     }
     
-    u3 := randomString(19)
-    _,err = model.UpdatePtype(u3)
+    u4 := randomString(19)
+    _,err = model.UpdatePtype(u4)
     if err != nil {
-        t.Errorf(`failed UpdatePtype(u3) %s`,err)
+        t.Errorf(`failed UpdatePtype(u4) %s`,err)
         return
     }
 
-    if model.GetPtype() != u3 {
-        t.Errorf(`Position.GetPtype() != u3 after UpdatePtype`)
+    if model.GetPtype() != u4 {
+        t.Errorf(`Position.GetPtype() != u4 after UpdatePtype`)
         return
     }
     model.Reload()
-    if model.GetPtype() != u3 {
-        t.Errorf(`Position.GetPtype() != u3 after Reload`)
+    if model.GetPtype() != u4 {
+        t.Errorf(`Position.GetPtype() != u4 after Reload`)
         return
     }
 
@@ -1625,20 +2498,20 @@ func TestPositionUpdaters(t *testing.T) {
         return
     }
     
-    u4 := int(randomInteger())
-    _,err = model.UpdateBuy(u4)
+    u5 := int(randomInteger())
+    _,err = model.UpdateBuy(u5)
     if err != nil {
-        t.Errorf(`failed UpdateBuy(u4) %s`,err)
+        t.Errorf(`failed UpdateBuy(u5) %s`,err)
         return
     }
 
-    if model.GetBuy() != u4 {
-        t.Errorf(`Position.GetBuy() != u4 after UpdateBuy`)
+    if model.GetBuy() != u5 {
+        t.Errorf(`Position.GetBuy() != u5 after UpdateBuy`)
         return
     }
     model.Reload()
-    if model.GetBuy() != u4 {
-        t.Errorf(`Position.GetBuy() != u4 after Reload`)
+    if model.GetBuy() != u5 {
+        t.Errorf(`Position.GetBuy() != u5 after Reload`)
         return
     }
 
@@ -1651,20 +2524,20 @@ func TestPositionUpdaters(t *testing.T) {
         return
     }
     
-    u5 := int(randomInteger())
-    _,err = model.UpdateSell(u5)
+    u6 := int(randomInteger())
+    _,err = model.UpdateSell(u6)
     if err != nil {
-        t.Errorf(`failed UpdateSell(u5) %s`,err)
+        t.Errorf(`failed UpdateSell(u6) %s`,err)
         return
     }
 
-    if model.GetSell() != u5 {
-        t.Errorf(`Position.GetSell() != u5 after UpdateSell`)
+    if model.GetSell() != u6 {
+        t.Errorf(`Position.GetSell() != u6 after UpdateSell`)
         return
     }
     model.Reload()
-    if model.GetSell() != u5 {
-        t.Errorf(`Position.GetSell() != u5 after Reload`)
+    if model.GetSell() != u6 {
+        t.Errorf(`Position.GetSell() != u6 after Reload`)
         return
     }
 
@@ -1677,20 +2550,20 @@ func TestPositionUpdaters(t *testing.T) {
         return
     }
     
-    u6 := int(randomInteger())
-    _,err = model.UpdateStopLoss(u6)
+    u7 := int(randomInteger())
+    _,err = model.UpdateStopLoss(u7)
     if err != nil {
-        t.Errorf(`failed UpdateStopLoss(u6) %s`,err)
+        t.Errorf(`failed UpdateStopLoss(u7) %s`,err)
         return
     }
 
-    if model.GetStopLoss() != u6 {
-        t.Errorf(`Position.GetStopLoss() != u6 after UpdateStopLoss`)
+    if model.GetStopLoss() != u7 {
+        t.Errorf(`Position.GetStopLoss() != u7 after UpdateStopLoss`)
         return
     }
     model.Reload()
-    if model.GetStopLoss() != u6 {
-        t.Errorf(`Position.GetStopLoss() != u6 after Reload`)
+    if model.GetStopLoss() != u7 {
+        t.Errorf(`Position.GetStopLoss() != u7 after Reload`)
         return
     }
 
@@ -1703,20 +2576,240 @@ func TestPositionUpdaters(t *testing.T) {
         return
     }
     
-    u7 := int(randomInteger())
-    _,err = model.UpdateQuantity(u7)
+    u8 := int(randomInteger())
+    _,err = model.UpdateQuantity(u8)
+    if err != nil {
+        t.Errorf(`failed UpdateQuantity(u8) %s`,err)
+        return
+    }
+
+    if model.GetQuantity() != u8 {
+        t.Errorf(`Position.GetQuantity() != u8 after UpdateQuantity`)
+        return
+    }
+    model.Reload()
+    if model.GetQuantity() != u8 {
+        t.Errorf(`Position.GetQuantity() != u8 after Reload`)
+        return
+    }
+
+    model.SetLockVersion(int(randomInteger()))
+    if model.GetLockVersion() != model.LockVersion {
+        t.Errorf(`Position.GetLockVersion() != Position.LockVersion`)
+    }
+    if model.IsLockVersionDirty != true {
+        t.Errorf(`Position.IsLockVersionDirty != true`)
+        return
+    }
+    
+    u9 := int(randomInteger())
+    _,err = model.UpdateLockVersion(u9)
+    if err != nil {
+        t.Errorf(`failed UpdateLockVersion(u9) %s`,err)
+        return
+    }
+
+    if model.GetLockVersion() != u9 {
+        t.Errorf(`Position.GetLockVersion() != u9 after UpdateLockVersion`)
+        return
+    }
+    model.Reload()
+    if model.GetLockVersion() != u9 {
+        t.Errorf(`Position.GetLockVersion() != u9 after Reload`)
+        return
+    }
+
+};
+
+
+func TestNewSetting(t *testing.T) {
+    a := NewMysqlAdapter(``)
+    o := NewSetting(a)
+    if o._table != "settings" {
+        t.Errorf("failed creating %+v",o);
+        return
+    }
+}
+func TestSettingFromDBValueMap(t *testing.T) {
+    a := NewMysqlAdapter(``)
+    o := NewSetting(a)
+    m := make(map[string]DBValue)
+	m["id"] = a.NewDBValue()
+	m["id"].SetInternalValue("id",strconv.Itoa(999))
+	m["skey"] = a.NewDBValue()
+	m["skey"].SetInternalValue("skey","AString")
+	m["svalue"] = a.NewDBValue()
+	m["svalue"].SetInternalValue("svalue","AString")
+
+    err := o.FromDBValueMap(m)
+    if err != nil {
+        t.Errorf("FromDBValueMap failed %s",err)
+    }
+
+    if o.Id != 999 {
+        t.Errorf("o.Id test failed %+v",o)
+        return
+    }    
+
+    if o.Skey != "AString" {
+        t.Errorf("o.Skey test failed %+v",o)
+        return
+    }    
+
+    if o.Svalue != "AString" {
+        t.Errorf("o.Svalue test failed %+v",o)
+        return
+    }    
+}
+
+func TestSettingCreate(t *testing.T) {
+    if fileExists(`../gopaper-testing.db.yml`) {
+    a,err := NewMysqlAdapterEx(`../gopaper-testing.db.yml`)
+    defer a.Close()
+    if err != nil {
+        t.Errorf(`could not load ../gopaper-testing.db.yml %s`,err)
+        return
+    }
+    file, err := os.OpenFile("adapter.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+    if err != nil {
+        t.Errorf(" Failed to open log file %s", err)
+    }
+    a.SetLogs(file)
+    model := NewSetting(a)
+model.Skey = randomString(19)
+model.Svalue = randomString(25)
+
+    err = model.Create()
+    if err != nil {
+        t.Errorf(` failed to create model %s`,err)
+        return
+    }
+
+    model2 := NewSetting(a)
+    found,err := model2.Find(model.GetPrimaryKeyValue())
+    if err != nil {
+        t.Errorf(` did not find record for %s = %d because of %s`,model.GetPrimaryKeyName(),model.GetPrimaryKeyValue(),err)
+        return
+    }
+    if found == false {
+        t.Errorf(` did not find record for %s = %d because of %s`,model.GetPrimaryKeyName(),model.GetPrimaryKeyValue(),err)
+        return
+    }
+
+
+    if model.Skey != model2.Skey {
+        t.Errorf(` model.Skey[%s] != model2.Skey[%s]`,model.Skey,model2.Skey)
+        return
+    }
+
+    if model.Svalue != model2.Svalue {
+        t.Errorf(` model.Svalue[%s] != model2.Svalue[%s]`,model.Svalue,model2.Svalue)
+        return
+    }
+model2.SetSkey(randomString(19))
+model2.SetSvalue(randomString(25))
+
+    err = model2.Save()
+    if err != nil {
+        t.Errorf(`failed to save model2 %s`,err)
+    }
+
+    if model.Skey == model2.Skey {
+        t.Errorf(`1: model.Skey[%s] != model2.Skey[%s]`,model.Skey,model2.Skey)
+        return
+    }
+
+    if model.Svalue == model2.Svalue {
+        t.Errorf(`1: model.Svalue[%s] != model2.Svalue[%s]`,model.Svalue,model2.Svalue)
+        return
+    }
+
+    res6,err := model.FindBySkey(model2.GetSkey())
+    if err != nil {
+        t.Errorf(`failed model.FindBySkey(model2.GetSkey())`)
+    }
+    if len(res6) == 0 {
+        t.Errorf(`failed to find any Setting`)
+    }
+
+    res7,err := model.FindBySvalue(model2.GetSvalue())
+    if err != nil {
+        t.Errorf(`failed model.FindBySvalue(model2.GetSvalue())`)
+    }
+    if len(res7) == 0 {
+        t.Errorf(`failed to find any Setting`)
+    }
+} // end of if fileExists
+};
+
+
+func TestSettingUpdaters(t *testing.T) {
+    if fileExists(`../gopaper-testing.db.yml`) == false {
+        return
+    }
+    a,err := NewMysqlAdapterEx(`../gopaper-testing.db.yml`)
+    defer a.Close()
+    if err != nil {
+        t.Errorf(`could not load ../gopaper-testing.db.yml %s`,err)
+        return
+    }
+    file, err := os.OpenFile("adapter.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+    if err != nil {
+        t.Errorf("Failed to open log file %s", err)
+        return
+    }
+    a.SetLogs(file)
+    model := NewSetting(a)
+
+    model.SetSkey(randomString(19))
+    if model.GetSkey() != model.Skey {
+        t.Errorf(`Setting.GetSkey() != Setting.Skey`)
+    }
+    if model.IsSkeyDirty != true {
+        t.Errorf(`Setting.IsSkeyDirty != true`)
+        return
+    }
+    
+    u0 := randomString(19)
+    _,err = model.UpdateSkey(u0)
+    if err != nil {
+        t.Errorf(`failed UpdateSkey(u0) %s`,err)
+        return
+    }
+
+    if model.GetSkey() != u0 {
+        t.Errorf(`Setting.GetSkey() != u0 after UpdateSkey`)
+        return
+    }
+    model.Reload()
+    if model.GetSkey() != u0 {
+        t.Errorf(`Setting.GetSkey() != u0 after Reload`)
+        return
+    }
+
+    model.SetSvalue(randomString(25))
+    if model.GetSvalue() != model.Svalue {
+        t.Errorf(`Setting.GetSvalue() != Setting.Svalue`)
+    }
+    if model.IsSvalueDirty != true {
+        t.Errorf(`Setting.IsSvalueDirty != true`)
+        return
+    }
+    
+    u1 := randomString(25)
+    _,err = model.UpdateSvalue(u1)
     if err != nil {
-        t.Errorf(`failed UpdateQuantity(u7) %s`,err)
+        t.Errorf(`failed UpdateSvalue(u1) %s`,err)
         return
     }
 
-    if model.GetQuantity() != u7 {
-        t.Errorf(`Position.GetQuantity() != u7 after UpdateQuantity`)
+    if model.GetSvalue() != u1 {
+        t.Errorf(`Setting.GetSvalue() != u1 after UpdateSvalue`)
         return
     }
     model.Reload()
-    if model.GetQuantity() != u7 {
-        t.Errorf(`Position.GetQuantity() != u7 after Reload`)
+    if model.GetSvalue() != u1 {
+        t.Errorf(`Setting.GetSvalue() != u1 after Reload`)
         return
     }
 
@@ -1740,7 +2833,17 @@ func TestMysqlAdapterFromYAML(t *testing.T) {
         a.Pass != `rootpass` ||
         a.Host != `localhost` ||
         a.Database != `my_db` ||
-        a.DBPrefix != `wp_`) {
+        a.DBPrefix != `wp_` ||
+        a.BatchSize != 250 ||
+        a.MaxOpenConns != 4 ||
+        a.MaxIdleConns != 2 ||
+        a.ConnMaxLifetime != time.Minute ||
+        a.PingOnBorrow != true ||
+        a.Retries != 1 ||
+        a.RetryBackoff != time.Millisecond ||
+        a.Port != 3306 ||
+        a.Charset != `utf8mb4` ||
+        a.Params[`loc`] != `UTC`) {
         t.Errorf(`did not fully apply yaml file %+v`,a)
     }
 }
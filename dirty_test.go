@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestDirtyTracking(t *testing.T) {
+	a := newFakeAdapter()
+	n := NewNote(a)
+	n.PortfolioId = 1
+	n.PositionId = 2
+	n.Value = `first`
+	err := n.Save()
+	if err != nil || a.count(`INSERT INTO notes`) != 1 {
+		t.Errorf(`saving a new note should insert it %v %v`, err, a.executed)
+	}
+	if n.IsDirty() {
+		t.Errorf(`a saved note should not be dirty %v`, n.DirtyFields())
+	}
+	executed := len(a.executed)
+	err = n.Save()
+	if err != nil || len(a.executed) != executed {
+		t.Errorf(`saving a clean note should do nothing %v`, a.executed[executed:])
+	}
+	n.SetValue(`second`)
+	if fields := n.DirtyFields(); len(fields) != 1 || fields[0] != `value` {
+		t.Errorf(`only value should be dirty got %v`, fields)
+	}
+	if v, _ := n.Original(`value`); v != `first` {
+		t.Errorf(`the original value should be first got %s`, v)
+	}
+	err = n.Save()
+	if err != nil || a.count(`UPDATE notes SET value = 'second' WHERE id = '1'`) != 1 {
+		t.Errorf(`only the value should be updated %v %v`, err, a.executed)
+	}
+	if v, _ := n.Original(`value`); n.IsDirty() || v != `second` {
+		t.Errorf(`saving should reset the dirty fields and originals`)
+	}
+	n.SetPositionId(3)
+	_, err = n.UpdatePositionId(3)
+	if err != nil || n.IsDirty() {
+		t.Errorf(`UpdatePositionId should clear its dirty marker %v`, n.DirtyFields())
+	}
+}
+
+func TestLoadedModelsAreClean(t *testing.T) {
+	a := newFakeAdapter()
+	a.rows = func(q string) []map[string]string {
+		return []map[string]string{{`id`: `4`, `value`: `loaded`, `portfolio_id`: `1`, `position_id`: `2`}}
+	}
+	n := NewNote(a)
+	n.SetValue(`changed`)
+	found, err := n.Find(4)
+	if !found || err != nil {
+		t.Errorf(`Find failed %v`, err)
+	}
+	if n.IsDirty() || n.Value != `loaded` {
+		t.Errorf(`Find should replace the values and reset the dirty fields %v`, n.DirtyFields())
+	}
+	if _, ok := NewNote(a).Original(`value`); ok {
+		t.Errorf(`a new note has no originals`)
+	}
+	n.SetValue(`changed`)
+	err = n.Save()
+	if err != nil || a.count(`INSERT INTO notes`) != 0 || a.count(`UPDATE notes`) != 1 {
+		t.Errorf(`a loaded note should be updated %v %v`, err, a.executed)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"sync/atomic"
+)
+
+// countingDriver is a database/sql driver whose statements do nothing
+// but count how often they are prepared and executed, every execution
+// returns the count as its insert id. fail, when set, may fail the
+// query, exec or commit stage of a statement. Queries return no rows.
+type countingDriver struct {
+	prepared int64
+	executed int64
+	mu       sync.Mutex
+	ids      map[string]int64
+	fail     func(stage string) error
+}
+
+type countingConn struct{ d *countingDriver }
+type countingStmt struct {
+	d *countingDriver
+	q string
+}
+type countingTx struct{ d *countingDriver }
+type countingResult int64
+type countingRows struct{}
+
+func (d *countingDriver) Open(string) (driver.Conn, error) { return &countingConn{d}, nil }
+func (c *countingConn) Prepare(q string) (driver.Stmt, error) {
+	atomic.AddInt64(&c.d.prepared, 1)
+	return &countingStmt{c.d, q}, nil
+}
+func (c *countingConn) Close() error              { return nil }
+func (c *countingConn) Begin() (driver.Tx, error) { return countingTx{c.d}, nil }
+func (s *countingStmt) Close() error              { return nil }
+func (s *countingStmt) NumInput() int             { return -1 }
+func (s *countingStmt) Exec([]driver.Value) (driver.Result, error) {
+	if s.d.fail != nil {
+		if err := s.d.fail(`exec`); err != nil {
+			return nil, err
+		}
+	}
+	id := atomic.AddInt64(&s.d.executed, 1)
+	s.d.mu.Lock()
+	s.d.ids[s.q] = id
+	s.d.mu.Unlock()
+	return countingResult(id), nil
+}
+func (s *countingStmt) Query([]driver.Value) (driver.Rows, error) {
+	if s.d.fail != nil {
+		if err := s.d.fail(`query`); err != nil {
+			return nil, err
+		}
+	}
+	return countingRows{}, nil
+}
+func (tx countingTx) Commit() error {
+	if tx.d.fail != nil {
+		return tx.d.fail(`commit`)
+	}
+	return nil
+}
+func (countingTx) Rollback() error                    { return nil }
+func (r countingResult) LastInsertId() (int64, error) { return int64(r), nil }
+func (r countingResult) RowsAffected() (int64, error) { return 1, nil }
+func (countingRows) Columns() []string                { return nil }
+func (countingRows) Close() error                     { return nil }
+func (countingRows) Next([]driver.Value) error        { return io.EOF }
+
+var countingDrivers int64
+
+// newCountingAdapter returns an opened MysqlAdapter on a countingDriver
+func newCountingAdapter() (*MysqlAdapter, *countingDriver) {
+	d := &countingDriver{ids: map[string]int64{}}
+	name := fmt.Sprintf(`counting%d`, atomic.AddInt64(&countingDrivers, 1))
+	sql.Register(name, d)
+	db, _ := sql.Open(name, ``)
+	a := NewMysqlAdapter(``)
+	a.SetLogs(ioutil.Discard)
+	a._conn = db
+	a._opened = true
+	return a, d
+}
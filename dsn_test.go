@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDSN(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	a.Host, a.User, a.Pass, a.Database = `localhost`, `root`, `secret`, `my_db`
+	cases := []struct {
+		setup func()
+		dsn   string
+	}{
+		{func() {}, `root:secret@/my_db`},
+		{func() { a.Host = `db.example.com` }, `root:secret@tcp(db.example.com)/my_db`},
+		{func() { a.Port = 3307 }, `root:secret@tcp(db.example.com:3307)/my_db`},
+		{func() { a.Socket = `/tmp/mysql.sock` }, `root:secret@unix(/tmp/mysql.sock)/my_db`},
+		{func() { a.Charset = `utf8mb4,utf8` }, `root:secret@unix(/tmp/mysql.sock)/my_db?charset=utf8mb4,utf8`},
+		{func() { a.Params = map[string]string{`timeout`: `5s`, `loc`: `America/New_York`} },
+			`root:secret@unix(/tmp/mysql.sock)/my_db?loc=America%2FNew_York&timeout=5s&charset=utf8mb4,utf8`},
+		{func() { a.TLS = `skip-verify` },
+			`root:secret@unix(/tmp/mysql.sock)/my_db?loc=America%2FNew_York&timeout=5s&charset=utf8mb4,utf8&tls=skip-verify`},
+	}
+	for _, c := range cases {
+		c.setup()
+		dsn, err := a.DSN()
+		if err != nil || dsn != c.dsn {
+			t.Errorf(`expected %s got %s %v`, c.dsn, dsn, err)
+		}
+	}
+	a.Params = map[string]string{`parseTime`: `maybe`}
+	if _, err := a.DSN(); err == nil {
+		t.Errorf(`a bad driver parameter should be reported`)
+	}
+	a.Params = map[string]string{`parseTime`: `true`}
+	if _, err := a.DSN(); err == nil {
+		t.Errorf(`parseTime should be refused, AsDateTime cannot read its DATETIMEs`)
+	}
+	a.Params = nil
+	a.TLSCA = `test_data/no_such_ca.pem`
+	if _, err := a.DSN(); err == nil {
+		t.Errorf(`a missing CA file should be reported`)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	a.Host, a.Pass = `localhost`, `from_yaml`
+	os.Setenv(`TEST_DB_PASS`, `from_env`)
+	os.Setenv(`TEST_DB_PORT`, `3307`)
+	defer os.Unsetenv(`TEST_DB_PASS`)
+	defer os.Unsetenv(`TEST_DB_PORT`)
+	err := a.FromEnv(`TEST_DB_`)
+	if err != nil || a.Pass != `from_env` || a.Port != 3307 || a.Host != `localhost` {
+		t.Errorf(`the environment should override pass and port only %+v %v`, a, err)
+	}
+	os.Setenv(`TEST_DB_PORT`, `three`)
+	if a.FromEnv(`TEST_DB_`) == nil {
+		t.Errorf(`a port which is not a number should fail`)
+	}
+}
@@ -8,37 +8,49 @@ function _save_create($t) {
     $pkeyfmt = "";
     $pkeyname = "";
     $sets = "";
+    $audit_values = "";
+    $lock = false;
     $pkeyfmt = mysqlToFmtType($t->pfield->Type);
     $pkeyname =  maybeLC(convertFieldName($t->pfield->Field));
     foreach ( $t->fields as $tf) {
         if (isPrimaryKey($tf) && $t->model_name != "TermRelationship") {
             continue;
         }
+        // DateTimes go through dateTimeSQL so that nil is written as NULL,
+        // which means they must not be quoted here
         if ($tf->go_type == "*DateTime") {
-             $go_fnames[$i] = maybeLC(convertFieldName($tf->Field)) . ".ToString()";
+             $go_fnames[$i] = "dateTimeSQL(o." . maybeLC(convertFieldName($tf->Field)) . ")";
+             $fmts[$i] = mysqlToFmtType($tf->Type);
         } else {
-             $go_fnames[$i] = maybeLC(convertFieldName($tf->Field));
+             $go_fnames[$i] = "o." . maybeLC(convertFieldName($tf->Field));
+             $fmts[$i] = "'" . mysqlToFmtType($tf->Type) . "'";
         }
         $gfn = convertFieldName($tf->Field);
         $mysql_fnames[$i] = $tf->Field;
-        $fmts[$i] = mysqlToFmtType($tf->Type);
         if ($tf->go_type == 'string') {
             $the_gfn = "o._adapter.SafeString(o.$gfn)";
+        } else if ($tf->go_type == "*DateTime") {
+            $the_gfn = "dateTimeSQL(o.$gfn)";
         } else {
             $the_gfn = "o.$gfn";
         }
-        $sets .= "
+        // lock_version is never set, Update increments it in the database
+        if ($tf->Field == "lock_version") {
+            $lock = true;
+        } else {
+            $sets .= "
     if o.Is{$gfn}Dirty == true {
-        sets = append(sets,fmt.Sprintf(`{$mysql_fnames[$i]} = '{$fmts[$i]}'`,$the_gfn))
+        sets = append(sets,fmt.Sprintf(`{$mysql_fnames[$i]} = {$fmts[$i]}`,$the_gfn))
+        changes[`{$mysql_fnames[$i]}`] = auditValue(o.$gfn)
     }
 ";
+        }
+        $audit_values .= "
+            `{$mysql_fnames[$i]}`: auditValue(o.$gfn),";
         $i++;
     }
-    $fmts = array_map(function ($x) { return "'$x'";},$fmts);
     $mysql_fnames = array_map(function ($x) { return "`$x`";},$mysql_fnames);
 
-    $go_fnames = array_map(function ($x) { return "o.$x";},$go_fnames);
-
     $update_entries = array();
     $cr_cols = array();
     $cr_vals = array();
@@ -63,49 +75,139 @@ function _save_create($t) {
         $where = "%s = '$pkeyfmt'";
         $up_gn_line = "o._pkey, o.$pkeyname";  
     }
-    $set_primary_key_field = "o.{$t->pfield->model_field_name} = o._adapter.LastInsertedId()";
+    $set_primary_key_field = "o.{$t->pfield->model_field_name} = res.LastInsertId";
+    $cr_exec = "res, err :=";
     if ($t->model_name == "TermRelationship") {
         $set_primary_key_field = "";
+        $cr_exec = "_, err =";
+    }
+    // with a lock_version column Update is optimistically locked, it only
+    // matches the row when the version is the one loaded
+    $up_where = $where;
+    $up_args = $up_gn_line;
+    $lock_set = "";
+    $lock_check = "";
+    $lock_bump = "";
+    $up_exec = "_, err =";
+    if ($lock) {
+        $up_exec = "res, err :=";
+        $up_where .= " AND lock_version = '%d'";
+        $up_args .= ", o.LockVersion";
+        $lock_set = "\n        sets = append(sets,`lock_version = lock_version + 1`)";
+        $lock_check = "\n        if res.RowsAffected == 0 {\n            return ErrStaleObject\n        }";
+        $lock_bump = "\n    o.LockVersion++";
     }
-$txt = "// Save is a dynamic saver 'inherited' by all models
+$sets = str_replace("\n    ","\n        ",$sets);
+$txt = "// transaction runs f with the model bound to a transaction of its
+// Adapter, see Adapter.Transaction
+func (o *{$t->model_name}) transaction(f func() error) error {
+    _adapter := o._adapter
+    defer func() { o._adapter = _adapter }()
+    return _adapter.Transaction(func(a Adapter) error {
+        o._adapter = a
+        return f()
+    })
+}
+// Save is a dynamic saver 'inherited' by all models
 func (o *{$t->model_name}) Save() error {
     if o._new == true {
         return o.Create()
     }
-    var sets []string
-    $sets
-    frmt := fmt.Sprintf(\"UPDATE %s SET %s WHERE $where\",o._table,strings.Join(sets,`,`),$up_gn_line)
-    err := o._adapter.Execute(frmt)
-    if err != nil {
-        return err
-    }
-    return nil
+    return o.Update()
 }
 // Update is a dynamic updater, it considers whether or not
 // a field is 'dirty' and needs to be updated. Will only work
-// if you use the Getters and Setters
+// if you use the Getters and Setters, a model with nothing dirty
+// is not written at all
 func (o *{$t->model_name}) Update() error {
-    var sets []string
-    $sets
-    frmt := fmt.Sprintf(\"UPDATE %s SET %s WHERE $where\",o._table,strings.Join(sets,`,`),$up_gn_line)
-    err := o._adapter.Execute(frmt)
+    if o.IsDirty() == false {
+        return nil
+    }
+    err := o.transaction(func() error {
+        err := runCallbacks(o._adapter,`{$t->model_name}`,BeforeSave,o)
+        if err != nil {
+            return err
+        }
+        err = o.Validate()
+        if err != nil {
+            return err
+        }
+        var sets []string
+        changes := make(map[string]string)
+        $sets$lock_set
+        err = recordAudit(o._adapter,o._table,o._pkey,o.$pkeyname,AuditUpdate,changes)
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf(\"UPDATE %s SET %s WHERE $up_where\",o._table,strings.Join(sets,`,`),$up_args)
+        $up_exec o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }$lock_check
+        return runCallbacks(o._adapter,`{$t->model_name}`,AfterSave,o)
+    })
     if err != nil {
         return err
-    }
+    }$lock_bump
+    o.ResetDirty()
     return nil
 }
 // Create inserts the model. Calling Save will call this function
 // automatically for new models
 func (o *{$t->model_name}) Create() error {
-    frmt := fmt.Sprintf(\"INSERT INTO %s ($cr_col_line) VALUES ($cr_val_line)\",o._table,$cr_gn_line)
-    err := o._adapter.Execute(frmt)
+    err := o.transaction(func() error {
+        err := runCallbacks(o._adapter,`{$t->model_name}`,BeforeSave,o)
+        if err != nil {
+            return err
+        }
+        err = runCallbacks(o._adapter,`{$t->model_name}`,BeforeCreate,o)
+        if err != nil {
+            return err
+        }
+        err = o.Validate()
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf(\"INSERT INTO %s ($cr_col_line) VALUES ($cr_val_line)\",o._table,$cr_gn_line)
+        $cr_exec o._adapter.Execute(frmt)
+        if err != nil {
+            return o._adapter.Oops(fmt.Sprintf(`%s led to %s`,frmt,err))
+        }
+        $set_primary_key_field
+        err = recordAudit(o._adapter,o._table,o._pkey,o.$pkeyname,AuditCreate,map[string]string{{$audit_values}
+        })
+        if err != nil {
+            return err
+        }
+        err = runCallbacks(o._adapter,`{$t->model_name}`,AfterCreate,o)
+        if err != nil {
+            return err
+        }
+        return runCallbacks(o._adapter,`{$t->model_name}`,AfterSave,o)
+    })
     if err != nil {
-        return o._adapter.Oops(fmt.Sprintf(`%s led to %s`,frmt,err))
+        return err
     }
-    $set_primary_key_field
     o._new = false
+    o.ResetDirty()
     return nil
 }
+// Delete removes the model from the database
+func (o *{$t->model_name}) Delete() error {
+    return o.transaction(func() error {
+        err := runCallbacks(o._adapter,`{$t->model_name}`,BeforeDelete,o)
+        if err != nil {
+            return err
+        }
+        err = recordAudit(o._adapter,o._table,o._pkey,o.$pkeyname,AuditDelete,nil)
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf(\"DELETE FROM %s WHERE $where\",o._table,$up_gn_line)
+        _, err = o._adapter.Execute(frmt)
+        return err
+    })
+}
 ";   
     return $txt; 
 }
@@ -1,4 +1,6 @@
 <?php
 
 include "_save.php";
-include "updaters.php";
\ No newline at end of file
+include "validate.php";
+include "updaters.php";
+include "dirty.php";
\ No newline at end of file
@@ -0,0 +1,68 @@
+<?php
+if ( ! function_exists('_dirty') ) {
+function _dirty($t) {
+    $fields = "";
+    $resets = "";
+    $copies = "";
+    $origs = "";
+    foreach ( $t->fields as $tf) {
+        if (isPrimaryKey($tf)) {
+            continue;
+        }
+        $gfn = convertFieldName($tf->Field);
+        $fields .= "
+    if o.{$tf->dirty_marker} == true {
+        fields = append(fields,`{$tf->Field}`)
+    }";
+        $resets .= "
+    o.{$tf->dirty_marker} = false";
+        // DateTimes are pointers, the snapshot needs its own
+        if ($tf->go_type == "*DateTime") {
+            $copies .= "
+    if o.$gfn != nil {
+        _$gfn := *o.$gfn
+        orig.$gfn = &_$gfn
+    }";
+        }
+        $origs .= "
+    case `{$tf->Field}`:
+        return auditValue(o._orig.$gfn),true";
+    }
+$txt = "// IsDirty returns true when a field was set through its setter since
+// the model was loaded or last saved
+func (o *{$t->model_name}) IsDirty() bool {
+    return len(o.DirtyFields()) > 0
+}
+// DirtyFields returns the columns set through their setters since the
+// model was loaded or last saved, in the order of the table
+func (o *{$t->model_name}) DirtyFields() []string {
+    var fields []string$fields
+    return fields
+}
+// ResetDirty clears the dirty markers and takes a snapshot of the
+// current values as the originals, it is called after loading and
+// saving. The snapshot is reused so loading rows into the same model
+// does not allocate a new one each time.
+func (o *{$t->model_name}) ResetDirty() {{$resets}
+    if o._orig == nil {
+        o._orig = &{$t->model_name}{}
+    }
+    orig := o._orig
+    *orig = *o
+    orig._orig = nil$copies
+}
+// Original returns a column as it was when the model was loaded or last
+// saved, formatted like the audit log, and false for new models
+func (o *{$t->model_name}) Original(column string) (string,bool) {
+    if o._orig == nil {
+        return ``,false
+    }
+    switch column {{$origs}
+    }
+    return ``,false
+}
+";
+    return $txt;
+}
+}
+puts(_dirty($t));
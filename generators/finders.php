@@ -20,7 +20,7 @@ foreach($t->fields as $f) {
     if (isPrimaryKey($f) && $t->model_name == "TermRelationship") {
         $fname = "Find";
         $rtype = "bool"; //i.e. we set the current model
-        $from_map_body .= "\t_" . $f->model_field_name . ",err := m[\"{$f->Field}\"].As" . ucfirst($f->go_type). "()\n";
+        $from_map_body .= "\t_" . $f->model_field_name . ",err := r.Get(\"{$f->Field}\").As" . ucfirst($f->go_type). "()\n";
         $from_map_body .= "\tif err != nil {\n \t\treturn o._adapter.Oops(fmt.Sprintf(`%s`,err))\n\t}\n";
         $from_map_body .= "\to." . $f->model_field_name . " = _" . $f->model_field_name . "\n";
         $from_model_body .= "\to.{$f->model_field_name} = m.{$f->model_field_name}\n";
@@ -30,9 +30,9 @@ foreach($t->fields as $f) {
     $scol = $f->Field;
     // these are here just to save having to loop
     if ( $f->go_type == "*DateTime" ) {
-        $from_map_body .= "\t_" . $f->model_field_name . ",err := m[\"{$f->Field}\"].As" . ucfirst(substr($f->go_type,1)). "()\n";
+        $from_map_body .= "\t_" . $f->model_field_name . ",err := r.Get(\"{$f->Field}\").As" . ucfirst(substr($f->go_type,1)). "()\n";
     } else {
-        $from_map_body .= "\t_" . $f->model_field_name . ",err := m[\"{$f->Field}\"].As" . ucfirst($f->go_type). "()\n";
+        $from_map_body .= "\t_" . $f->model_field_name . ",err := r.Get(\"{$f->Field}\").As" . ucfirst($f->go_type). "()\n";
     }
     $from_model_body .= "\to.{$f->model_field_name} = m.{$f->model_field_name}\n";
     
@@ -105,6 +105,8 @@ if ( $fname == "Find" ) {
         return false, o._adapter.Oops(`not found`)
     }
     o.From{$t->model_name}(_modelSlice[0])
+    o._new = false
+    o.ResetDirty()
     return true,nil
 ";
 } else {
@@ -127,7 +129,13 @@ if ($t->model_name == "TermRelationship") {
 puts("
 // FromDBValueMap Converts a DBValueMap returned from Adapter.Query to a {$t->model_name}
 func (o *{$t->model_name}) FromDBValueMap(m map[string]DBValue) error {
+    return o.FromRow(DBValueMap(m))
+}
+// FromRow Converts a Row, e.g. from Adapter.QueryEach, to a {$t->model_name}
+func (o *{$t->model_name}) FromRow(r Row) error {
 $from_map_body
+    o._new = false
+    o.ResetDirty()
  \treturn nil
 }
 // From{$t->model_name} A kind of Clone function for {$t->model_name}
@@ -139,4 +147,17 @@ func (o *{$t->model_name}) Reload() error {
     _,err := $find_line
     return err
 }
+// Each{$t->model_name} runs f for every {$t->model_name} q selects, reading the rows
+// one at a time with Adapter.QueryEach. The {$t->model_name} given to f is
+// reused for the next row, copy it with From{$t->model_name} to keep it.
+func Each{$t->model_name}(a Adapter, q string, f func(*{$t->model_name}) error) error {
+    o := New{$t->model_name}(a)
+    return a.QueryEach(q,func(r Row) error {
+        err := o.FromRow(r)
+        if err != nil {
+            return err
+        }
+        return f(o)
+    })
+}
 ");
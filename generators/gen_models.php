@@ -206,9 +206,15 @@ puts("package main");
 puts('import (
     "database/sql"
     "fmt"
-    _ "github.com/go-sql-driver/mysql" // This is standard for this library.
+    mysqldriver "github.com/go-sql-driver/mysql" // This is standard for this library.
+    "database/sql/driver"
+    "net"
+    "net/url"
+    "crypto/tls"
+    "crypto/x509"
     "strconv"
-    "gopkg.in/yaml.v2"
+    "gopkg.in/yaml.v3"
+    "reflect"
     "regexp"
     "errors"
     "os"
@@ -216,7 +222,12 @@ puts('import (
     "io/ioutil"
     "bufio"
     "log"
+    "log/slog"
+    "context"
     "strings"
+    "sort"
+    "sync"
+    "time"
 )
 
 ');
@@ -240,7 +251,8 @@ type {$t->model_name} struct {
     _adapter Adapter
     _pkey string // $_ii The name of the primary key in this table
     _conds []string
-    _new bool");
+    _new bool
+    _orig *{$t->model_name}");
     include "models/interface_fields.php";
     foreach($t->fields as $f) {
         $fname = $f->model_field_name;
@@ -268,7 +280,7 @@ func New{$t->model_name}(a Adapter) *{$t->model_name} {
     o._table = fmt.Sprintf(\"%s{$t->dname}\",a.DatabasePrefix())
     o._adapter = a
     o._pkey = \"{$t->pfield->Field}\"
-    o._new = false
+    o._new = true
     return &o
 }
 ";
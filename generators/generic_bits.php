@@ -17,18 +17,24 @@ type Adapter interface {
     Open(string,string,string,string) error
     Close()
     Query(string) ([]map[string]DBValue,error)
-    Execute(string) error
+    Execute(string) (ExecResult,error)
     LastInsertedId() int64
     AffectedRows() int64
     DatabasePrefix() string
     LogInfo(string)
     LogError(error)
     LogDebug(string)
+    Log(slog.Level,string,...slog.Attr)
     SetLogs(io.Writer)
     SetLogFilter(LogFilter)
     Oops(string) error
     SafeString(string)string
     NewDBValue() DBValue
+    Transaction(func(Adapter) error) error
+    Upsert(string,string,[]string,[]string,[][]string) ([]int64,error)
+    QueryEach(string,func(Row) error) error
+    Health() Health
+    Stats() []StatementStats
 }
 ");
 include "mysql_adapter.php";
@@ -45,6 +51,27 @@ type DBValue interface {
     AsDateTime() (*DateTime,error)
     SetInternalValue(string,string)
 }
+// Row is a row of results read by Adapter.QueryEach, the DBValues it
+// returns are only valid until the function given to QueryEach returns
+type Row interface {
+    Columns() []string
+    Get(string) DBValue
+}
+// DBValueMap is a row returned by Adapter.Query, it is also a Row
+type DBValueMap map[string]DBValue
+// Columns returns the columns of the row, sorted by name
+func (m DBValueMap) Columns() []string {
+    var columns []string
+    for c := range m {
+        columns = append(columns,c)
+    }
+    sort.Strings(columns)
+    return columns
+}
+// Get returns the value of a column, or nil when there is no such column
+func (m DBValueMap) Get(column string) DBValue {
+    return m[column]
+}
 // MysqlValue Implements DBValue for MySQL, you'll generally
 // not interact directly with this type, but it
 // is there for special cases.
@@ -99,6 +126,10 @@ func (v *MysqlValue) AsFloat64() (float64,error) {
 // parsing may fail.
 func (v *MysqlValue) AsDateTime() (*DateTime,error) {
     dt := NewDateTime(v._adapter)
+    if v._v == `` {
+        // NULL columns come back empty, leave the DateTime zeroed
+        return dt,nil
+    }
     err := dt.FromString(v._v)
     if err != nil {
         return &DateTime{}, err
@@ -130,10 +161,13 @@ type DateTime struct {
     Seconds int
     _adapter Adapter
 }
+// _dateTimeRegexp matches the DATETIME strings MySQL returns, it is
+// compiled once as FromString runs for every DATETIME read
+var _dateTimeRegexp = regexp.MustCompile(\"(?P<year>[\\\d]{4})-(?P<month>[\\\d]{2})-(?P<day>[\\\d]{2}) (?P<hours>[\\\d]{2}):(?P<minutes>[\\\d]{2}):(?P<seconds>[\\\d]{2})\")
 // FromString Converts a string like 0000-00-00 00:00:00 into a DateTime
 func (d *DateTime) FromString(s string) error {
     es := s
-    re := regexp.MustCompile(\"(?P<year>[\\\d]{4})-(?P<month>[\\\d]{2})-(?P<day>[\\\d]{2}) (?P<hours>[\\\d]{2}):(?P<minutes>[\\\d]{2}):(?P<seconds>[\\\d]{2})\")
+    re := _dateTimeRegexp
     n1 := re.SubexpNames()
     ir2 := re.FindAllStringSubmatch(es, -1)
     if len(ir2) == 0 {
@@ -194,6 +228,412 @@ func (d *DateTime) ToString() string {
 func (d *DateTime) String() string {
     return d.ToString()
 }
+// IsZero Returns true when the DateTime was never set, i.e. it came
+// from a NULL column or was never filled out.
+func (d *DateTime) IsZero() bool {
+    if d == nil {
+        return true
+    }
+    return d.Year == 0 && d.Month == 0 && d.Day == 0 && d.Hours == 0 && d.Minutes == 0 && d.Seconds == 0
+}
+// ToTime Converts the DateTime into a time.Time in UTC
+func (d *DateTime) ToTime() time.Time {
+    return time.Date(d.Year,time.Month(d.Month),d.Day,d.Hours,d.Minutes,d.Seconds,0,time.UTC)
+}
+// FromTime Sets the DateTime from a time.Time
+func (d *DateTime) FromTime(t time.Time) {
+    d.Year = t.Year()
+    d.Month = int(t.Month())
+    d.Day = t.Day()
+    d.Hours = t.Hour()
+    d.Minutes = t.Minute()
+    d.Seconds = t.Second()
+}
+// dateTimeSQL Quotes a DateTime for use in a query, a nil or
+// zero DateTime is written as NULL
+func dateTimeSQL(d *DateTime) string {
+    if d.IsZero() {
+        return `NULL`
+    }
+    return fmt.Sprintf(`'%s'`,d.ToString())
+}
+// ValidationErrors is returned by Validate, and so by Create and Save,
+// when a model is invalid. It maps field names to what is wrong with them.
+type ValidationErrors map[string][]string
+// Add records a problem with a field
+func (e ValidationErrors) Add(field, msg string) {
+    e[field] = append(e[field],msg)
+}
+// Error lists the problems ordered by field name
+func (e ValidationErrors) Error() string {
+    var fields []string
+    for f := range e {
+        fields = append(fields,f)
+    }
+    sort.Strings(fields)
+    var parts []string
+    for _,f := range fields {
+        parts = append(parts,fmt.Sprintf(`%s %s`,f,strings.Join(e[f],` and `)))
+    }
+    return strings.Join(parts,`, `)
+}
+// ValidatorFunc checks a model before Create or Save write it to the
+// database, returning an error stops the write. Return ValidationErrors
+// to report problems with fields, they are merged with the problems
+// found by the other validators.
+type ValidatorFunc func(a Adapter, model interface{}) error
+var _validators = make(map[string][]ValidatorFunc)
+// RegisterValidator adds a ValidatorFunc for a model, by model name, e.g.
+// Position. Validators run in the order they were registered.
+func RegisterValidator(model string, v ValidatorFunc) {
+    _validators[model] = append(_validators[model],v)
+}
+// runValidators runs the validators registered for a model, adding their
+// ValidationErrors to errs. Any other error is returned at once.
+func runValidators(a Adapter, model string, o interface{}, errs ValidationErrors) error {
+    for _,v := range _validators[model] {
+        err := v(a,o)
+        if ve, ok := err.(ValidationErrors); ok {
+            for f,msgs := range ve {
+                for _,msg := range msgs {
+                    errs.Add(f,msg)
+                }
+            }
+            continue
+        }
+        if err != nil {
+            return err
+        }
+    }
+    if len(errs) > 0 {
+        return errs
+    }
+    return nil
+}
+// The lifecycle events a CallbackFunc can be registered for. Create runs
+// BeforeSave, BeforeCreate, AfterCreate and AfterSave in that order, an
+// update through Save or Update runs BeforeSave and AfterSave and the
+// single column UpdateXxx runs AfterSave.
+const (
+    BeforeCreate = `before_create`
+    AfterCreate = `after_create`
+    BeforeSave = `before_save`
+    AfterSave = `after_save`
+    BeforeDelete = `before_delete`
+)
+// CallbackFunc is run by the generated Create, Save, Update, UpdateXxx and
+// Delete methods inside the transaction of the write. a is bound to that
+// transaction, returning an error rolls it back.
+type CallbackFunc func(a Adapter, model interface{}) error
+var _callbacks = make(map[string]map[string][]CallbackFunc)
+// RegisterCallback adds a CallbackFunc for an event of a model, by model
+// name, e.g. RegisterCallback(`Position`,BeforeCreate,f)
+func RegisterCallback(model, event string, f CallbackFunc) {
+    if _callbacks[model] == nil {
+        _callbacks[model] = make(map[string][]CallbackFunc)
+    }
+    _callbacks[model][event] = append(_callbacks[model][event],f)
+}
+// runCallbacks runs the callbacks registered for an event of a model
+// and returns the first error
+func runCallbacks(a Adapter, model, event string, o interface{}) error {
+    for _,f := range _callbacks[model][event] {
+        err := f(a,o)
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+// The actions passed to an AuditFunc
+const (
+    AuditCreate = `create`
+    AuditUpdate = `update`
+    AuditDelete = `delete`
+)
+// AuditFunc records a change to the row of table whose primary key pkey
+// is id. changes maps the columns written to their new values and is nil
+// for a delete. It runs inside the transaction of the write, before the
+// row is changed for an update or a delete and after it is inserted for
+// a create, returning an error rolls the write back.
+type AuditFunc func(a Adapter, table, pkey string, id int64, action string, changes map[string]string) error
+var _auditor AuditFunc
+// SetAuditor sets the AuditFunc called by the generated Create, Update,
+// UpdateXxx and Delete methods, nil turns auditing off
+func SetAuditor(f AuditFunc) {
+    _auditor = f
+}
+// recordAudit calls the AuditFunc when there is one
+func recordAudit(a Adapter, table, pkey string, id int64, action string, changes map[string]string) error {
+    if _auditor == nil {
+        return nil
+    }
+    return _auditor(a,table,pkey,id,action,changes)
+}
+// auditValue formats a field for an AuditFunc, DateTimes the way they are
+// stored and nil DateTimes as the empty string
+func auditValue(v interface{}) string {
+    if d, ok := v.(*DateTime); ok {
+        if d.IsZero() {
+            return ``
+        }
+        return d.ToString()
+    }
+    return fmt.Sprintf(`%v`,v)
+}
+// ErrStaleObject is returned by Save and Update on models with a
+// lock_version column when the row was changed since the model was
+// loaded. Reload the model and apply the change again.
+var ErrStaleObject = errors.New(`the row was changed since it was loaded`)
+// ConfigError is a problem with an Adapter configuration, Line and
+// Column are those of the offending YAML node when known
+type ConfigError struct {
+    File string
+    Line int
+    Column int
+    Msg string
+}
+// Error renders the error as file:line:column: message
+func (e *ConfigError) Error() string {
+    var at []string
+    if e.File != `` {
+        at = append(at,e.File)
+    }
+    if e.Line > 0 {
+        at = append(at,strconv.Itoa(e.Line),strconv.Itoa(e.Column))
+    }
+    if len(at) == 0 {
+        return e.Msg
+    }
+    return strings.Join(at,`:`) + `: ` + e.Msg
+}
+// ConfigErrors are all the problems found in a configuration
+type ConfigErrors []*ConfigError
+// Error renders one error per line
+func (es ConfigErrors) Error() string {
+    var lines []string
+    for _, e := range es {
+        lines = append(lines,e.Error())
+    }
+    return strings.Join(lines,\"\\n\")
+}
+func configError(fname string, n *yaml.Node, format string, args ...interface{}) *ConfigError {
+    return &ConfigError{File: fname, Line: n.Line, Column: n.Column, Msg: fmt.Sprintf(format,args...)}
+}
+// ExecResult is what Execute reports about the statement it ran, unlike
+// LastInsertedId and AffectedRows it belongs to the caller alone
+type ExecResult struct {
+    // LastInsertId is the AUTO_INCREMENT id of an INSERT
+    LastInsertId int64
+    // RowsAffected is the number of rows changed
+    RowsAffected int64
+}
+// The retry settings of a new MysqlAdapter, see MysqlAdapter.Retries
+const (
+    DefaultRetries = 2
+    DefaultRetryBackoff = 100 * time.Millisecond
+)
+// Health is the report of Adapter.Health on the database connection
+type Health struct {
+    // Err is why the database cannot be used, nil when it can
+    Err error
+    // Latency is how long the ping took
+    Latency time.Duration
+    // Stats are the statistics of the connection pool
+    Stats sql.DBStats
+}
+// OK returns true when the database can be used
+func (h Health) OK() bool {
+    return h.Err == nil
+}
+// String renders the report on one line
+func (h Health) String() string {
+    if h.OK() == false {
+        return fmt.Sprintf(`unavailable: %s`,h.Err)
+    }
+    return fmt.Sprintf(`ok, ping %s, %d open connections (%d in use, %d idle), waited %d times`,
+        h.Latency,h.Stats.OpenConnections,h.Stats.InUse,h.Stats.Idle,h.Stats.WaitCount)
+}
+// StatementStats are the statistics of the statements sharing a Shape
+type StatementStats struct {
+    // Shape is the statement with its values replaced, see NormalizeSQL
+    Shape string
+    Count int64
+    Errors int64
+    // Slow is how many took at least the SlowQuery of the Adapter
+    Slow int64
+    // Rows is the total of the rows read or changed
+    Rows int64
+    Total time.Duration
+    Max time.Duration
+}
+// Mean returns the average duration of the statements
+func (s StatementStats) Mean() time.Duration {
+    if s.Count == 0 {
+        return 0
+    }
+    return s.Total / time.Duration(s.Count)
+}
+// String renders the statistics on one line
+func (s StatementStats) String() string {
+    return fmt.Sprintf(`count %d total %s mean %s max %s rows %d errors %d slow %d: %s`,
+        s.Count,s.Total,s.Mean(),s.Max,s.Rows,s.Errors,s.Slow,s.Shape)
+}
+var (
+    _sqlStringRegexp = regexp.MustCompile(`'(?:[^'\\\\]|\\\\.|'')*'|\"(?:[^\"\\\\]|\\\\.|\"\")*\"`)
+    _sqlNumberRegexp = regexp.MustCompile(`\\b\\d+(?:\\.\\d+)?\\b`)
+    _sqlListRegexp = regexp.MustCompile(`\\(\\s*\\?(?:\\s*,\\s*\\?)*\\s*\\)`)
+    _sqlListsRegexp = regexp.MustCompile(`\\(\\.\\.\\.\\)(?:\\s*,\\s*\\(\\.\\.\\.\\))+`)
+    _sqlSpaceRegexp = regexp.MustCompile(`\\s+`)
+)
+// NormalizeSQL returns the shape of q: the literal strings and numbers
+// become ?, lists of them (...) and runs of such lists (...),... so
+// that the statements of a finder share one shape whatever the values.
+func NormalizeSQL(q string) string {
+    q = _sqlStringRegexp.ReplaceAllString(q,`?`)
+    q = _sqlNumberRegexp.ReplaceAllString(q,`?`)
+    q = _sqlListRegexp.ReplaceAllString(q,`(...)`)
+    q = _sqlListsRegexp.ReplaceAllString(q,`(...),...`)
+    q = _sqlSpaceRegexp.ReplaceAllString(q,` `)
+    return strings.TrimSpace(q)
+}
+// MaskSQL returns q with its literal strings and numbers replaced by ?,
+// unlike NormalizeSQL the statement keeps its length and layout
+func MaskSQL(q string) string {
+    q = _sqlStringRegexp.ReplaceAllString(q,`?`)
+    return _sqlNumberRegexp.ReplaceAllString(q,`?`)
+}
+var _dsnCredentialsRegexp = regexp.MustCompile(`([^\\s:@/(]+):\\S*@((?:tcp|unix)\\(|/)`)
+// RedactDSN replaces the password of the DSNs in s with ***
+func RedactDSN(s string) string {
+    return _dsnCredentialsRegexp.ReplaceAllString(s,`\${1}:***@\${2}`)
+}
+// finalError marks an error of a statement which may have been applied,
+// retry gives up on it whether it is transient or not
+type finalError struct {
+    err error
+}
+func (e *finalError) Error() string {
+    return e.err.Error()
+}
+func (e *finalError) Unwrap() error {
+    return e.err
+}
+func final(err error) error {
+    return &finalError{err}
+}
+// isRolledBack returns true for the errors after which the server has
+// rolled the statement back: deadlocks and lock wait timeouts
+func isRolledBack(err error) bool {
+    var me *mysqldriver.MySQLError
+    return errors.As(err,&me) && (me.Number == 1205 || me.Number == 1213)
+}
+// isTransient returns true for the errors worth retrying: bad or lost
+// connections, network errors, deadlocks, lock wait timeouts and too
+// many connections
+func isTransient(err error) bool {
+    if errors.Is(err,driver.ErrBadConn) || errors.Is(err,mysqldriver.ErrInvalidConn) {
+        return true
+    }
+    var me *mysqldriver.MySQLError
+    if errors.As(err,&me) {
+        return me.Number == 1040 || me.Number == 1205 || me.Number == 1213
+    }
+    var ne net.Error
+    return errors.As(err,&ne)
+}
+// DefaultBatchSize is the number of rows Upsert writes per statement
+// when the Adapter does not configure one
+const DefaultBatchSize = 500
+// upsertRows implements Adapter.Upsert on top of Transaction, Execute
+// and Query. values are SQL literals in the order of columns, keys are
+// the columns of a unique key of table and a row whose keys match a
+// stored row updates it. The rows are written with multi-row INSERT ...
+// ON DUPLICATE KEY UPDATE statements of at most batchSize rows, in one
+// transaction, and the values under pkey of the rows are read back and
+// returned in the order of values.
+func upsertRows(a Adapter, batchSize int, table, pkey string, keys, columns []string, values [][]string) ([]int64,error) {
+    if batchSize <= 0 {
+        batchSize = DefaultBatchSize
+    }
+    keyIndex := make([]int,len(keys))
+    var quotedKeys []string
+    for i,k := range keys {
+        keyIndex[i] = -1
+        for j,c := range columns {
+            if c == k {
+                keyIndex[i] = j
+            }
+        }
+        if keyIndex[i] < 0 {
+            return nil,a.Oops(fmt.Sprintf(`the key %s of %s is not one of the columns`,k,table))
+        }
+        quotedKeys = append(quotedKeys,fmt.Sprintf(\"`%s`\",k))
+    }
+    var quoted, updates []string
+    for _,c := range columns {
+        quoted = append(quoted,fmt.Sprintf(\"`%s`\",c))
+        updates = append(updates,fmt.Sprintf(\"`%s` = VALUES(`%s`)\",c,c))
+    }
+    ids := make([]int64,len(values))
+    err := a.Transaction(func(ta Adapter) error {
+        for start := 0; start < len(values); start += batchSize {
+            end := start + batchSize
+            if end > len(values) {
+                end = len(values)
+            }
+            var rows, matches, rowKeys []string
+            for _,row := range values[start:end] {
+                if len(row) != len(columns) {
+                    return ta.Oops(fmt.Sprintf(`%v does not have a value for each of %v`,row,columns))
+                }
+                rows = append(rows,`(` + strings.Join(row,`, `) + `)`)
+                var k, plain []string
+                for _,i := range keyIndex {
+                    k = append(k,row[i])
+                    plain = append(plain,strings.Trim(row[i],`'`))
+                }
+                matches = append(matches,`(` + strings.Join(k,`, `) + `)`)
+                rowKeys = append(rowKeys,strings.Join(plain,\"\\x00\"))
+            }
+            q := fmt.Sprintf(`INSERT INTO %s (%s) VALUES %s ON DUPLICATE KEY UPDATE %s`,table,strings.Join(quoted,`, `),strings.Join(rows,`, `),strings.Join(updates,`, `))
+            _, err := ta.Execute(q)
+            if err != nil {
+                return err
+            }
+            q = fmt.Sprintf(\"SELECT `%s`, %s FROM %s WHERE (%s) IN (%s)\",pkey,strings.Join(quotedKeys,`, `),table,strings.Join(quotedKeys,`, `),strings.Join(matches,`, `))
+            results, err := ta.Query(q)
+            if err != nil {
+                return err
+            }
+            found := make(map[string]int64)
+            for _,result := range results {
+                var plain []string
+                for _,k := range keys {
+                    v,_ := result[k].AsString()
+                    plain = append(plain,v)
+                }
+                id,err := result[pkey].AsInt64()
+                if err != nil {
+                    return err
+                }
+                found[strings.Join(plain,\"\\x00\")] = id
+            }
+            for i,k := range rowKeys {
+                id,ok := found[k]
+                if !ok {
+                    return ta.Oops(fmt.Sprintf(`could not read back row %d written to %s`,start + i,table))
+                }
+                ids[start + i] = id
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return nil,err
+    }
+    return ids,nil
+}
 // NewDateTime Returns a basic DateTime value
 func NewDateTime(a Adapter) *DateTime {
     d := &DateTime{_adapter: a}
@@ -18,7 +18,17 @@ func TestMysqlAdapterFromYAML(t *testing.T) {
         a.Pass != `rootpass` ||
         a.Host != `localhost` ||
         a.Database != `my_db` ||
-        a.DBPrefix != `wp_`) {
+        a.DBPrefix != `wp_` ||
+        a.BatchSize != 250 ||
+        a.MaxOpenConns != 4 ||
+        a.MaxIdleConns != 2 ||
+        a.ConnMaxLifetime != time.Minute ||
+        a.PingOnBorrow != true ||
+        a.Retries != 1 ||
+        a.RetryBackoff != time.Millisecond ||
+        a.Port != 3306 ||
+        a.Charset != `utf8mb4` ||
+        a.Params[`loc`] != `UTC`) {
         $fail(`did not fully apply yaml file %+v`,a)
     }
 }
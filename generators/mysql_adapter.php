@@ -11,21 +11,73 @@ type MysqlAdapter struct {
     Pass string `yaml:\"pass\"`
     // The database name
     Database string `yaml:\"database\"`
+    // The TCP port, 3306 when it is not set
+    Port int `yaml:\"port\"`
+    // The path of a unix socket, used instead of Host and Port when set
+    Socket string `yaml:\"socket\"`
+    // The TLS mode: true, false, skip-verify or preferred. With TLSCA
+    // set the server certificate is checked against that CA instead.
+    TLS string `yaml:\"tls\"`
+    // The path of a PEM file with the CA certificate(s) of the server
+    TLSCA string `yaml:\"tls_ca\"`
+    // The connection character set and collation
+    Charset string `yaml:\"charset\"`
+    Collation string `yaml:\"collation\"`
+    // Any other parameter of the driver or system variable, for
+    // instance loc: \"UTC\". parseTime is refused, the models read
+    // DATETIMEs as the strings MySQL returns.
+    Params map[string]string `yaml:\"params\"`
     // A prefix, if any - can be blank
     DBPrefix string `yaml:\"prefix\"`
+    // The number of rows each statement of Upsert writes, DefaultBatchSize
+    // when it is not set
+    BatchSize int `yaml:\"batch_size\"`
+    // The connection pool, zero leaves the database/sql default
+    MaxOpenConns int `yaml:\"max_open_conns\"`
+    MaxIdleConns int `yaml:\"max_idle_conns\"`
+    ConnMaxLifetime time.Duration `yaml:\"conn_max_lifetime\"`
+    // PingOnBorrow pings the database before each statement run outside
+    // a transaction, so a dead connection is noticed and retried
+    PingOnBorrow bool `yaml:\"ping_on_borrow\"`
+    // Retries is how often a statement failing with a transient error
+    // is retried, waiting RetryBackoff and then twice as long each time
+    Retries int `yaml:\"retries\"`
+    RetryBackoff time.Duration `yaml:\"retry_backoff\"`
+    // The least level logged: debug, info, warn or error
+    LogLevel string `yaml:\"log_level\"`
+    // text for the [INFO]: lines of SetLogs, json for one JSON object
+    // per line
+    LogFormat string `yaml:\"log_format\"`
+    // Statements taking at least SlowQuery are logged at WARN and to
+    // the slow log, zero turns this off
+    SlowQuery time.Duration `yaml:\"slow_query\"`
+    // MaskQueries logs the statements with their values replaced by ?,
+    // see MaskSQL, and the values quoted in their errors masked too
+    MaskQueries bool `yaml:\"mask_queries\"`
     _infoLog *log.Logger
+    _warnLog *log.Logger
     _errorLog *log.Logger
     _debugLog *log.Logger
+    _logger *slog.Logger
+    _level *slog.LevelVar
+    _slowLog *log.Logger
+    _stats *queryStats
     _conn *sql.DB
-    _lid int64
-    _cnt int64
+    _last *lastResult
     _opened bool
+    _tx *sql.Tx
     _logFilter LogFilter
     _safeStringFilter SafeStringFilter
 }
 // NewMysqlAdapter returns a pointer to MysqlAdapter
 func NewMysqlAdapter(pre string) *MysqlAdapter {
-    return &MysqlAdapter{DBPrefix: pre}
+    a := &MysqlAdapter{DBPrefix: pre, Retries: DefaultRetries, RetryBackoff: DefaultRetryBackoff}
+    a.SetLogLevel(slog.LevelDebug)
+    a._stats = newQueryStats()
+    a._last = &lastResult{}
+    // Open may already log its retries
+    a.SetLogs(ioutil.Discard)
+    return a
 } 
 // NewMysqlAdapterEx sets everything up based on your YAML config
 // Args: fname is a string path to a YAML config file
@@ -36,13 +88,42 @@ func NewMysqlAdapter(pre string) *MysqlAdapter {
 //     pass: \"dbuserpass\"
 //     database: \"my_db\"
 //     prefix: \"wp_\"
+//     batch_size: 500
+//     max_open_conns: 10
+//     max_idle_conns: 5
+//     conn_max_lifetime: 5m
+//     ping_on_borrow: true
+//     retries: 2
+//     retry_backoff: 100ms
+//     log_level: info
+//     log_format: json
+//     slow_query: 250ms
+//     mask_queries: true
+//     port: 3306
+//     socket: /var/run/mysqld/mysqld.sock
+//     tls: true
+//     tls_ca: /etc/mysql/ca.pem
+//     charset: utf8mb4
+//     collation: utf8mb4_unicode_ci
+//     params:
+//         loc: UTC
+// The file may instead hold several named profiles, each a mapping
+// like the one above, and fname selects one with file#profile:
+//     dev:
+//         host: \"localhost\"
+//         ...
+//     prod:
+//         host: \"db.example.com\"
+//         ...
+// The environment overrides the file, see FromEnv.
 func NewMysqlAdapterEx(fname string) (*MysqlAdapter,error) {
     a := NewMysqlAdapter(``)
+    fname, profile := splitProfile(fname)
     y,err := fileGetContents(fname)
     if err != nil {
         return nil,err
     }
-    err = a.FromYAML(y)
+    err = a.loadYAML(fname,y,profile,EnvPrefix)
     if err != nil {
         return nil,err
     }
@@ -68,6 +149,16 @@ func (a *MysqlAdapter) SafeString(s string) string {
 func (a *MysqlAdapter) SetInfoLog(t io.Writer) {
     a._infoLog = log.New(t,`[INFO]:`,log.Ldate|log.Ltime|log.Lshortfile)
 }
+// SetWarnLog Sets the _warnLog to the io.Writer, use ioutil.Discard if you
+// don't want this one at all.
+func (a *MysqlAdapter) SetWarnLog(t io.Writer) {
+    a._warnLog = log.New(t,`[WARN]:`,log.Ldate|log.Ltime|log.Lshortfile)
+}
+// SetSlowLog Sets the _slowLog to the io.Writer, the statements slower
+// than SlowQuery are written to it. SetLogs leaves it alone.
+func (a *MysqlAdapter) SetSlowLog(t io.Writer) {
+    a._slowLog = log.New(t,`[SLOW]:`,log.Ldate|log.Ltime)
+}
 // SetErrorLog Sets the _errorLog to the io.Writer, use ioutil.Discard if you
 // don't want this one at all.
 func (a *MysqlAdapter) SetErrorLog(t io.Writer) {
@@ -79,43 +170,183 @@ func (a *MysqlAdapter) SetDebugLog(t io.Writer) {
     a._debugLog = log.New(t,`[DEBUG]:`,log.Ldate|log.Ltime|log.Lshortfile)
 }
 // SetLogs Sets ALL logs to the io.Writer, use ioutil.Discard if you
-// don't want this one at all.
+// don't want this one at all. With LogFormat json the records are
+// written as JSON instead of to the tagged logs.
 func (a *MysqlAdapter) SetLogs(t io.Writer) {
     a.SetInfoLog(t)
+    a.SetWarnLog(t)
     a.SetErrorLog(t)
     a.SetDebugLog(t)
+    if a.LogFormat == `json` {
+        a.SetLogHandler(slog.NewJSONHandler(t,&slog.HandlerOptions{Level: slog.LevelDebug}))
+        return
+    }
+    a.SetLogHandler(&tagHandler{a: a})
 }
-// LogInfo Tags the string with INFO and puts it into _infoLog.
-func (a *MysqlAdapter) LogInfo(s string) {
-    if a._logFilter != nil {
-        s = a._logFilter(`INFO`,s)
+// SetLogHandler sends the log records to h, any slog.Handler will do.
+// The level of the Adapter is checked before h sees a record.
+func (a *MysqlAdapter) SetLogHandler(h slog.Handler) {
+    a._logger = slog.New(h)
+}
+// SetLogLevel sets the least level which is logged
+func (a *MysqlAdapter) SetLogLevel(l slog.Level) {
+    if a._level == nil {
+        a._level = new(slog.LevelVar)
     }
-    if s == \"\" {
+    a._level.Set(l)
+}
+// Logger returns the slog.Logger of the Adapter, records logged with it
+// skip the LogFilter
+func (a *MysqlAdapter) Logger() *slog.Logger {
+    return a._logger
+}
+// Log logs msg with the attributes at level. The LogFilter sees the
+// record as a line of text, if it changes the line the line becomes
+// the message and the attributes are dropped. The message and the
+// string attributes are redacted first, see Redact.
+func (a *MysqlAdapter) Log(level slog.Level, msg string, attrs ...slog.Attr) {
+    if a._logger == nil || (a._level != nil && level < a._level.Level()) {
         return
     }
-    a._infoLog.Println(s)
-}
-// LogError Tags the string with ERROR and puts it into _errorLog.
-func (a *MysqlAdapter) LogError(s error) {
+    msg = a.Redact(msg)
+    attrs = append([]slog.Attr(nil),attrs...)
+    for i, at := range attrs {
+        if at.Value.Kind() == slog.KindString {
+            attrs[i].Value = slog.StringValue(a.Redact(at.Value.String()))
+        }
+    }
     if a._logFilter != nil {
-        ns := a._logFilter(`ERROR`,fmt.Sprintf(`%s`,s))
-        if ns == `` {
+        line := formatRecord(msg,attrs)
+        s := a._logFilter(levelTag(level),line)
+        if s == `` {
             return
         }
-        a._errorLog.Println(ns)
+        if s != line {
+            msg, attrs = s, nil
+        }
+    }
+    if msg == `` && len(attrs) == 0 {
         return
     }
-    a._errorLog.Println(s)
+    a._logger.LogAttrs(context.Background(),level,msg,attrs...)
+}
+// Redact replaces the password of any DSN in s with ***. Only DSNs are
+// redacted, replacing the password wherever it appears would mangle
+// the logs when it is short or a common word.
+func (a *MysqlAdapter) Redact(s string) string {
+    return RedactDSN(s)
+}
+// maskQuery returns q with its values masked when MaskQueries is set
+func (a *MysqlAdapter) maskQuery(q string) string {
+    if a.MaskQueries {
+        return MaskSQL(q)
+    }
+    return q
+}
+// maskError returns the text of err with its quoted values masked when
+// MaskQueries is set, the driver quotes the offending values
+func (a *MysqlAdapter) maskError(err error) string {
+    if a.MaskQueries {
+        return _sqlStringRegexp.ReplaceAllString(err.Error(),`?`)
+    }
+    return err.Error()
+}
+// LogInfo Tags the string with INFO and puts it into _infoLog.
+func (a *MysqlAdapter) LogInfo(s string) {
+    a.Log(slog.LevelInfo,s)
+}
+// LogError Tags the string with ERROR and puts it into _errorLog.
+func (a *MysqlAdapter) LogError(s error) {
+    a.Log(slog.LevelError,fmt.Sprintf(`%s`,s))
 }
 // LogDebug Tags the string with DEBUG and puts it into _debugLog.
 func (a *MysqlAdapter) LogDebug(s string) {
-    if a._logFilter != nil {
-        s = a._logFilter(`DEBUG`,s)
+    a.Log(slog.LevelDebug,s)
+}
+// levelTag returns the tag of level as the LogFilter sees it
+func levelTag(level slog.Level) string {
+    switch {
+    case level >= slog.LevelError:
+        return `ERROR`
+    case level >= slog.LevelWarn:
+        return `WARN`
+    case level >= slog.LevelInfo:
+        return `INFO`
+    }
+    return `DEBUG`
+}
+// formatRecord renders a record as the message followed by key=value
+// pairs, quoting the values which need it
+func formatRecord(msg string, attrs []slog.Attr) string {
+    var b strings.Builder
+    b.WriteString(msg)
+    for _, at := range attrs {
+        formatAttr(&b,``,at)
     }
-    if s == \"\" {
+    return b.String()
+}
+func formatAttr(b *strings.Builder, group string, at slog.Attr) {
+    v := at.Value.Resolve()
+    if v.Kind() == slog.KindGroup {
+        for _, ga := range v.Group() {
+            formatAttr(b,group + at.Key + `.`,ga)
+        }
         return
     }
-    a._debugLog.Println(s)
+    s := v.String()
+    if s == `` || strings.ContainsAny(s,\" =\\\"\\t\\n\") {
+        s = strconv.Quote(s)
+    }
+    if b.Len() > 0 {
+        b.WriteString(` `)
+    }
+    b.WriteString(group + at.Key + `=` + s)
+}
+// tagHandler is the slog.Handler of SetLogs, it writes each record as
+// a line to the tagged log of its level
+type tagHandler struct {
+    a *MysqlAdapter
+    attrs []slog.Attr
+    group string
+}
+func (h *tagHandler) Enabled(_ context.Context, level slog.Level) bool {
+    return h.a._level == nil || level >= h.a._level.Level()
+}
+func (h *tagHandler) Handle(_ context.Context, r slog.Record) error {
+    attrs := append([]slog.Attr{},h.attrs...)
+    r.Attrs(func(at slog.Attr) bool {
+        if h.group != `` {
+            at.Key = h.group + at.Key
+        }
+        attrs = append(attrs,at)
+        return true
+    })
+    l := h.a._debugLog
+    switch levelTag(r.Level) {
+    case `ERROR`:
+        l = h.a._errorLog
+    case `WARN`:
+        l = h.a._warnLog
+    case `INFO`:
+        l = h.a._infoLog
+    }
+    return l.Output(1,formatRecord(r.Message,attrs))
+}
+func (h *tagHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+    nh := *h
+    nh.attrs = append([]slog.Attr{},h.attrs...)
+    for _, at := range attrs {
+        if h.group != `` {
+            at.Key = h.group + at.Key
+        }
+        nh.attrs = append(nh.attrs,at)
+    }
+    return &nh
+}
+func (h *tagHandler) WithGroup(name string) slog.Handler {
+    nh := *h
+    nh.group = h.group + name + `.`
+    return &nh
 }
 // NewDBValue Creates a new DBValue, mostly used internally, but
 // you may wish to use it in special circumstances.
@@ -126,29 +357,249 @@ func (a *MysqlAdapter) NewDBValue() DBValue {
 func (a *MysqlAdapter) DatabasePrefix() string {
     return a.DBPrefix
 }
-// FromYAML Set the Adapter's members from a YAML file
+// FromYAML Set the Adapter's members from a YAML file, which must
+// hold a single configuration, see LoadYAML
 func (a *MysqlAdapter) FromYAML(b []byte) error {
-    return yaml.Unmarshal(b,a)
+    return a.LoadYAML(b,``)
 }
-// Open Opens the database connection. Be sure to use 
-// a.Close() as closing is NOT handled for you.
-func (a *MysqlAdapter) Open(h,u,p,d string) error {
-    if ( h != \"localhost\") {
-        l := fmt.Sprintf(\"%s:%s@tcp(%s)/%s\",u,p,h,d)
-        tc, err := sql.Open(\"mysql\",l)
-        if err != nil {
-            return a.Oops(fmt.Sprintf(`%s with %s`,err,l))
+// LoadYAML sets the Adapter's members from the profile of a YAML file,
+// when profile is blank the file must hold a single configuration or a
+// single profile. Unknown keys and invalid values are reported as
+// ConfigErrors with their line and column.
+func (a *MysqlAdapter) LoadYAML(b []byte, profile string) error {
+    return a.loadYAML(``,b,profile,``)
+}
+// loadYAML decodes the profile of the file, applies the environment
+// variables starting with envPrefix, when it is not blank, and only then
+// validates the configuration
+func (a *MysqlAdapter) loadYAML(fname string, b []byte, profile string, envPrefix string) error {
+    var doc yaml.Node
+    err := yaml.Unmarshal(b,&doc)
+    if err != nil {
+        return &ConfigError{File: fname, Msg: err.Error()}
+    }
+    if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+        return &ConfigError{File: fname, Line: 1, Column: 1, Msg: `the configuration is empty`}
+    }
+    n, err := selectProfile(fname,doc.Content[0],profile)
+    if err != nil {
+        return err
+    }
+    var errs ConfigErrors
+    known := yamlKeys(a)
+    keys := map[string]*yaml.Node{}
+    for i := 0; i + 1 < len(n.Content); i += 2 {
+        k := n.Content[i]
+        if k.Value == `<<` {
+            continue
+        }
+        if known[k.Value] == false {
+            errs = append(errs,configError(fname,k,`unknown key %s`,k.Value))
+            continue
         }
-        a._conn = tc
-    } else {
-        l := fmt.Sprintf(\"%s:%s@/%s\",u,p,d)
-        tc, err := sql.Open(\"mysql\",l)
+        keys[k.Value] = n.Content[i+1]
+    }
+    err = n.Decode(a)
+    if err != nil {
+        errs = append(errs,configError(fname,n,`%s`,err))
+        return errs
+    }
+    env := map[string]string{}
+    if envPrefix != `` {
+        env, err = a.fromEnv(envPrefix)
         if err != nil {
-            return a.Oops(fmt.Sprintf(`%s with %s`,err,l))
+            return err
         }
-        a._conn = tc
     }
-    err := a._conn.Ping()
+    for _, p := range a.problems() {
+        if name, ok := env[p.key]; ok {
+            errs = append(errs,&ConfigError{Msg: name + `: ` + p.msg})
+            continue
+        }
+        at, ok := keys[p.key]
+        if ok == false {
+            at = n
+        }
+        errs = append(errs,configError(fname,at,`%s`,p.msg))
+    }
+    if len(errs) > 0 {
+        return errs
+    }
+    if a.LogLevel != `` {
+        var l slog.Level
+        l.UnmarshalText([]byte(a.LogLevel))
+        a.SetLogLevel(l)
+    }
+    return nil
+}
+// Validate checks that the members form a usable configuration
+func (a *MysqlAdapter) Validate() error {
+    var errs ConfigErrors
+    for _, p := range a.problems() {
+        errs = append(errs,&ConfigError{Msg: p.msg})
+    }
+    if len(errs) > 0 {
+        return errs
+    }
+    return nil
+}
+type configProblem struct {
+    key string
+    msg string
+}
+// problems lists what is wrong with the configuration along with the
+// YAML key which is to blame
+func (a *MysqlAdapter) problems() []configProblem {
+    var ps []configProblem
+    add := func(key, format string, args ...interface{}) {
+        ps = append(ps,configProblem{key,fmt.Sprintf(format,args...)})
+    }
+    if a.Socket == `` {
+        if a.Host == `` {
+            add(`host`,`host is required unless socket is set`)
+        } else if validHost(a.Host) == false {
+            add(`host`,`host %q is not a valid host name or address`,a.Host)
+        }
+    }
+    if a.User == `` {
+        add(`user`,`user is required`)
+    }
+    if a.Database == `` {
+        add(`database`,`database is required`)
+    }
+    if a.Port < 0 || a.Port > 65535 {
+        add(`port`,`port %d is out of range`,a.Port)
+    }
+    switch a.TLS {
+    case ``, `true`, `false`, `skip-verify`, `preferred`:
+    default:
+        add(`tls`,`tls must be true, false, skip-verify or preferred, not %q`,a.TLS)
+    }
+    for _, n := range []struct{ key string; v int }{
+        {`batch_size`,a.BatchSize},{`max_open_conns`,a.MaxOpenConns},{`max_idle_conns`,a.MaxIdleConns},{`retries`,a.Retries},
+    } {
+        if n.v < 0 {
+            add(n.key,`%s must not be negative`,n.key)
+        }
+    }
+    if a.LogLevel != `` {
+        var l slog.Level
+        if l.UnmarshalText([]byte(a.LogLevel)) != nil {
+            add(`log_level`,`log_level must be debug, info, warn or error, not %q`,a.LogLevel)
+        }
+    }
+    switch a.LogFormat {
+    case ``, `text`, `json`:
+    default:
+        add(`log_format`,`log_format must be text or json, not %q`,a.LogFormat)
+    }
+    for _, d := range []struct{ key string; v time.Duration }{
+        {`conn_max_lifetime`,a.ConnMaxLifetime},{`retry_backoff`,a.RetryBackoff},{`slow_query`,a.SlowQuery},
+    } {
+        if d.v < 0 {
+            add(d.key,`%s must not be negative`,d.key)
+        }
+    }
+    return ps
+}
+// splitProfile splits file#profile
+func splitProfile(fname string) (string,string) {
+    i := strings.LastIndex(fname,`#`)
+    if i < 0 {
+        return fname, ``
+    }
+    return fname[:i], fname[i+1:]
+}
+// selectProfile returns the mapping of profile in n. A mapping with any
+// key of the configuration is a single configuration, otherwise each
+// key names a profile.
+func selectProfile(fname string, n *yaml.Node, profile string) (*yaml.Node,error) {
+    if n.Kind != yaml.MappingNode {
+        return nil, configError(fname,n,`expected a mapping of configuration keys`)
+    }
+    known := yamlKeys(&MysqlAdapter{})
+    var names []string
+    profiles := map[string]*yaml.Node{}
+    for i := 0; i + 1 < len(n.Content); i += 2 {
+        k, v := n.Content[i], n.Content[i+1]
+        if known[k.Value] {
+            if profile != `` {
+                return nil, configError(fname,n,`there are no profiles to select %s from`,profile)
+            }
+            return n, nil
+        }
+        if v.Kind == yaml.AliasNode {
+            v = v.Alias
+        }
+        if v.Kind == yaml.MappingNode {
+            names = append(names,k.Value)
+            profiles[k.Value] = v
+        }
+    }
+    if profile == `` && len(names) == 1 {
+        profile = names[0]
+    }
+    p, ok := profiles[profile]
+    if ok == false {
+        sort.Strings(names)
+        if profile == `` {
+            return nil, configError(fname,n,`select one of the profiles %s with file#profile`,strings.Join(names,`, `))
+        }
+        return nil, configError(fname,n,`no profile %s, the profiles are %s`,profile,strings.Join(names,`, `))
+    }
+    return p, nil
+}
+// yamlKeys returns the set of yaml keys of the struct v points to
+func yamlKeys(v interface{}) map[string]bool {
+    keys := map[string]bool{}
+    t := reflect.TypeOf(v).Elem()
+    for i := 0; i < t.NumField(); i++ {
+        tag := strings.Split(t.Field(i).Tag.Get(`yaml`),`,`)[0]
+        if tag != `` && tag != `-` {
+            keys[tag] = true
+        }
+    }
+    return keys
+}
+var _hostLabelRegexp = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?\$`)
+// validHost returns true for an IP address or host name, with an
+// optional :port
+func validHost(h string) bool {
+    if host, port, err := net.SplitHostPort(h); err == nil {
+        p, err := strconv.Atoi(port)
+        if err != nil || p < 1 || p > 65535 {
+            return false
+        }
+        h = host
+    }
+    if net.ParseIP(h) != nil {
+        return true
+    }
+    if len(h) == 0 || len(h) > 253 {
+        return false
+    }
+    for _, l := range strings.Split(h,`.`) {
+        if _hostLabelRegexp.MatchString(l) == false {
+            return false
+        }
+    }
+    return true
+}
+// Open Opens the database connection. Be sure to use 
+// a.Close() as closing is NOT handled for you.
+func (a *MysqlAdapter) Open(h,u,p,d string) error {
+    a.Host, a.User, a.Pass, a.Database = h, u, p, d
+    l, err := a.DSN()
+    if err != nil {
+        return a.Oops(fmt.Sprintf(`%s with %s`,err,RedactDSN(l)))
+    }
+    tc, err := sql.Open(\"mysql\",l)
+    if err != nil {
+        return a.Oops(fmt.Sprintf(`%s with %s`,err,RedactDSN(l)))
+    }
+    a._conn = tc
+    a.configurePool()
+    err = a.retry(`Ping`,a._conn.Ping)
     if err != nil {
         return err
     }
@@ -156,6 +607,116 @@ func (a *MysqlAdapter) Open(h,u,p,d string) error {
     return nil
 
 }
+// EnvPrefix starts the names of the environment variables read by
+// NewMysqlAdapterEx, so GOPAPER_DB_PASS overrides pass
+const EnvPrefix = `GOPAPER_DB_`
+// FromEnv overrides the connection settings with those of the
+// environment variables named prefix followed by HOST, PORT, SOCKET,
+// USER, PASS or DATABASE, which keeps secrets out of the YAML file
+func (a *MysqlAdapter) FromEnv(prefix string) error {
+    _, err := a.fromEnv(prefix)
+    return err
+}
+// fromEnv returns the YAML keys it overrode along with the name of
+// their variable
+func (a *MysqlAdapter) fromEnv(prefix string) (map[string]string,error) {
+    set := map[string]string{}
+    for _, s := range []struct{ name string; to *string }{
+        {`HOST`,&a.Host},{`SOCKET`,&a.Socket},{`USER`,&a.User},{`PASS`,&a.Pass},{`DATABASE`,&a.Database},
+    } {
+        if v, ok := os.LookupEnv(prefix + s.name); ok {
+            *s.to = v
+            set[strings.ToLower(s.name)] = prefix + s.name
+        }
+    }
+    if v, ok := os.LookupEnv(prefix + `PORT`); ok {
+        port, err := strconv.Atoi(v)
+        if err != nil {
+            return set, a.Oops(fmt.Sprintf(`%sPORT is not a number: %s`,prefix,v))
+        }
+        a.Port = port
+        set[`port`] = prefix + `PORT`
+    }
+    return set, nil
+}
+// DSN returns the data source name of the connection. A localhost
+// without Port or Socket connects to the default address as it always
+// has. The returned DSN is valid even with an error, for reporting.
+func (a *MysqlAdapter) DSN() (string,error) {
+    cfg := mysqldriver.NewConfig()
+    cfg.User = a.User
+    cfg.Passwd = a.Pass
+    cfg.DBName = a.Database
+    cfg.Collation = a.Collation
+    switch {
+    case a.Socket != ``:
+        cfg.Net = `unix`
+        cfg.Addr = a.Socket
+    case a.Port != 0:
+        cfg.Net = `tcp`
+        cfg.Addr = net.JoinHostPort(a.Host,strconv.Itoa(a.Port))
+    case a.Host != `localhost`:
+        cfg.Net = `tcp`
+        cfg.Addr = a.Host
+    }
+    var params []string
+    if len(a.Params) > 0 {
+        v := url.Values{}
+        for k, p := range a.Params {
+            v.Set(k,p)
+        }
+        params = append(params,v.Encode())
+    }
+    if a.Charset != `` {
+        // the driver does not unescape the charset, which may be a list
+        params = append(params,`charset=` + a.Charset)
+    }
+    tlsName, err := a.tlsConfig()
+    if tlsName != `` {
+        params = append(params,`tls=` + tlsName)
+    }
+    l := cfg.FormatDSN()
+    if len(params) > 0 {
+        if strings.Contains(l,`?`) {
+            l += `&`
+        } else {
+            l += `?`
+        }
+        l += strings.Join(params,`&`)
+    }
+    if err != nil {
+        return l, err
+    }
+    // parsing tells us about unknown or bad parameters now rather
+    // than at the first connection
+    parsed, err := mysqldriver.ParseDSN(l)
+    if err == nil && parsed.ParseTime {
+        err = errors.New(`parseTime is not supported, AsDateTime expects DATETIMEs as strings`)
+    }
+    return l, err
+}
+// tlsConfig returns the value of the tls parameter of the DSN. With
+// TLSCA set it registers a configuration trusting that CA.
+func (a *MysqlAdapter) tlsConfig() (string,error) {
+    if a.TLSCA == `` {
+        return a.TLS, nil
+    }
+    if a.TLS == `false` {
+        return a.TLS, nil
+    }
+    pem, err := ioutil.ReadFile(a.TLSCA)
+    if err != nil {
+        return ``, err
+    }
+    pool := x509.NewCertPool()
+    if pool.AppendCertsFromPEM(pem) == false {
+        return ``, fmt.Errorf(`no certificates in %s`,a.TLSCA)
+    }
+    c := &tls.Config{RootCAs: pool, ServerName: a.Host}
+    name := fmt.Sprintf(`gopaper-%p`,a)
+    err = mysqldriver.RegisterTLSConfig(name,c)
+    return name, err
+}
 // Close This should be called in your application with a defer a.Close() 
 // or something similar. Closing is not automatic!
 func (a *MysqlAdapter) Close() {
@@ -167,13 +728,74 @@ func (a *MysqlAdapter) Query(q string) ([]map[string]DBValue,error) {
     if a._opened != true {
         return nil,a.Oops(`you must first open the connection`)
     }
-    results := new([]map[string]DBValue)
-    a.LogInfo(q)
-    rows, err := a._conn.Query(q)
+    start := time.Now()
+    rows, err := a.queryRetry(`Query`,q)
     if err != nil {
+        a.recordStatement(`query`,q,start,0,err)
         return nil,err
     }
     defer rows.Close()
+    results, err := a.readRows(rows)
+    a.recordStatement(`query`,q,start,int64(len(results)),err)
+    return results,err
+}
+// QueryEach runs q like Query but calls f with each row as it is read
+// instead of collecting them, the buffers of the Row are reused for
+// every row. An error from f stops the iteration and is returned.
+func (a *MysqlAdapter) QueryEach(q string, f func(Row) error) error {
+    if a._opened != true {
+        return a.Oops(`you must first open the connection`)
+    }
+    start := time.Now()
+    rows, err := a.queryRetry(`QueryEach`,q)
+    if err != nil {
+        a.recordStatement(`query`,q,start,0,err)
+        return err
+    }
+    defer rows.Close()
+    var n int64
+    err = a.eachRow(rows,func(r Row) error {
+        n++
+        return f(r)
+    })
+    a.recordStatement(`query`,q,start,n,err)
+    return err
+}
+// queryRetry runs q, retrying transient errors outside of a transaction.
+// In one a deadlock or lock wait timeout has already rolled back the
+// whole transaction, so the error fails the Transaction instead.
+func (a *MysqlAdapter) queryRetry(method, q string) (*sql.Rows,error) {
+    if a._tx != nil {
+        return a.query(q)
+    }
+    var rows *sql.Rows
+    err := a.retry(method,func() (err error) {
+        rows, err = a.query(q)
+        return err
+    })
+    return rows, err
+}
+// query runs q in the transaction of the Adapter, if there is one
+func (a *MysqlAdapter) query(q string) (*sql.Rows,error) {
+    if a._tx != nil {
+        return a._tx.Query(q)
+    }
+    err := a.borrow()
+    if err != nil {
+        return nil,err
+    }
+    return a._conn.Query(q)
+}
+// rowScanner is the part of *sql.Rows that results are read with
+type rowScanner interface {
+    Columns() ([]string,error)
+    Next() bool
+    Scan(...interface{}) error
+    Err() error
+}
+// readRows collects rows as DBValue maps for Query
+func (a *MysqlAdapter) readRows(rows rowScanner) ([]map[string]DBValue,error) {
+    var results []map[string]DBValue
     columns, err := rows.Columns()
     if err != nil {
         return nil, err
@@ -194,46 +816,231 @@ func (a *MysqlAdapter) Query(q string) ([]map[string]DBValue,error) {
             res[k] = a.NewDBValue()
             res[k].SetInternalValue(k,string(col))
         }
-        *results = append(*results,res)
+        results = append(results,res)
+    }
+    return results,rows.Err()
+}
+// mysqlRow is the Row of QueryEach, one is reused for all the rows
+type mysqlRow struct {
+    columns []string
+    index map[string]int
+    raw []sql.RawBytes
+    values []MysqlValue
+}
+// Columns returns the columns in the order of the query
+func (r *mysqlRow) Columns() []string {
+    return r.columns
+}
+// Get returns the value of a column, or nil when there is no such column
+func (r *mysqlRow) Get(column string) DBValue {
+    i, ok := r.index[column]
+    if !ok {
+        return nil
     }
-    return *results,nil
+    r.values[i]._v = string(r.raw[i])
+    return &r.values[i]
+}
+// eachRow calls f with every row for QueryEach
+func (a *MysqlAdapter) eachRow(rows rowScanner, f func(Row) error) error {
+    columns, err := rows.Columns()
+    if err != nil {
+        return err
+    }
+    r := &mysqlRow{
+        columns: columns,
+        index: make(map[string]int,len(columns)),
+        raw: make([]sql.RawBytes,len(columns)),
+        values: make([]MysqlValue,len(columns)),
+    }
+    scanArgs := make([]interface{},len(columns))
+    for i,c := range columns {
+        r.index[c] = i
+        r.values[i] = MysqlValue{_k: c, _adapter: a}
+        scanArgs[i] = &r.raw[i]
+    }
+    for rows.Next() {
+        err = rows.Scan(scanArgs...)
+        if err != nil {
+            return err
+        }
+        err = f(r)
+        if err != nil {
+            return err
+        }
+    }
+    return rows.Err()
 }
 // Oops A function for catching errors generated by
 // the library and funneling them to the log files
 func (a *MysqlAdapter) Oops(s string) error {
-    e := errors.New(s)
+    e := errors.New(a.Redact(s))
     a.LogError(e)
     return e
 }
 // Execute For UPDATE and INSERT calls, i.e. nothing that
-// returns a result set.
-func (a *MysqlAdapter) Execute(q string) error {
+// returns a result set. The ExecResult holds the id of an INSERT and
+// the number of rows changed.
+func (a *MysqlAdapter) Execute(q string) (ExecResult,error) {
     if a._opened != true {
-        return a.Oops(`you must first open the connection`)
+        return ExecResult{},a.Oops(`you must first open the connection`)
+    }
+    if a._tx != nil {
+        r, _, err := a.execute(a._tx,q)
+        return r,err
+    }
+    var res ExecResult
+    err := a.retry(`Execute`,func() error {
+        tx, err := a.begin()
+        if err != nil {
+            return err
+        }
+        defer tx.Rollback();
+        var sent bool
+        res, sent, err = a.execute(tx,q)
+        if err != nil {
+            if sent && isRolledBack(err) == false {
+                // the server may have applied it
+                return final(err)
+            }
+            return err
+        }
+        err = tx.Commit()
+        if err != nil {
+            // the commit may have gone through, running the statement
+            // again could apply it twice
+            return final(a.oopsErr(`could not Commit Transaction`,err))
+        }
+        return nil
+    })
+    return res,err
+}
+// begin starts a transaction, pinging first when PingOnBorrow is set
+func (a *MysqlAdapter) begin() (*sql.Tx,error) {
+    err := a.borrow()
+    if err != nil {
+        return nil,err
     }
     tx, err := a._conn.Begin()
     if err != nil {
-        return a.Oops(fmt.Sprintf(`could not Begin Transaction %s`,err))
+        return nil,a.oopsErr(`could not Begin Transaction`,err)
     }
-    defer tx.Rollback();
+    return tx,nil
+}
+// execute runs q in the transaction tx, sent is true once the statement
+// went to the server
+func (a *MysqlAdapter) execute(tx *sql.Tx, q string) (r ExecResult, sent bool, err error) {
+    start := time.Now()
     stmt, err := tx.Prepare(q)
     if err != nil {
-        return a.Oops(fmt.Sprintf(`could not Prepare Statement %s`,err))
+        a.recordStatement(`execute`,q,start,0,err)
+        return r,false,a.oopsErr(`could not Prepare Statement`,err)
     }
     defer stmt.Close()
-    a.LogInfo(q)
     res,err := stmt.Exec()
     if err != nil {
-        return a.Oops(fmt.Sprintf(`could not Exec stmt %s`,err))
+        a.recordStatement(`execute`,q,start,0,err)
+        return r,true,a.oopsErr(`could not Exec stmt`,err)
+    }
+    r.LastInsertId,err = res.LastInsertId()
+    if err != nil {
+        return r,true,a.Oops(fmt.Sprintf(`could not get LastInsertId %s`,err))
+    }
+    r.RowsAffected,err = res.RowsAffected()
+    if err != nil {
+        return r,true,a.Oops(fmt.Sprintf(`could not get RowsAffected %s`,err))
+    }
+    a._last.set(r)
+    a.recordStatement(`execute`,q,start,r.RowsAffected,nil,slog.Int64(`id`,r.LastInsertId))
+    return r,true,nil
+}
+// lastResult keeps the ExecResult of the latest Execute of any caller
+// for LastInsertedId and AffectedRows, the copies made by Transaction
+// share it
+type lastResult struct {
+    mu sync.Mutex
+    r ExecResult
+}
+func (l *lastResult) set(r ExecResult) {
+    if l == nil {
+        return
+    }
+    l.mu.Lock()
+    l.r = r
+    l.mu.Unlock()
+}
+func (l *lastResult) get() ExecResult {
+    if l == nil {
+        return ExecResult{}
+    }
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    return l.r
+}
+var _statementTableRegexp = regexp.MustCompile(\"(?i)\\\\b(?:FROM|INTO|UPDATE)\\\\s+`?(\\\\w+)\")
+// statementTable returns the first table named by q
+func statementTable(q string) string {
+    m := _statementTableRegexp.FindStringSubmatch(q)
+    if m == nil {
+        return ``
+    }
+    return m[1]
+}
+// recordStatement counts q in the Stats and logs it with its table,
+// duration and the number of rows it read or changed, at ERROR when it
+// failed and at WARN when it was slow
+func (a *MysqlAdapter) recordStatement(msg, q string, start time.Time, rows int64, err error, attrs ...slog.Attr) {
+    d := time.Since(start)
+    slow := a.SlowQuery > 0 && d >= a.SlowQuery
+    a._stats.record(q,d,rows,err != nil,slow)
+    level := slog.LevelInfo
+    switch {
+    case err != nil:
+        level = slog.LevelError
+    case slow:
+        level = slog.LevelWarn
+        msg = `slow ` + msg
+        if a._slowLog != nil {
+            a._slowLog.Printf(`%s rows=%d %s`,d,rows,a.Redact(a.maskQuery(q)))
+        }
+    }
+    if a._logger == nil || (a._level != nil && level < a._level.Level()) {
+        return
+    }
+    attrs = append([]slog.Attr{
+        slog.String(`table`,statementTable(q)),
+        slog.String(`query`,a.maskQuery(q)),
+        slog.Duration(`duration`,d),
+        slog.Int64(`rows`,rows),
+    },attrs...)
+    if err != nil {
+        attrs = append(attrs,slog.String(`error`,a.maskError(err)))
+    }
+    a.Log(level,msg,attrs...)
+}
+// Transaction runs f with a copy of the Adapter bound to a new
+// transaction, which is committed when f returns nil and rolled
+// back otherwise. Inside a transaction f joins the outer one.
+func (a *MysqlAdapter) Transaction(f func(Adapter) error) error {
+    if a._tx != nil {
+        return f(a)
+    }
+    if a._opened != true {
+        return a.Oops(`you must first open the connection`)
     }
-    a._lid,err = res.LastInsertId()
-    a.LogInfo(fmt.Sprintf(`LastInsertedId is %d`,a._lid))
+    var tx *sql.Tx
+    err := a.retry(`Begin`,func() (err error) {
+        tx, err = a.begin()
+        return err
+    })
     if err != nil {
-        return a.Oops(fmt.Sprintf(`could not get LastInsertId %s`,err))
+        return err
     }
-    a._cnt,err = res.RowsAffected()
+    defer tx.Rollback()
+    ta := *a
+    ta._tx = tx
+    err = f(&ta)
     if err != nil {
-        return a.Oops(fmt.Sprintf(`could not get RowsAffected %s`,err))
+        return err
     }
     err = tx.Commit()
     if err != nil {
@@ -241,12 +1048,150 @@ func (a *MysqlAdapter) Execute(q string) error {
     }
     return nil
 }
-// LastInsertedId Grab the last auto_incremented id
+// LastInsertedId Grab the last auto_incremented id of any caller of
+// Execute. Only kept for compatibility, when the Adapter is shared by
+// goroutines it may be the id of another's INSERT, use the ExecResult.
 func (a *MysqlAdapter) LastInsertedId() int64 {
-    return a._lid
+    return a._last.get().LastInsertId
 }
-// AffectedRows Grab the number of AffectedRows
+// AffectedRows Grab the number of AffectedRows of the last statement of
+// any caller of Execute, only kept for compatibility like LastInsertedId
 func (a *MysqlAdapter) AffectedRows() int64 {
-    return a._cnt
+    return a._last.get().RowsAffected
+}
+// Stats returns a snapshot of the statistics of every statement shape
+// run so far, the shapes taking the most time in total first
+func (a *MysqlAdapter) Stats() []StatementStats {
+    return a._stats.snapshot()
+}
+// ResetStats forgets the statistics gathered so far
+func (a *MysqlAdapter) ResetStats() {
+    a._stats.reset()
+}
+// queryStats gathers the StatementStats of an Adapter, the copies made
+// by Transaction share it
+type queryStats struct {
+    mu sync.Mutex
+    shapes map[string]*StatementStats
+}
+func newQueryStats() *queryStats {
+    return &queryStats{shapes: map[string]*StatementStats{}}
+}
+func (qs *queryStats) record(q string, d time.Duration, rows int64, failed, slow bool) {
+    if qs == nil {
+        return
+    }
+    shape := NormalizeSQL(q)
+    qs.mu.Lock()
+    defer qs.mu.Unlock()
+    s, ok := qs.shapes[shape]
+    if ok == false {
+        s = &StatementStats{Shape: shape}
+        qs.shapes[shape] = s
+    }
+    s.Count++
+    s.Rows += rows
+    s.Total += d
+    if d > s.Max {
+        s.Max = d
+    }
+    if failed {
+        s.Errors++
+    }
+    if slow {
+        s.Slow++
+    }
+}
+func (qs *queryStats) snapshot() []StatementStats {
+    if qs == nil {
+        return nil
+    }
+    qs.mu.Lock()
+    defer qs.mu.Unlock()
+    ss := make([]StatementStats,0,len(qs.shapes))
+    for _, s := range qs.shapes {
+        ss = append(ss,*s)
+    }
+    sort.Slice(ss,func(i, j int) bool {
+        if ss[i].Total != ss[j].Total {
+            return ss[i].Total > ss[j].Total
+        }
+        return ss[i].Shape < ss[j].Shape
+    })
+    return ss
+}
+func (qs *queryStats) reset() {
+    if qs == nil {
+        return
+    }
+    qs.mu.Lock()
+    defer qs.mu.Unlock()
+    qs.shapes = map[string]*StatementStats{}
+}
+// Health pings the database and reports how long it took along with
+// the statistics of the connection pool
+func (a *MysqlAdapter) Health() Health {
+    var h Health
+    if a._opened != true {
+        h.Err = a.Oops(`you must first open the connection`)
+        return h
+    }
+    start := time.Now()
+    h.Err = a._conn.Ping()
+    h.Latency = time.Since(start)
+    h.Stats = a._conn.Stats()
+    return h
+}
+// configurePool applies the pool settings to the connection
+func (a *MysqlAdapter) configurePool() {
+    if a.MaxOpenConns > 0 {
+        a._conn.SetMaxOpenConns(a.MaxOpenConns)
+    }
+    if a.MaxIdleConns > 0 {
+        a._conn.SetMaxIdleConns(a.MaxIdleConns)
+    }
+    if a.ConnMaxLifetime > 0 {
+        a._conn.SetConnMaxLifetime(a.ConnMaxLifetime)
+    }
+}
+// borrow pings the database when PingOnBorrow is set
+func (a *MysqlAdapter) borrow() error {
+    if a.PingOnBorrow != true {
+        return nil
+    }
+    return a._conn.Ping()
+}
+// retry runs f until it succeeds, fails with an error which is not
+// transient or has been retried Retries times. It waits RetryBackoff
+// before the first retry and twice as long before each next one. An
+// error f wraps with final is returned as it is, without retrying.
+func (a *MysqlAdapter) retry(what string, f func() error) error {
+    backoff := a.RetryBackoff
+    for attempt := 0; ; attempt++ {
+        err := f()
+        var fe *finalError
+        if errors.As(err,&fe) {
+            return fe.err
+        }
+        if err == nil || attempt >= a.Retries || isTransient(err) == false {
+            return err
+        }
+        a.Log(slog.LevelWarn,`retrying`,slog.String(`what`,what),slog.String(`error`,err.Error()),slog.Duration(`backoff`,backoff))
+        time.Sleep(backoff)
+        backoff *= 2
+    }
+}
+// oopsErr is Oops for errors from the driver, the error returned wraps
+// err so that isTransient can still see what it was
+func (a *MysqlAdapter) oopsErr(s string, err error) error {
+    e := fmt.Errorf(`%s %w`,s,err)
+    a.Log(slog.LevelError,s + ` ` + a.maskError(err))
+    return e
+}
+// Upsert writes values to the columns of table, updating the rows which
+// match a stored row on the unique key keys, BatchSize rows at a time.
+// It returns the values under pkey of the rows, see upsertRows.
+func (a *MysqlAdapter) Upsert(table, pkey string, keys, columns []string, values [][]string) ([]int64,error) {
+    return upsertRows(a,a.BatchSize,table,pkey,keys,columns,values)
 }
 ");
\ No newline at end of file
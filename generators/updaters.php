@@ -5,6 +5,14 @@ function gen_updaters($t) {
     $pkmname = maybeLC(convertFieldName($t->pfield->Field));
     $pkfname = $t->pfield->Field;
     $pkfmttype = mysqlToFmtType($t->pfield->go_type);
+    // with a lock_version column the updates are optimistically locked
+    // like Update
+    $lock = false;
+    foreach ($t->fields as $f) {
+        if ($f->Field == "lock_version") {
+            $lock = true;
+        }
+    }
     foreach ($t->fields as $f) {
         if ( isPrimaryKey($f) ) {
             continue;
@@ -13,24 +21,57 @@ function gen_updaters($t) {
         $mname = maybeLC($fname);
         $arg = "_upd" . maybeLC(convertFieldName($f->Field));
         $argtype = $f->go_type;
-        $fmt_type = mysqlToFmtType($f->Type);
-        
+        $fmt_type = "'" . mysqlToFmtType($f->Type) . "'";
+        $the_arg = $arg;
+        if ($argtype == "*DateTime") {
+            $fmt_type = mysqlToFmtType($f->Type);
+            $the_arg = "dateTimeSQL($arg)";
+        }
         
-        $update_line = "\"UPDATE %s SET `{$f->Field}` = '$fmt_type' WHERE `$pkfname` = '$pkfmttype'\",o._table,$arg,o.{$pkmname}";
+        $update_line = "\"UPDATE %s SET `{$f->Field}` = $fmt_type WHERE `$pkfname` = '$pkfmttype'\",o._table,$the_arg,o.{$pkmname}";
         if ( $t->model_name == "TermRelationship") {
-           $update_line = "\"UPDATE %s SET `{$f->Field}` = '$fmt_type' WHERE term_taxonomy_id = '%d' AND object_id = '%d'\",o._table,$arg,o.TermTaxonomyId,o.ObjectId"; 
+           $update_line = "\"UPDATE %s SET `{$f->Field}` = $fmt_type WHERE term_taxonomy_id = '%d' AND object_id = '%d'\",o._table,$the_arg,o.TermTaxonomyId,o.ObjectId"; 
+        }
+        $lock_check = "";
+        $lock_bump = "";
+        $orig_bump = "";
+        if ($lock && $f->Field != "lock_version") {
+            $update_line = "\"UPDATE %s SET `{$f->Field}` = $fmt_type, lock_version = lock_version + 1 WHERE `$pkfname` = '$pkfmttype' AND lock_version = '%d'\",o._table,$the_arg,o.{$pkmname},o.LockVersion";
+            $lock_check = "\n        if affected == 0 {\n            return ErrStaleObject\n        }";
+            $lock_bump = "\n    o.LockVersion++";
+            $orig_bump = "\n        o._orig.LockVersion = o.LockVersion";
         }
 $txt .= "
 // Update{$fname} an immediate DB Query to update a single column, in this
-// case {$f->Field}
+// case {$f->Field}. The AfterSave callbacks see the column dirty with its
+// new value, as after a Save.
 func (o *{$t->model_name}) Update{$fname}($arg $argtype) (int64,error) {
-    frmt := fmt.Sprintf($update_line)
-    err := o._adapter.Execute(frmt)
+    var affected int64
+    prev, prevDirty := o.{$mname}, o.{$f->dirty_marker}
+    err := o.transaction(func() error {
+        err := recordAudit(o._adapter,o._table,o._pkey,o.{$pkmname},AuditUpdate,map[string]string{`{$f->Field}`: auditValue($arg)})
+        if err != nil {
+            return err
+        }
+        frmt := fmt.Sprintf($update_line)
+        res, err := o._adapter.Execute(frmt)
+        if err != nil {
+            return err
+        }
+        affected = res.RowsAffected$lock_check
+        o.{$mname} = $arg
+        o.{$f->dirty_marker} = true
+        return runCallbacks(o._adapter,`{$t->model_name}`,AfterSave,o)
+    })
     if err != nil {
+        o.{$mname}, o.{$f->dirty_marker} = prev, prevDirty
         return 0,err
     }
-    o.{$mname} = $arg
-    return o._adapter.AffectedRows(),nil
+    o.{$f->dirty_marker} = false$lock_bump
+    if o._orig != nil {
+        o._orig.{$mname} = $arg$orig_bump
+    }
+    return affected,nil
 }
 ";
     } 
@@ -0,0 +1,48 @@
+<?php
+if ( ! function_exists('_validate') ) {
+function _validate($t) {
+    $checks = "";
+    foreach ( $t->fields as $tf) {
+        if (isPrimaryKey($tf)) {
+            continue;
+        }
+        $gfn = convertFieldName($tf->Field);
+        // NOT NULL can only be checked where the zero value means
+        // missing, ids of 0 never refer to a row
+        if ($tf->Null == "NO") {
+            if ($tf->go_type == "string") {
+                $checks .= "
+    if o.$gfn == \"\" {
+        errs.Add(`{$tf->Field}`,`is required`)
+    }";
+            } else if ($tf->go_type == "*DateTime") {
+                $checks .= "
+    if o.$gfn.IsZero() {
+        errs.Add(`{$tf->Field}`,`is required`)
+    }";
+            } else if (preg_match("/_id$/",$tf->Field)) {
+                $checks .= "
+    if o.$gfn == 0 {
+        errs.Add(`{$tf->Field}`,`is required`)
+    }";
+            }
+        }
+        if (preg_match("/varchar\((\d+)\)/",$tf->Type,$m)) {
+            $checks .= "
+    if len(o.$gfn) > {$m[1]} {
+        errs.Add(`{$tf->Field}`,`is longer than {$m[1]}`)
+    }";
+        }
+    }
+$txt = "// Validate checks the model against the constraints of the
+// {$t->dname} table and then runs the validators registered for
+// {$t->model_name}. Create and Save call it before writing.
+func (o *{$t->model_name}) Validate() error {
+    errs := make(ValidationErrors)$checks
+    return runValidators(o._adapter,`{$t->model_name}`,o,errs)
+}
+";
+    return $txt;
+}
+}
+puts(_validate($t));
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ImportReport summarizes what happened to the bars of an import
+type ImportReport struct {
+	PositionId int64
+	Inserted   int
+	Updated    int
+	// Skipped counts bars which were already stored unchanged
+	Skipped int
+	// Invalid lists the rows which could not be parsed
+	Invalid []RowError
+	// Rejected lists the inconsistent bars which were not stored
+	Rejected []BarIssue
+	// Flagged lists the inconsistent bars which were stored anyway
+	Flagged []BarIssue
+}
+
+// String renders the report on one line
+func (r *ImportReport) String() string {
+	return fmt.Sprintf(`position %d: %d inserted, %d updated, %d skipped, %d invalid, %d rejected, %d flagged`,
+		r.PositionId, r.Inserted, r.Updated, r.Skipped, len(r.Invalid), len(r.Rejected), len(r.Flagged))
+}
+
+// Print writes the report followed by the bars which were rejected or flagged
+func (r *ImportReport) Print(w io.Writer) {
+	fmt.Fprintln(w, r)
+	for _, i := range r.Rejected {
+		fmt.Fprintln(w, `  rejected`, i)
+	}
+	for _, i := range r.Flagged {
+		fmt.Fprintln(w, `  flagged`, i)
+	}
+}
+
+// playMatchesBar returns true when the Play already holds the bar
+func playMatchesBar(p *Play, b Bar, source string) bool {
+	return p.Open == toPrice(b.Open) &&
+		p.High == toPrice(b.High) &&
+		p.Low == toPrice(b.Low) &&
+		p.AdjClose == toPrice(b.ClosePrice()) &&
+		p.Pvolume == int(b.Volume) &&
+		p.DataSource == source
+}
+
+// setPlayFromBar copies the bar into the Play through the setters so
+// that only the changed columns are marked dirty
+func setPlayFromBar(p *Play, b Bar, source string) {
+	if p.Open != toPrice(b.Open) {
+		p.SetOpen(toPrice(b.Open))
+	}
+	if p.High != toPrice(b.High) {
+		p.SetHigh(toPrice(b.High))
+	}
+	if p.Low != toPrice(b.Low) {
+		p.SetLow(toPrice(b.Low))
+	}
+	if p.AdjClose != toPrice(b.ClosePrice()) {
+		p.SetAdjClose(toPrice(b.ClosePrice()))
+	}
+	if p.Pvolume != int(b.Volume) {
+		p.SetPvolume(int(b.Volume))
+	}
+	if p.DataSource != source {
+		p.SetDataSource(source)
+	}
+}
+
+// UpsertBars stores the bars as the Plays of a Position. A bar for a day
+// the position already has a Play for updates that Play, so importing the
+// same file twice is harmless. source is recorded as the DataSource.
+func UpsertBars(a Adapter, positionId int64, bars []Bar, source string, policy BarPolicy) (*ImportReport, error) {
+	return storeBars(a, positionId, bars, source, policy, true)
+}
+
+// InsertMissingBars is like UpsertBars but only stores the bars for days
+// the position has no Play for yet, existing Plays count as skipped
+func InsertMissingBars(a Adapter, positionId int64, bars []Bar, source string, policy BarPolicy) (*ImportReport, error) {
+	return storeBars(a, positionId, bars, source, policy, false)
+}
+
+// priceChange computes Pchange and PchangePercent from the previous
+// close, the percentage is in hundredths of a percent so 1.25% is 125
+func priceChange(prevClose, c int) (int, int) {
+	if prevClose == 0 {
+		return 0, 0
+	}
+	change := c - prevClose
+	return change, int(math.Round(float64(change) / float64(prevClose) * 100 * PriceScale))
+}
+
+// storeBars implements UpsertBars and InsertMissingBars. Once the bars
+// are merged with the stored Plays the change fields of every Play are
+// computed again, as a bar inserted into a gap changes the next Play too.
+func storeBars(a Adapter, positionId int64, bars []Bar, source string, policy BarPolicy, overwrite bool) (*ImportReport, error) {
+	report := &ImportReport{PositionId: positionId}
+	existing, err := playsForPosition(a, positionId)
+	if err != nil {
+		return report, err
+	}
+	byDay := make(map[time.Time]*Play)
+	for _, p := range existing {
+		if !p.Day.IsZero() {
+			byDay[truncateDay(p.Day.ToTime())] = p
+		}
+	}
+	created := make(map[*Play]bool)
+	changed := make(map[*Play]bool)
+	for _, b := range bars {
+		day := truncateDay(b.Day)
+		err = ValidateBar(b)
+		if err != nil {
+			issue := BarIssue{Day: day, Err: err.Error()}
+			if policy == RejectInconsistentBars {
+				report.Rejected = append(report.Rejected, issue)
+				continue
+			}
+			report.Flagged = append(report.Flagged, issue)
+		}
+		if p, ok := byDay[day]; ok {
+			if !overwrite || playMatchesBar(p, b, source) {
+				report.Skipped++
+				continue
+			}
+			setPlayFromBar(p, b, source)
+			changed[p] = true
+			continue
+		}
+		p := NewPlay(a)
+		p.PositionId = positionId
+		p.Day = NewDateTime(a)
+		p.Day.FromTime(day)
+		setPlayFromBar(p, b, source)
+		byDay[day] = p
+		created[p] = true
+	}
+	days := make([]time.Time, 0, len(byDay))
+	for day := range byDay {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	var prevClose int
+	var writes []*Play
+	for _, day := range days {
+		p := byDay[day]
+		change, percent := priceChange(prevClose, p.AdjClose)
+		prevClose = p.AdjClose
+		if p.Pchange != change {
+			p.SetPchange(change)
+			changed[p] = true
+		}
+		if p.PchangePercent != percent {
+			p.SetPchangePercent(percent)
+			changed[p] = true
+		}
+		if created[p] || changed[p] {
+			writes = append(writes, p)
+		}
+	}
+	err = InsertPlays(a, writes)
+	if err != nil {
+		return report, err
+	}
+	for _, p := range writes {
+		if created[p] {
+			report.Inserted++
+		} else {
+			report.Updated++
+		}
+	}
+	return report, nil
+}
+
+// playColumns are the columns InsertPlays writes
+var playColumns = []string{`position_id`, `day`, `open`, `high`, `low`, `pvolume`,
+	`pchange`, `pchange_percent`, `adj_close`, `data_source`}
+
+// InsertPlays stores the plays with batched upserts, see Adapter.Upsert.
+// A Play for a day its Position already has a Play for replaces the
+// stored one, thanks to the unique key on position_id and day, so
+// importing the same bars twice is harmless. Every play is validated and
+// gets the id of its row, unlike Create no callbacks run. A play which
+// was loaded, as storeBars loads them, is audited as an update in the
+// transaction of the upsert, new plays are not audited.
+func InsertPlays(a Adapter, plays []*Play) error {
+	if len(plays) == 0 {
+		return nil
+	}
+	values := make([][]string, len(plays))
+	for i, p := range plays {
+		if p.Day.IsZero() {
+			return a.Oops(fmt.Sprintf(`the play of position %d needs a day to be stored`, p.PositionId))
+		}
+		err := p.Validate()
+		if err != nil {
+			return err
+		}
+		values[i] = []string{
+			fmt.Sprintf(`'%d'`, p.PositionId),
+			dateTimeSQL(p.Day),
+			fmt.Sprintf(`'%d'`, p.Open),
+			fmt.Sprintf(`'%d'`, p.High),
+			fmt.Sprintf(`'%d'`, p.Low),
+			fmt.Sprintf(`'%d'`, p.Pvolume),
+			fmt.Sprintf(`'%d'`, p.Pchange),
+			fmt.Sprintf(`'%d'`, p.PchangePercent),
+			fmt.Sprintf(`'%d'`, p.AdjClose),
+			fmt.Sprintf(`'%s'`, a.SafeString(p.DataSource)),
+		}
+	}
+	var ids []int64
+	err := a.Transaction(func(ta Adapter) error {
+		for _, p := range plays {
+			if p._new || p.Id == 0 {
+				continue
+			}
+			err := recordAudit(ta, p._table, p._pkey, p.Id, AuditUpdate, playAuditValues(p))
+			if err != nil {
+				return err
+			}
+		}
+		var err error
+		ids, err = ta.Upsert(plays[0]._table, plays[0]._pkey, []string{`position_id`, `day`}, playColumns, values)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for i, p := range plays {
+		p.Id = ids[i]
+		p._new = false
+		p.ResetDirty()
+	}
+	return nil
+}
+
+// playAuditValues maps the playColumns to the values of p for the audit log
+func playAuditValues(p *Play) map[string]string {
+	changes := make(map[string]string)
+	for i, v := range []interface{}{p.PositionId, p.Day, p.Open, p.High, p.Low, p.Pvolume,
+		p.Pchange, p.PchangePercent, p.AdjClose, p.DataSource} {
+		changes[playColumns[i]] = auditValue(v)
+	}
+	return changes
+}
+
+// importPositions resolves the -position or -symbol flag of an import
+func importPositions(a Adapter, positionId int64, symbol string) ([]*Position, error) {
+	if positionId != 0 {
+		p := NewPosition(a)
+		_, err := p.Find(positionId)
+		if err != nil {
+			return nil, a.Oops(fmt.Sprintf(`no position with id %d`, positionId))
+		}
+		return []*Position{p}, nil
+	}
+	if symbol != `` {
+		positions, err := NewPosition(a).FindBySymbol(symbol)
+		if err != nil {
+			return nil, a.Oops(fmt.Sprintf(`no positions for symbol %s`, symbol))
+		}
+		return positions, nil
+	}
+	return nil, oops(`one of -position or -symbol is required`)
+}
+
+// importCommand implements `gopaper import bars [flags] file.csv`
+func importCommand(a Adapter, args []string, w io.Writer) error {
+	usage := `usage: gopaper import bars [-position id | -symbol sym] [-source name] [-columns field=Header,...] [-flag-inconsistent] file.csv`
+	if len(args) == 0 || args[0] != `bars` {
+		return oops(usage)
+	}
+	fs := flag.NewFlagSet(`import bars`, flag.ContinueOnError)
+	fs.SetOutput(w)
+	positionId := fs.Int64(`position`, 0, `the id of the position the bars belong to`)
+	symbol := fs.String(`symbol`, ``, `import the bars for every position with this symbol`)
+	source := fs.String(`source`, ``, `the DataSource recorded on each bar, defaults to csv:<file name>`)
+	columns := fs.String(`columns`, ``, `map bar fields to CSV headers, e.g. date=Day,close=Last`)
+	flagBars := fs.Bool(`flag-inconsistent`, false, `store inconsistent bars and report them instead of rejecting them`)
+	err := fs.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return oops(usage)
+	}
+	fname := fs.Arg(0)
+	cols, err := ParseBarColumns(BarColumns{}, *columns)
+	if err != nil {
+		return err
+	}
+	if *source == `` {
+		*source = `csv:` + filepath.Base(fname)
+	}
+	positions, err := importPositions(a, *positionId, *symbol)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	bars, invalid, err := ParseBarsCSV(f, cols)
+	if err != nil {
+		return err
+	}
+	for _, p := range positions {
+		report, err := UpsertBars(a, p.Id, bars, *source, barPolicyFromFlag(*flagBars))
+		if err != nil {
+			return err
+		}
+		report.Invalid = invalid
+		report.Print(w)
+	}
+	for _, e := range invalid {
+		fmt.Fprintln(w, `  invalid`, e)
+	}
+	return nil
+}
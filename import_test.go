@@ -0,0 +1,109 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPriceChange(t *testing.T) {
+	change, percent := priceChange(1000, 1025)
+	if change != 25 || percent != 250 {
+		t.Errorf(`expected 25 and 250 got %d %d`, change, percent)
+	}
+	change, percent = priceChange(1000, 990)
+	if change != -10 || percent != -100 {
+		t.Errorf(`expected -10 and -100 got %d %d`, change, percent)
+	}
+	change, percent = priceChange(0, 990)
+	if change != 0 || percent != 0 {
+		t.Errorf(`the first bar should have no change got %d %d`, change, percent)
+	}
+}
+
+func TestSetPlayFromBar(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	p := NewPlay(a)
+	b := Bar{Open: 10, High: 12, Low: 9, Close: 11, AdjClose: 10.5, Volume: 100}
+	setPlayFromBar(p, b, `test`)
+	if p.Open != 1000 || p.High != 1200 || p.Low != 900 || p.AdjClose != 1050 || p.Pvolume != 100 {
+		t.Errorf(`wrong play %+v`, p)
+	}
+	if !p.IsOpenDirty || !p.IsDataSourceDirty {
+		t.Errorf(`setters were not used %+v`, p)
+	}
+	if !playMatchesBar(p, b, `test`) || playMatchesBar(p, b, `other`) {
+		t.Errorf(`playMatchesBar disagrees with setPlayFromBar`)
+	}
+}
+
+func TestInsertPlays(t *testing.T) {
+	a := newFakeAdapter()
+	a.BatchSize = 2
+	// the stored rows, the second day was already there as id 7
+	a.rows = func(q string) []map[string]string {
+		var rows []map[string]string
+		for day, id := range map[string]string{`2016-01-04 00:00:00`: `10`, `2016-01-05 00:00:00`: `7`, `2016-01-06 00:00:00`: `11`} {
+			if strings.Contains(q, `'`+day+`'`) {
+				rows = append(rows, map[string]string{`id`: id, `position_id`: `3`, `day`: day})
+			}
+		}
+		return rows
+	}
+	var plays []*Play
+	for day := 4; day <= 6; day++ {
+		p := NewPlay(a)
+		p.PositionId = 3
+		p.Day = testDateTime(a, 2016, 1, day)
+		p.AdjClose = 100 + day
+		plays = append(plays, p)
+	}
+	err := InsertPlays(a, plays)
+	if err != nil {
+		t.Errorf(`InsertPlays failed %s`, err)
+		return
+	}
+	if a.count(`INSERT INTO plays`) != 2 || a.count(`INSERT INTO audit_log`) != 0 {
+		t.Errorf(`expected two batches and no audit got %v`, a.executed)
+	}
+	if !strings.HasSuffix(a.executed[0], "ON DUPLICATE KEY UPDATE `position_id` = VALUES(`position_id`), `day` = VALUES(`day`), `open` = VALUES(`open`), `high` = VALUES(`high`), `low` = VALUES(`low`), `pvolume` = VALUES(`pvolume`), `pchange` = VALUES(`pchange`), `pchange_percent` = VALUES(`pchange_percent`), `adj_close` = VALUES(`adj_close`), `data_source` = VALUES(`data_source`)") {
+		t.Errorf(`the insert should update duplicates got %s`, a.executed[0])
+	}
+	if plays[0].Id != 10 || plays[1].Id != 7 || plays[2].Id != 11 {
+		t.Errorf(`wrong ids %d %d %d`, plays[0].Id, plays[1].Id, plays[2].Id)
+	}
+	if plays[0].IsDirty() {
+		t.Errorf(`stored plays should not be dirty`)
+	}
+	a.rows = nil
+	err = InsertPlays(a, plays[:1])
+	if err == nil {
+		t.Errorf(`a row which cannot be read back should fail`)
+	}
+}
+
+func TestInsertPlaysAudit(t *testing.T) {
+	a := newFakeAdapter()
+	a.rows = func(q string) []map[string]string {
+		return []map[string]string{{`id`: `7`, `position_id`: `3`, `day`: `2016-01-05 00:00:00`, `open`: `100`, `high`: `100`,
+			`low`: `100`, `pvolume`: `10`, `pchange`: `0`, `pchange_percent`: `0`, `adj_close`: `100`, `data_source`: `test`}}
+	}
+	p := NewPlay(a)
+	_, err := p.Find(7)
+	if err != nil {
+		t.Errorf(`could not find the play %v`, err)
+		return
+	}
+	p.SetAdjClose(105)
+	n := NewPlay(a)
+	n.PositionId = 3
+	n.Day = testDateTime(a, 2016, 1, 5)
+	err = InsertPlays(a, []*Play{p, n})
+	if err != nil {
+		t.Errorf(`InsertPlays failed %s`, err)
+		return
+	}
+	if a.count(`INSERT INTO audit_log`) != 1 || !strings.HasPrefix(a.executed[0], `INSERT INTO audit_log`) ||
+		!strings.Contains(a.executed[0], `{"column":"adj_close","old":"100","new":"105"}`) {
+		t.Errorf(`the updated play should be audited before it is written got %v`, a.executed)
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"math"
+)
+
+// Series is a sequence of values, one per Play, in the order of the
+// plays it came from. Prices are in the units stored in Play, see
+// PriceScale. Indicators return a Series as long as their input with
+// NaN for the days before there is enough data, so that index i of
+// every series refers to the same day.
+type Series []float64
+
+// Last returns the last value of the series, NaN when it is empty
+func (s Series) Last() float64 {
+	if len(s) == 0 {
+		return math.NaN()
+	}
+	return s[len(s)-1]
+}
+
+// At returns the value n days before the last, At(0) is Last()
+func (s Series) At(n int) float64 {
+	if n < 0 || n >= len(s) {
+		return math.NaN()
+	}
+	return s[len(s)-1-n]
+}
+
+// nanSeries returns a series of n NaNs
+func nanSeries(n int) Series {
+	s := make(Series, n)
+	for i := range s {
+		s[i] = math.NaN()
+	}
+	return s
+}
+
+// playSeries builds a series from one field of each play
+func playSeries(plays []*Play, field func(p *Play) int) Series {
+	s := make(Series, len(plays))
+	for i, p := range plays {
+		s[i] = float64(field(p))
+	}
+	return s
+}
+
+// Opens returns the open of every play
+func Opens(plays []*Play) Series {
+	return playSeries(plays, func(p *Play) int { return p.Open })
+}
+
+// Highs returns the high of every play
+func Highs(plays []*Play) Series {
+	return playSeries(plays, func(p *Play) int { return p.High })
+}
+
+// Lows returns the low of every play
+func Lows(plays []*Play) Series {
+	return playSeries(plays, func(p *Play) int { return p.Low })
+}
+
+// Closes returns the adjusted close of every play
+func Closes(plays []*Play) Series {
+	return playSeries(plays, func(p *Play) int { return p.AdjClose })
+}
+
+// Volumes returns the volume of every play
+func Volumes(plays []*Play) Series {
+	return playSeries(plays, func(p *Play) int { return p.Pvolume })
+}
+
+// SMA is the simple moving average over n days
+func SMA(s Series, n int) Series {
+	out := nanSeries(len(s))
+	if n <= 0 {
+		return out
+	}
+	var sum float64
+	for i, v := range s {
+		sum += v
+		if i >= n {
+			sum -= s[i-n]
+		}
+		if i >= n-1 {
+			out[i] = sum / float64(n)
+		}
+	}
+	return out
+}
+
+// EMA is the exponential moving average over n days, seeded with the
+// SMA of the first n values
+func EMA(s Series, n int) Series {
+	out := nanSeries(len(s))
+	if n <= 0 || len(s) < n {
+		return out
+	}
+	k := 2 / float64(n+1)
+	var sum float64
+	for _, v := range s[:n] {
+		sum += v
+	}
+	out[n-1] = sum / float64(n)
+	for i := n; i < len(s); i++ {
+		out[i] = (s[i]-out[i-1])*k + out[i-1]
+	}
+	return out
+}
+
+// wilder smooths s the way Wilder does for RSI and ATR, starting with
+// the average of the n values from start
+func wilder(s Series, n, start int) Series {
+	out := nanSeries(len(s))
+	if n <= 0 || len(s) < start+n {
+		return out
+	}
+	var sum float64
+	for _, v := range s[start : start+n] {
+		sum += v
+	}
+	out[start+n-1] = sum / float64(n)
+	for i := start + n; i < len(s); i++ {
+		out[i] = (out[i-1]*float64(n-1) + s[i]) / float64(n)
+	}
+	return out
+}
+
+// RSI is Wilder's relative strength index over n days, from 0 to 100
+func RSI(s Series, n int) Series {
+	out := nanSeries(len(s))
+	if len(s) < 2 {
+		return out
+	}
+	gains, losses := make(Series, len(s)), make(Series, len(s))
+	for i := 1; i < len(s); i++ {
+		change := s[i] - s[i-1]
+		if change > 0 {
+			gains[i] = change
+		} else {
+			losses[i] = -change
+		}
+	}
+	avgGain, avgLoss := wilder(gains, n, 1), wilder(losses, n, 1)
+	for i := range s {
+		if math.IsNaN(avgGain[i]) {
+			continue
+		}
+		if avgLoss[i] == 0 {
+			out[i] = 100
+			continue
+		}
+		out[i] = 100 - 100/(1+avgGain[i]/avgLoss[i])
+	}
+	return out
+}
+
+// MACD returns the difference of the fast and slow EMAs, its signal
+// line, the EMA of the MACD over signal days, and their difference
+func MACD(s Series, fast, slow, signal int) (Series, Series, Series) {
+	macd := nanSeries(len(s))
+	fastEMA, slowEMA := EMA(s, fast), EMA(s, slow)
+	start := -1
+	for i := range s {
+		if math.IsNaN(fastEMA[i]) || math.IsNaN(slowEMA[i]) {
+			continue
+		}
+		macd[i] = fastEMA[i] - slowEMA[i]
+		if start < 0 {
+			start = i
+		}
+	}
+	sig := nanSeries(len(s))
+	hist := nanSeries(len(s))
+	if start < 0 {
+		return macd, sig, hist
+	}
+	tail := EMA(macd[start:], signal)
+	for i, v := range tail {
+		sig[start+i] = v
+		if !math.IsNaN(v) {
+			hist[start+i] = macd[start+i] - v
+		}
+	}
+	return macd, sig, hist
+}
+
+// TrueRange returns the true range of every play, the first play has
+// no previous close so its range is high minus low
+func TrueRange(plays []*Play) Series {
+	tr := make(Series, len(plays))
+	for i, p := range plays {
+		r := float64(p.High - p.Low)
+		if i > 0 {
+			prev := float64(plays[i-1].AdjClose)
+			r = math.Max(r, math.Max(math.Abs(float64(p.High)-prev), math.Abs(float64(p.Low)-prev)))
+		}
+		tr[i] = r
+	}
+	return tr
+}
+
+// ATR is Wilder's average true range over n days
+func ATR(plays []*Play, n int) Series {
+	return wilder(TrueRange(plays), n, 0)
+}
+
+// Bollinger returns the upper band, the SMA and the lower band over n
+// days, the bands are k population standard deviations from the SMA
+func Bollinger(s Series, n int, k float64) (Series, Series, Series) {
+	middle := SMA(s, n)
+	upper, lower := nanSeries(len(s)), nanSeries(len(s))
+	for i := range s {
+		if math.IsNaN(middle[i]) {
+			continue
+		}
+		var variance float64
+		for _, v := range s[i-n+1 : i+1] {
+			variance += (v - middle[i]) * (v - middle[i])
+		}
+		sd := math.Sqrt(variance / float64(n))
+		upper[i] = middle[i] + k*sd
+		lower[i] = middle[i] - k*sd
+	}
+	return upper, middle, lower
+}
+
+// VWAP is the volume weighted average of the typical price, the mean of
+// the high, low and close, accumulated from the first play. Days before
+// any volume are NaN.
+func VWAP(plays []*Play) Series {
+	out := nanSeries(len(plays))
+	var pv, volume float64
+	for i, p := range plays {
+		typical := float64(p.High+p.Low+p.AdjClose) / 3
+		pv += typical * float64(p.Pvolume)
+		volume += float64(p.Pvolume)
+		if volume > 0 {
+			out[i] = pv / volume
+		}
+	}
+	return out
+}
+
+// Highest returns the highest value of the n days before each day, not
+// counting the day itself
+func Highest(s Series, n int) Series {
+	out := nanSeries(len(s))
+	for i := n; i < len(s) && n > 0; i++ {
+		out[i] = s[i-n]
+		for _, v := range s[i-n : i] {
+			out[i] = math.Max(out[i], v)
+		}
+	}
+	return out
+}
+
+// Lowest returns the lowest value of the n days before each day, not
+// counting the day itself
+func Lowest(s Series, n int) Series {
+	out := nanSeries(len(s))
+	for i := n; i < len(s) && n > 0; i++ {
+		out[i] = s[i-n]
+		for _, v := range s[i-n : i] {
+			out[i] = math.Min(out[i], v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func testOHLCPlay(a Adapter, high, low, c, volume int) *Play {
+	p := NewPlay(a)
+	p.High = high
+	p.Low = low
+	p.AdjClose = c
+	p.Pvolume = volume
+	return p
+}
+
+// checkSeries compares a series with the expected values, NaN in want
+// means the value should be NaN
+func checkSeries(t *testing.T, name string, got Series, want []float64, tolerance float64) {
+	if len(got) != len(want) {
+		t.Errorf(`%s should have %d values got %d`, name, len(want), len(got))
+		return
+	}
+	for i := range want {
+		if math.IsNaN(want[i]) {
+			if !math.IsNaN(got[i]) {
+				t.Errorf(`%s[%d] should be NaN got %f`, name, i, got[i])
+			}
+			continue
+		}
+		if math.IsNaN(got[i]) || math.Abs(got[i]-want[i]) > tolerance {
+			t.Errorf(`%s[%d] should be %f got %f`, name, i, want[i], got[i])
+		}
+	}
+}
+
+func TestSMA(t *testing.T) {
+	nan := math.NaN()
+	checkSeries(t, `SMA`, SMA(Series{1, 2, 3, 4, 5}, 3), []float64{nan, nan, 2, 3, 4}, 1e-9)
+	s := Series{1, 2, 3}
+	if s.Last() != 3 || s.At(2) != 1 || !math.IsNaN(s.At(3)) {
+		t.Errorf(`Last and At disagree with the series`)
+	}
+}
+
+func TestEMA(t *testing.T) {
+	// the 10 day EMA example from StockCharts
+	s := Series{22.27, 22.19, 22.08, 22.17, 22.18, 22.13, 22.23, 22.43, 22.24, 22.29,
+		22.15, 22.39, 22.38, 22.61, 23.36, 24.05, 23.75, 23.83, 23.95, 23.63}
+	want := []float64{22.22, 22.21, 22.24, 22.27, 22.33, 22.52, 22.80, 22.97, 23.13, 23.28, 23.34}
+	got := EMA(s, 10)
+	checkSeries(t, `EMA`, got[9:], want, 0.01)
+	if !math.IsNaN(got[8]) {
+		t.Errorf(`EMA should be NaN before 10 days`)
+	}
+}
+
+func TestRSIReference(t *testing.T) {
+	// the 14 day RSI example from StockCharts, which rounds the averages
+	// at every step, hence the tolerance
+	s := Series{44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08,
+		45.89, 46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64}
+	want := []float64{70.53, 66.32, 66.55, 69.41, 66.36, 57.97}
+	got := RSI(s, 14)
+	checkSeries(t, `RSI`, got[14:], want, 0.1)
+	if !math.IsNaN(got[13]) {
+		t.Errorf(`RSI should be NaN before 14 changes`)
+	}
+	up := RSI(Series{1, 2, 3, 4}, 3)
+	if up.Last() != 100 {
+		t.Errorf(`only gains should give an RSI of 100 got %f`, up.Last())
+	}
+}
+
+func TestMACD(t *testing.T) {
+	// on a straight line every EMA lags the price by (n-1)/2 days, so
+	// the MACD of a 2 and 3 day EMA is a constant 0.5
+	s := Series{1, 2, 3, 4, 5, 6, 7, 8}
+	nan := math.NaN()
+	macd, signal, hist := MACD(s, 2, 3, 2)
+	checkSeries(t, `MACD`, macd, []float64{nan, nan, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5}, 1e-9)
+	checkSeries(t, `MACD signal`, signal, []float64{nan, nan, nan, 0.5, 0.5, 0.5, 0.5, 0.5}, 1e-9)
+	checkSeries(t, `MACD histogram`, hist, []float64{nan, nan, nan, 0, 0, 0, 0, 0}, 1e-9)
+}
+
+func TestATR(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	plays := []*Play{
+		testOHLCPlay(a, 100, 80, 90, 0),
+		testOHLCPlay(a, 110, 90, 100, 0),
+		testOHLCPlay(a, 120, 100, 110, 0),
+		// gaps down, the true range reaches back to the previous close
+		testOHLCPlay(a, 110, 70, 80, 0),
+		testOHLCPlay(a, 90, 80, 85, 0),
+	}
+	nan := math.NaN()
+	checkSeries(t, `TrueRange`, TrueRange(plays), []float64{20, 20, 20, 40, 10}, 1e-9)
+	checkSeries(t, `ATR`, ATR(plays, 3), []float64{nan, nan, 20, 80.0 / 3, 190.0 / 9}, 1e-9)
+}
+
+func TestBollinger(t *testing.T) {
+	// the mean is 5 and the population standard deviation 2
+	s := Series{2, 4, 4, 4, 5, 5, 7, 9}
+	upper, middle, lower := Bollinger(s, 8, 2)
+	if middle.Last() != 5 || upper.Last() != 9 || lower.Last() != 1 {
+		t.Errorf(`expected bands 9 5 1 got %f %f %f`, upper.Last(), middle.Last(), lower.Last())
+	}
+	if !math.IsNaN(upper[6]) {
+		t.Errorf(`bands should be NaN before 8 days`)
+	}
+}
+
+func TestVWAP(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	plays := []*Play{
+		testOHLCPlay(a, 0, 0, 0, 0),
+		testOHLCPlay(a, 12, 8, 10, 100),
+		testOHLCPlay(a, 15, 9, 12, 300),
+	}
+	checkSeries(t, `VWAP`, VWAP(plays), []float64{math.NaN(), 10, 11.5}, 1e-9)
+}
+
+func TestHighestLowest(t *testing.T) {
+	nan := math.NaN()
+	s := Series{3, 1, 4, 1, 5}
+	checkSeries(t, `Highest`, Highest(s, 2), []float64{nan, nan, 3, 4, 4}, 1e-9)
+	checkSeries(t, `Lowest`, Lowest(s, 2), []float64{nan, nan, 1, 1, 1}, 1e-9)
+}
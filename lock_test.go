@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOptimisticLocking(t *testing.T) {
+	a := newFakeAdapter()
+	a.rows = func(q string) []map[string]string {
+		if !strings.HasPrefix(q, `SELECT * FROM positions`) {
+			return nil
+		}
+		return []map[string]string{{`id`: `1`, `portfolio_id`: `7`, `symbol`: `AAA`, `started_at`: `2016-01-04 00:00:00`,
+			`closed_at`: ``, `ptype`: `long`, `buy`: `100`, `sell`: `0`, `stop_loss`: `90`, `quantity`: `10`, `lock_version`: `3`}}
+	}
+	p := NewPosition(a)
+	_, err := p.Find(1)
+	if err != nil || p.LockVersion != 3 {
+		t.Errorf(`expected lock_version 3 got %d %v`, p.LockVersion, err)
+		return
+	}
+	p.SetSell(120)
+	err = p.Save()
+	if err != nil || a.count("UPDATE positions SET sell = '120',lock_version = lock_version + 1 WHERE id = '1' AND lock_version = '3'") != 1 {
+		t.Errorf(`the update should check and increment the version %v %v`, err, a.executed)
+	}
+	if p.LockVersion != 4 {
+		t.Errorf(`the version should be 4 after saving got %d`, p.LockVersion)
+	}
+	// someone else saved the position in the meantime
+	a.noMatch = true
+	p.SetSell(130)
+	err = p.Save()
+	if err != ErrStaleObject {
+		t.Errorf(`expected ErrStaleObject got %v`, err)
+	}
+	if p.LockVersion != 4 || !p.IsSellDirty {
+		t.Errorf(`a stale save should leave the model as it was`)
+	}
+}
+
+func TestOptimisticLockingUpdaters(t *testing.T) {
+	a := newFakeAdapter()
+	a.rows = func(q string) []map[string]string {
+		if !strings.HasPrefix(q, `SELECT * FROM positions`) {
+			return nil
+		}
+		return []map[string]string{{`id`: `1`, `portfolio_id`: `7`, `symbol`: `AAA`, `started_at`: `2016-01-04 00:00:00`,
+			`closed_at`: ``, `ptype`: `long`, `buy`: `100`, `sell`: `0`, `stop_loss`: `90`, `quantity`: `10`, `lock_version`: `3`}}
+	}
+	p := NewPosition(a)
+	_, err := p.Find(1)
+	if err != nil {
+		t.Errorf(`could not find the position %v`, err)
+		return
+	}
+	_, err = p.UpdateSell(120)
+	if err != nil || a.count("UPDATE positions SET `sell` = '120', lock_version = lock_version + 1 WHERE `id` = '1' AND lock_version = '3'") != 1 {
+		t.Errorf(`the update should check and increment the version %v %v`, err, a.executed)
+	}
+	if p.LockVersion != 4 || p.Sell != 120 {
+		t.Errorf(`the version should be 4 after updating got %d`, p.LockVersion)
+	}
+	a.noMatch = true
+	_, err = p.UpdateSell(130)
+	if err != ErrStaleObject {
+		t.Errorf(`expected ErrStaleObject got %v`, err)
+	}
+	if p.LockVersion != 4 || p.Sell != 120 {
+		t.Errorf(`a stale update should leave the model as it was`)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestStructuredLogging(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	var b bytes.Buffer
+	a.SetLogs(&b)
+	a.Log(slog.LevelInfo, `query`, slog.String(`table`, `plays`), slog.String(`query`, `SELECT * FROM plays`), slog.Int(`rows`, 3))
+	if !strings.HasSuffix(b.String(), `query table=plays query="SELECT * FROM plays" rows=3`+"\n") || !strings.HasPrefix(b.String(), `[INFO]:`) {
+		t.Errorf(`expected a tagged line with the fields got %s`, b.String())
+	}
+	b.Reset()
+	a.Log(slog.LevelWarn, `retrying`, slog.String(`what`, `Ping`))
+	if !strings.HasPrefix(b.String(), `[WARN]:`) {
+		t.Errorf(`expected a WARN line got %s`, b.String())
+	}
+	b.Reset()
+	a.SetLogLevel(slog.LevelWarn)
+	a.LogInfo(`Hello World`)
+	a.LogDebug(`Hello World`)
+	if b.String() != `` {
+		t.Errorf(`nothing below warn should be logged got %s`, b.String())
+	}
+	a.LogError(errors.New(`Hello World`))
+	if !strings.Contains(b.String(), `Hello World`) {
+		t.Errorf(`errors should still be logged`)
+	}
+}
+
+func TestLogFilterSeesFields(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	var b bytes.Buffer
+	a.SetLogs(&b)
+	var seen string
+	a.SetLogFilter(func(tag string, val string) string {
+		seen = tag + ` ` + val
+		return strings.Replace(val, `secret`, `***`, -1)
+	})
+	a.Log(slog.LevelError, `login`, slog.String(`pass`, `secret`))
+	if seen != `ERROR login pass=secret` || !strings.HasSuffix(b.String(), `login pass=***`+"\n") {
+		t.Errorf(`the filter should see and rewrite the line got %s and %s`, seen, b.String())
+	}
+}
+
+func TestJSONLogging(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	a.LogFormat = `json`
+	var b bytes.Buffer
+	a.SetLogs(&b)
+	a.Log(slog.LevelInfo, `execute`, slog.String(`table`, `plays`), slog.Int64(`rows`, 2))
+	var rec map[string]interface{}
+	err := json.Unmarshal(b.Bytes(), &rec)
+	if err != nil || rec[`level`] != `INFO` || rec[`msg`] != `execute` || rec[`table`] != `plays` || rec[`rows`] != float64(2) {
+		t.Errorf(`expected a JSON record got %s %v`, b.String(), err)
+	}
+	b.Reset()
+	a.SetLogHandler(slog.NewTextHandler(&b, nil))
+	a.LogInfo(`Hello World`)
+	if !strings.Contains(b.String(), `msg="Hello World"`) {
+		t.Errorf(`expected the plugged in handler to be used got %s`, b.String())
+	}
+}
+
+func TestLogLevelFromYAML(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	err := a.LoadYAML([]byte("host: localhost\nuser: root\ndatabase: my_db\nlog_level: error\n"), ``)
+	if err != nil || a._level.Level() != slog.LevelError {
+		t.Errorf(`expected the error level got %v`, err)
+	}
+	err = a.LoadYAML([]byte("host: localhost\nuser: root\ndatabase: my_db\nlog_level: loud\nlog_format: xml\n"), ``)
+	if err == nil || !strings.Contains(err.Error(), `4:12: log_level must be`) || !strings.Contains(err.Error(), `5:13: log_format must be text or json`) {
+		t.Errorf(`expected errors for the log settings got %v`, err)
+	}
+}
+
+func TestStatementTable(t *testing.T) {
+	for q, table := range map[string]string{
+		`SELECT * FROM plays WHERE id = '1'`:         `plays`,
+		"INSERT INTO `positions` (symbol) VALUES ()": `positions`,
+		`UPDATE settings SET value = '1'`:            `settings`,
+		`SHOW TABLES`:                                ``,
+	} {
+		if statementTable(q) != table {
+			t.Errorf(`expected %s for %s got %s`, table, q, statementTable(q))
+		}
+	}
+}
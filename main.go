@@ -20,19 +20,19 @@ func oops(s string) error {
 var (
 	sarg            = flag.String(`s`, `default value`, `document the option here`)
 	logFilePath     = flag.String(`l`, `gopaper.log`, `the path to your chosen logfile`)
-	yamlAdapterPath = flag.String(`a`, `../gopaper.db.yml`, `the adapter YAML for gopress`)
+	yamlAdapterPath = flag.String(`a`, `../gopaper.db.yml`, `the adapter YAML for gopress, file#profile selects one of its profiles`)
+	jsonOutput      = flag.Bool(`json`, false, `print command output as JSON`)
+	printStats      = flag.Bool(`stats`, false, `print the statistics of the SQL statements run by the command`)
 )
 var Info *log.Logger
 var Error *log.Logger
 var mysql *MysqlAdapter
 
-func init() {
-	flag.Parse()
-}
 func main() {
+	flag.Parse()
 	file, err := os.OpenFile(*logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
 	if err != nil {
-		fmt.Printf("Failed to open log file:", err)
+		fmt.Println("Failed to open log file:", err)
 		return
 	}
 	defer file.Close()
@@ -42,12 +42,29 @@ func main() {
 	mysql, err = NewMysqlAdapterEx(*yamlAdapterPath)
 	if err != nil {
 		Error.Println(err)
+		fmt.Println(err)
 		return
 	}
+	defer mysql.Close()
 	mysql.SetLogs(file)
+	health := mysql.Health()
+	if !health.OK() {
+		Error.Println(health.Err)
+		fmt.Println(health.Err)
+		return
+	}
+	Info.Println("Database", health)
 	Info.Println("Database opened for reading")
+	err = runCommand(mysql, flag.Args(), os.Stdout)
+	for _, s := range mysql.Stats() {
+		Info.Println(s)
+		if *printStats {
+			fmt.Println(s)
+		}
+	}
 	if err != nil {
 		Error.Println(err)
+		fmt.Println(err)
 		return
 	}
 	return
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+// fakeRows is a rowScanner over n generated plays, so the paths from
+// *sql.Rows to the models can be measured without MySQL
+type fakeRows struct {
+	n, i    int
+	columns []string
+	row     [][]byte
+}
+
+func newFakeRows(n int) *fakeRows {
+	r := &fakeRows{n: n, columns: append([]string{`id`}, playColumns...)}
+	for _, v := range []string{`1`, `3`, `2016-01-04 00:00:00`, `10000`, `10500`, `9900`, `123456`, `100`, `100`, `10100`, `yahoo`} {
+		r.row = append(r.row, []byte(v))
+	}
+	return r
+}
+
+func (r *fakeRows) Columns() ([]string, error) {
+	return r.columns, nil
+}
+
+func (r *fakeRows) Next() bool {
+	r.i++
+	return r.i <= r.n
+}
+
+func (r *fakeRows) Scan(dest ...interface{}) error {
+	if len(dest) != len(r.row) {
+		return fmt.Errorf(`expected %d destinations got %d`, len(r.row), len(dest))
+	}
+	for i, d := range dest {
+		*d.(*sql.RawBytes) = r.row[i]
+	}
+	return nil
+}
+
+func (r *fakeRows) Err() error {
+	return nil
+}
+
+func testScanAdapter() *MysqlAdapter {
+	a := NewMysqlAdapter(``)
+	a.SetLogs(ioutil.Discard)
+	return a
+}
+
+func TestEachRow(t *testing.T) {
+	a := testScanAdapter()
+	p := NewPlay(a)
+	var n, volume int
+	err := a.eachRow(newFakeRows(3), func(r Row) error {
+		n++
+		if len(r.Columns()) != 11 || r.Get(`missing`) != nil {
+			t.Errorf(`wrong columns %v`, r.Columns())
+		}
+		err := p.FromRow(r)
+		volume += p.Pvolume
+		return err
+	})
+	if err != nil || n != 3 || volume != 3*123456 {
+		t.Errorf(`expected 3 rows got %d %d %v`, n, volume, err)
+	}
+	if p.Day.ToString() != `2016-01-04 00:00:00` || p.DataSource != `yahoo` || p.IsDirty() {
+		t.Errorf(`the row was not read %+v`, p)
+	}
+	stop := fmt.Errorf(`stop`)
+	n = 0
+	err = a.eachRow(newFakeRows(3), func(r Row) error {
+		n++
+		return stop
+	})
+	if err != stop || n != 1 {
+		t.Errorf(`an error should stop the iteration got %v after %d rows`, err, n)
+	}
+}
+
+func TestEachPlay(t *testing.T) {
+	a := newFakeAdapter()
+	a.rows = func(q string) []map[string]string {
+		return []map[string]string{testBarRow(1, `AAA`, 2016, 1, 4, 100, 100, 100, 100), testBarRow(2, `AAA`, 2016, 1, 5, 110, 110, 110, 110)}
+	}
+	var closes []int
+	err := EachPlay(a, `SELECT * FROM plays`, func(p *Play) error {
+		closes = append(closes, p.AdjClose)
+		return nil
+	})
+	if err != nil || len(closes) != 2 || closes[1] != 110 {
+		t.Errorf(`expected two plays got %v %v`, closes, err)
+	}
+}
+
+// BenchmarkQueryPlays reads plays the way Query and FromDBValueMap do,
+// one map and one MysqlValue per cell and a Play per row
+func BenchmarkQueryPlays(b *testing.B) {
+	a := testScanAdapter()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		results, err := a.readRows(newFakeRows(1000))
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, result := range results {
+			p := NewPlay(a)
+			err = p.FromDBValueMap(result)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkEachPlay reads the same plays the way QueryEach and EachPlay
+// do, reusing the Row and the Play
+func BenchmarkEachPlay(b *testing.B) {
+	a := testScanAdapter()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p := NewPlay(a)
+		err := a.eachRow(newFakeRows(1000), func(r Row) error {
+			return p.FromRow(r)
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
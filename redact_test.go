@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedactDSN(t *testing.T) {
+	for s, redacted := range map[string]string{
+		`root:rootpass@tcp(db.example.com:3306)/my_db?charset=utf8mb4`: `root:***@tcp(db.example.com:3306)/my_db?charset=utf8mb4`,
+		`dial failed with root:p@ss:w/rd@unix(/tmp/mysql.sock)/my_db`:  `dial failed with root:***@unix(/tmp/mysql.sock)/my_db`,
+		`root:rootpass@/my_db`:   `root:***@/my_db`,
+		`no dsn here: just@text`: `no dsn here: just@text`,
+	} {
+		if RedactDSN(s) != redacted {
+			t.Errorf(`expected %s got %s`, redacted, RedactDSN(s))
+		}
+	}
+}
+
+func TestOopsRedactsPassword(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	var b bytes.Buffer
+	a.SetLogs(&b)
+	a.Host, a.User, a.Pass, a.Database = `localhost`, `root`, `s3cret`, `my_db`
+	a.Params = map[string]string{`parseTime`: `maybe`}
+	err := a.Open(a.Host, a.User, a.Pass, a.Database)
+	if err == nil || strings.Contains(err.Error(), `s3cret`) || !strings.Contains(err.Error(), `root:***@/my_db`) {
+		t.Errorf(`the error should name the DSN without the password got %v`, err)
+	}
+	if strings.Contains(b.String(), `s3cret`) {
+		t.Errorf(`the log should not contain the password got %s`, b.String())
+	}
+	b.Reset()
+	a.Log(slog.LevelInfo, `connecting with root:s3cret@/my_db`, slog.String(`dsn`, `root:s3cret@tcp(localhost:3306)/my_db`))
+	if strings.Contains(b.String(), `s3cret`) || !strings.Contains(b.String(), `connecting with root:***@/my_db dsn=root:***@tcp(localhost:3306)/my_db`) {
+		t.Errorf(`the DSNs of the message and attributes should be redacted got %s`, b.String())
+	}
+}
+
+func TestRedactShortPassword(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	var b bytes.Buffer
+	a.SetLogs(&b)
+	a.Host, a.User, a.Pass, a.Database = `localhost`, `root`, `s`, `my_db`
+	a.Log(slog.LevelInfo, `slow statements`, slog.String(`query`, `SELECT * FROM positions WHERE symbol = 'SPY'`))
+	if !strings.Contains(b.String(), `slow statements query="SELECT * FROM positions WHERE symbol = 'SPY'"`) {
+		t.Errorf(`only DSNs should be redacted got %s`, b.String())
+	}
+	a.Params = map[string]string{`parseTime`: `maybe`}
+	err := a.Open(a.Host, a.User, a.Pass, a.Database)
+	if err == nil || !strings.Contains(err.Error(), `root:***@/my_db`) {
+		t.Errorf(`the DSN should be redacted got %v`, err)
+	}
+}
+
+func TestMaskQueries(t *testing.T) {
+	if MaskSQL(`UPDATE positions SET symbol = 'AAA', quantity = 10 WHERE id = '1'`) != `UPDATE positions SET symbol = ?, quantity = ? WHERE id = ?` {
+		t.Errorf(`the values should be masked got %s`, MaskSQL(`UPDATE positions SET symbol = 'AAA', quantity = 10 WHERE id = '1'`))
+	}
+	a := NewMysqlAdapter(``)
+	var b, slow bytes.Buffer
+	a.SetLogs(&b)
+	a.SetSlowLog(&slow)
+	a.SlowQuery = time.Hour
+	a.MaskQueries = true
+	a.recordStatement(`execute`, `INSERT INTO notes (body) VALUES ('my secret plan')`, time.Now().Add(-2*time.Hour), 0,
+		errors.New(`Error 1062: Duplicate entry 'my secret plan' for key 'body'`))
+	if strings.Contains(b.String(), `secret`) || !strings.Contains(b.String(), `INSERT INTO notes (body) VALUES (?)`) ||
+		!strings.Contains(b.String(), `Error 1062: Duplicate entry ? for key ?`) {
+		t.Errorf(`the query and the error should be masked got %s`, b.String())
+	}
+	b.Reset()
+	a.recordStatement(`query`, `SELECT * FROM notes WHERE body = 'my secret plan'`, time.Now().Add(-2*time.Hour), 0, nil)
+	if strings.Contains(slow.String(), `secret`) || !strings.Contains(slow.String(), `SELECT * FROM notes WHERE body = ?`) {
+		t.Errorf(`the slow log should be masked got %s`, slow.String())
+	}
+	if s := a.Stats(); len(s) != 2 || strings.Contains(s[0].Shape+s[1].Shape, `secret`) {
+		t.Errorf(`the stats should only hold shapes got %v`, s)
+	}
+}
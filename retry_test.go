@@ -0,0 +1,155 @@
+package main
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+)
+
+func TestIsTransient(t *testing.T) {
+	transient := []error{
+		driver.ErrBadConn,
+		mysqldriver.ErrInvalidConn,
+		fmt.Errorf(`could not Exec stmt %w`, &mysqldriver.MySQLError{Number: 1213, Message: `Deadlock found`}),
+		&mysqldriver.MySQLError{Number: 1205, Message: `Lock wait timeout exceeded`},
+	}
+	for _, err := range transient {
+		if !isTransient(err) {
+			t.Errorf(`%v should be transient`, err)
+		}
+	}
+	permanent := []error{
+		errors.New(`you must first open the connection`),
+		&mysqldriver.MySQLError{Number: 1062, Message: `Duplicate entry`},
+	}
+	for _, err := range permanent {
+		if isTransient(err) {
+			t.Errorf(`%v should not be transient`, err)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	a.Retries = 2
+	a.RetryBackoff = 0
+	calls := 0
+	err := a.retry(`Query`, func() error {
+		calls++
+		if calls < 3 {
+			return driver.ErrBadConn
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf(`expected success on the third attempt got %v after %d`, err, calls)
+	}
+	calls = 0
+	err = a.retry(`Query`, func() error {
+		calls++
+		return driver.ErrBadConn
+	})
+	if err != driver.ErrBadConn || calls != 3 {
+		t.Errorf(`expected to give up after 2 retries got %v after %d`, err, calls)
+	}
+	calls = 0
+	dup := &mysqldriver.MySQLError{Number: 1062, Message: `Duplicate entry`}
+	err = a.retry(`Execute`, func() error {
+		calls++
+		return dup
+	})
+	if err != dup || calls != 1 {
+		t.Errorf(`a permanent error should not be retried got %v after %d`, err, calls)
+	}
+}
+
+func TestHealthClosed(t *testing.T) {
+	h := NewMysqlAdapter(``).Health()
+	if h.OK() || h.String() != `unavailable: you must first open the connection` {
+		t.Errorf(`a closed adapter should not be healthy got %s`, h)
+	}
+}
+
+// TestExecuteRetries fails the exec or commit of a statement once, only a
+// statement the server has rolled back may run again
+func TestExecuteRetries(t *testing.T) {
+	cases := []struct {
+		name     string
+		stage    string
+		err      error
+		attempts int
+	}{
+		{`a lost commit may have been applied`, `commit`, driver.ErrBadConn, 1},
+		{`a broken commit may have been applied`, `commit`, mysqldriver.ErrInvalidConn, 1},
+		{`a statement lost on the way may have been applied`, `exec`, mysqldriver.ErrInvalidConn, 1},
+		{`a deadlock is rolled back by the server`, `exec`, &mysqldriver.MySQLError{Number: 1213, Message: `Deadlock found`}, 2},
+	}
+	for _, c := range cases {
+		a, d := newCountingAdapter()
+		a.RetryBackoff = 0
+		attempts := 0
+		d.fail = func(stage string) error {
+			if stage != c.stage {
+				return nil
+			}
+			attempts++
+			if attempts == 1 {
+				return c.err
+			}
+			return nil
+		}
+		_, err := a.Execute(`INSERT INTO notes (body) VALUES ('once')`)
+		if attempts != c.attempts {
+			t.Errorf(`%s: expected %d attempts got %d`, c.name, c.attempts, attempts)
+		}
+		if (c.attempts == 1) != errors.Is(err, c.err) {
+			t.Errorf(`%s: unexpected error %v`, c.name, err)
+		}
+		a.Close()
+	}
+}
+
+// TestQueryDeadlockInTransaction deadlocks a query of a Transaction, the
+// server has rolled back the writes before it so the query must not run
+// again on its own
+func TestQueryDeadlockInTransaction(t *testing.T) {
+	a, d := newCountingAdapter()
+	defer a.Close()
+	a.RetryBackoff = 0
+	deadlock := &mysqldriver.MySQLError{Number: 1213, Message: `Deadlock found`}
+	queries := 0
+	d.fail = func(stage string) error {
+		if stage != `query` {
+			return nil
+		}
+		queries++
+		if queries == 1 {
+			return deadlock
+		}
+		return nil
+	}
+	after := false
+	err := a.Transaction(func(ta Adapter) error {
+		_, err := ta.Execute(`INSERT INTO notes (body) VALUES ('first')`)
+		if err != nil {
+			return err
+		}
+		_, err = ta.Query(`SELECT * FROM notes`)
+		if err != nil {
+			return err
+		}
+		after = true
+		return nil
+	})
+	if !errors.Is(err, deadlock) || queries != 1 || after {
+		t.Errorf(`the deadlock should fail the transaction got %v after %d queries`, err, queries)
+	}
+	queries = 0
+	_, err = a.Query(`SELECT * FROM notes`)
+	if err != nil || queries != 2 {
+		t.Errorf(`outside of a transaction the deadlock should be retried got %v after %d queries`, err, queries)
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+)
+
+// The settings read by LoadRiskLimits, percentages are of the
+// portfolio's equity
+const (
+	SettingRiskPerTrade      = `risk.per_trade_percent`
+	SettingRiskATRPeriod     = `risk.atr_period`
+	SettingRiskATRMultiplier = `risk.atr_multiplier`
+	SettingRiskMaxExposure   = `risk.max_exposure_percent`
+	SettingRiskMaxPosition   = `risk.max_position_percent`
+)
+
+// RiskLimits configures the risk calculator
+type RiskLimits struct {
+	// RiskPercent of equity is lost when the stop is hit
+	RiskPercent float64
+	// ATRPeriod and ATRMultiplier place the stop ATRMultiplier average
+	// true ranges away from the entry
+	ATRPeriod     int
+	ATRMultiplier float64
+	// MaxExposurePercent caps the value of all open positions, trades
+	// which would take the portfolio past it are refused. Zero is no cap.
+	MaxExposurePercent float64
+	// MaxPositionPercent caps the value of a single position, the
+	// quantity is reduced to fit. Zero is no cap.
+	MaxPositionPercent float64
+}
+
+// DefaultRiskLimits are used for the settings which are not set
+var DefaultRiskLimits = RiskLimits{
+	RiskPercent:   1,
+	ATRPeriod:     14,
+	ATRMultiplier: 2,
+}
+
+// LoadRiskLimits reads the RiskLimits from the settings table
+func LoadRiskLimits(a Adapter) (RiskLimits, error) {
+	l := DefaultRiskLimits
+	var err error
+	if l.RiskPercent, err = settingFloat(a, SettingRiskPerTrade, l.RiskPercent); err != nil {
+		return l, err
+	}
+	period, err := settingFloat(a, SettingRiskATRPeriod, float64(l.ATRPeriod))
+	if err != nil {
+		return l, err
+	}
+	l.ATRPeriod = int(period)
+	if l.ATRMultiplier, err = settingFloat(a, SettingRiskATRMultiplier, l.ATRMultiplier); err != nil {
+		return l, err
+	}
+	if l.MaxExposurePercent, err = settingFloat(a, SettingRiskMaxExposure, l.MaxExposurePercent); err != nil {
+		return l, err
+	}
+	if l.MaxPositionPercent, err = settingFloat(a, SettingRiskMaxPosition, l.MaxPositionPercent); err != nil {
+		return l, err
+	}
+	return l, nil
+}
+
+// TradeProposal is the quantity and stop the risk calculator suggests
+// for a trade. Money values are in the units stored in Play.
+type TradeProposal struct {
+	Symbol   string
+	Ptype    string
+	Entry    int
+	StopLoss int
+	Quantity int
+	ATR      float64
+	// Risk is what is lost when the stop is hit
+	Risk int
+	// Equity and Exposure describe the portfolio before the trade
+	Equity   int
+	Exposure int
+	// Capped is true when MaxPositionPercent reduced the quantity
+	Capped bool
+}
+
+// String renders the proposal on one line
+func (t *TradeProposal) String() string {
+	capped := ``
+	if t.Capped {
+		capped = `, capped by the max position size`
+	}
+	return fmt.Sprintf(`%s %d %s at %.2f stop %.2f (ATR %.2f), risking %.2f of %.2f%s`,
+		t.Ptype, t.Quantity, t.Symbol, fromPrice(t.Entry), fromPrice(t.StopLoss),
+		t.ATR/PriceScale, fromPrice(t.Risk), fromPrice(t.Equity), capped)
+}
+
+// ProposeTrade sizes a trade so that hitting a stop ATRMultiplier ATRs
+// from entry loses RiskPercent of equity. exposure is the value of the
+// positions already open. An error is returned when no quantity fits.
+func ProposeTrade(l RiskLimits, equity, exposure, entry int, atr float64, short bool) (*TradeProposal, error) {
+	t := &TradeProposal{Ptype: `long`, Entry: entry, ATR: atr, Equity: equity, Exposure: exposure}
+	if short {
+		t.Ptype = `short`
+	}
+	if equity <= 0 {
+		return nil, oops(`the portfolio has no equity to risk`)
+	}
+	if entry <= 0 || atr <= 0 || math.IsNaN(atr) {
+		return nil, oops(`an entry price and an ATR are needed to size the trade`)
+	}
+	distance := int(math.Ceil(atr * l.ATRMultiplier))
+	if distance <= 0 {
+		return nil, oops(`the ATR multiplier must be positive`)
+	}
+	t.StopLoss = entry - distance
+	if short {
+		t.StopLoss = entry + distance
+	}
+	if t.StopLoss <= 0 {
+		return nil, oops(fmt.Sprintf(`a stop %d ATRs away is below zero`, int(l.ATRMultiplier)))
+	}
+	t.Quantity = int(float64(equity) * l.RiskPercent / 100 / float64(distance))
+	if l.MaxPositionPercent > 0 {
+		largest := int(float64(equity) * l.MaxPositionPercent / 100 / float64(entry))
+		if t.Quantity > largest {
+			t.Quantity = largest
+			t.Capped = true
+		}
+	}
+	if t.Quantity <= 0 {
+		return nil, oops(`the risk allowed per trade is too small for a single unit`)
+	}
+	t.Risk = t.Quantity * distance
+	if l.MaxExposurePercent > 0 {
+		limit := float64(equity) * l.MaxExposurePercent / 100
+		if float64(exposure+t.Quantity*entry) > limit {
+			return nil, oops(fmt.Sprintf(`refused: exposure would be %.2f, the limit is %.2f`,
+				fromPrice(exposure+t.Quantity*entry), limit/PriceScale))
+		}
+	}
+	return t, nil
+}
+
+// lastClose returns the close of the last Play of a Position, or its
+// entry price when it has none
+func lastClose(a Adapter, p *Position) (int, error) {
+	plays, err := playsForPosition(a, p.Id)
+	if err != nil {
+		return 0, err
+	}
+	if len(plays) == 0 {
+		return p.Buy, nil
+	}
+	return plays[len(plays)-1].AdjClose, nil
+}
+
+// PortfolioExposure returns the equity of the portfolio, its Value plus
+// the realized and unrealized profits, and the value of its open
+// positions at their last close
+func PortfolioExposure(a Adapter, p *Portfolio) (int, int, error) {
+	positions, err := positionsForPortfolio(a, p.Id)
+	if err != nil {
+		return 0, 0, err
+	}
+	equity, exposure := p.Value, 0
+	for _, pos := range positions {
+		if isClosedPosition(pos) {
+			equity += int(PositionPnL(pos))
+			continue
+		}
+		c, err := lastClose(a, pos)
+		if err != nil {
+			return 0, 0, err
+		}
+		open := *pos
+		open.Sell = c
+		equity += int(PositionPnL(&open))
+		exposure += c * pos.Quantity
+	}
+	return equity, exposure, nil
+}
+
+// symbolHistory returns the stored bars of a symbol ordered by day
+func symbolHistory(a Adapter, symbol string) ([]*Play, error) {
+	market, err := loadMarketBars(a, []string{symbol})
+	if err != nil {
+		return nil, err
+	}
+	var plays []*Play
+	for _, day := range market.days {
+		plays = append(plays, market.byDay[day][symbol])
+	}
+	return plays, nil
+}
+
+// NewTradeProposal proposes a trade in symbol for the portfolio using the
+// RiskLimits from the settings. An entry of zero uses the last close.
+func NewTradeProposal(a Adapter, p *Portfolio, symbol string, entry int, short bool) (*TradeProposal, error) {
+	l, err := LoadRiskLimits(a)
+	if err != nil {
+		return nil, err
+	}
+	plays, err := symbolHistory(a, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if len(plays) <= l.ATRPeriod {
+		return nil, a.Oops(fmt.Sprintf(`%s needs more than %d bars for the ATR, there are %d`, symbol, l.ATRPeriod, len(plays)))
+	}
+	if entry == 0 {
+		entry = plays[len(plays)-1].AdjClose
+	}
+	equity, exposure, err := PortfolioExposure(a, p)
+	if err != nil {
+		return nil, err
+	}
+	t, err := ProposeTrade(l, equity, exposure, entry, ATR(plays, l.ATRPeriod).Last(), short)
+	if err != nil {
+		return nil, err
+	}
+	t.Symbol = symbol
+	return t, nil
+}
+
+// riskCommand implements `gopaper risk -portfolio ref -symbol sym`
+func riskCommand(a Adapter, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet(`risk`, flag.ContinueOnError)
+	fs.SetOutput(w)
+	portfolio := fs.String(`portfolio`, ``, `the id or name of the portfolio`)
+	symbol := fs.String(`symbol`, ``, `the symbol to trade`)
+	entry := fs.Float64(`entry`, 0, `the entry price, defaults to the last close`)
+	short := fs.Bool(`short`, false, `propose a short position`)
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *portfolio == `` || *symbol == `` {
+		return oops(`usage: gopaper risk -portfolio ref -symbol sym [-entry price] [-short]`)
+	}
+	p, err := findPortfolio(a, *portfolio)
+	if err != nil {
+		return err
+	}
+	t, err := NewTradeProposal(a, p, *symbol, toPrice(*entry), *short)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, t)
+	return nil
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The names of the rules checked by CheckRiskRules. Each is read from the
+// setting risk.<portfolio id>.<name>, falling back to risk.<name> for
+// every portfolio, and is not enforced when neither is set or it is zero.
+const (
+	RuleMaxOpenPositions = `max_open_positions`
+	RuleMaxPosition      = `max_position_percent`
+	RuleMaxSymbol        = `max_symbol_percent`
+	RuleMaxSector        = `max_sector_percent`
+	RuleDailyLoss        = `daily_loss_percent`
+)
+
+// sectorSettingPrefix is followed by a symbol to name its sector, e.g.
+// sector.AAPL = technology
+const sectorSettingPrefix = `sector.`
+
+// RiskRules are the pre-trade limits of a portfolio, percentages are of
+// the portfolio's equity and zero means no limit
+type RiskRules struct {
+	MaxOpenPositions int
+	// MaxPositionPercent limits the value of a single position
+	MaxPositionPercent float64
+	// MaxSymbolPercent limits the value of all the positions in a symbol
+	MaxSymbolPercent float64
+	// MaxSectorPercent limits the value of all the positions in a sector
+	MaxSectorPercent float64
+	// DailyLossPercent stops new positions once the positions closed on
+	// the day have lost this much
+	DailyLossPercent float64
+}
+
+// IsZero returns true when no rule is enforced
+func (r RiskRules) IsZero() bool {
+	return r == RiskRules{}
+}
+
+// LoadRiskRules reads the RiskRules of a portfolio from the settings, all
+// of them with a single query
+func LoadRiskRules(a Adapter, portfolioId int64) (RiskRules, error) {
+	var r RiskRules
+	var open float64
+	rules := []struct {
+		name string
+		dst  *float64
+	}{
+		{RuleMaxOpenPositions, &open},
+		{RuleMaxPosition, &r.MaxPositionPercent},
+		{RuleMaxSymbol, &r.MaxSymbolPercent},
+		{RuleMaxSector, &r.MaxSectorPercent},
+		{RuleDailyLoss, &r.DailyLossPercent},
+	}
+	var keys []string
+	for _, f := range rules {
+		keys = append(keys, fmt.Sprintf(`'%s'`, a.SafeString(fmt.Sprintf(`risk.%d.%s`, portfolioId, f.name))),
+			fmt.Sprintf(`'%s'`, a.SafeString(`risk.`+f.name)))
+	}
+	m := NewSetting(a)
+	q := fmt.Sprintf("SELECT * FROM %s WHERE `skey` IN (%s) ORDER BY `id`", m._table, strings.Join(keys, `, `))
+	results, err := a.Query(q)
+	if err != nil {
+		return r, err
+	}
+	// the first row of a key wins, as in GetSetting
+	values := make(map[string]string)
+	for _, result := range results {
+		s := NewSetting(a)
+		err = s.FromDBValueMap(result)
+		if err != nil {
+			return r, err
+		}
+		if _, ok := values[s.Skey]; !ok {
+			values[s.Skey] = s.Svalue
+		}
+	}
+	for _, f := range rules {
+		key := fmt.Sprintf(`risk.%d.%s`, portfolioId, f.name)
+		v, ok := values[key]
+		if !ok {
+			key = `risk.` + f.name
+			v, ok = values[key]
+		}
+		if !ok {
+			continue
+		}
+		*f.dst, err = strconv.ParseFloat(v, 64)
+		if err != nil {
+			return r, a.Oops(fmt.Sprintf(`setting %s should be a number, got %q`, key, v))
+		}
+	}
+	r.MaxOpenPositions = int(open)
+	return r, nil
+}
+
+// RuleViolation describes one rule a position breaks
+type RuleViolation struct {
+	Rule  string
+	Limit float64
+	Value float64
+}
+
+func (v RuleViolation) String() string {
+	return fmt.Sprintf(`%s: %.2f is over the limit of %.2f`, v.Rule, v.Value, v.Limit)
+}
+
+// RuleViolationError is returned by Position.Create and Position.Save
+// when the position breaks the risk rules of its portfolio
+type RuleViolationError struct {
+	PortfolioId int64
+	Symbol      string
+	Violations  []RuleViolation
+}
+
+func (e *RuleViolationError) Error() string {
+	var parts []string
+	for _, v := range e.Violations {
+		parts = append(parts, v.String())
+	}
+	return fmt.Sprintf(`position in %s breaks the risk rules of portfolio %d: %s`,
+		e.Symbol, e.PortfolioId, strings.Join(parts, `; `))
+}
+
+// RiskState is what the rules are checked against, the position being
+// checked is not part of Open
+type RiskState struct {
+	Equity int
+	Open   []*Position
+	// Sectors maps symbols to their sector, symbols without one are
+	// not part of any sector
+	Sectors map[string]string
+	// LossToday is the loss of the positions closed on the day the new
+	// position starts, as a positive number. It is zero for positions
+	// which are already stored, the limit only stops new ones.
+	LossToday int
+}
+
+// positionValue is the value of a position at its entry price
+func positionValue(p *Position) float64 {
+	return float64(p.Buy * p.Quantity)
+}
+
+// CheckRiskRules returns a *RuleViolationError listing every rule p
+// breaks, or nil
+func CheckRiskRules(r RiskRules, s RiskState, p *Position) error {
+	e := &RuleViolationError{PortfolioId: p.PortfolioId, Symbol: p.Symbol}
+	percent := func(v float64) float64 {
+		if s.Equity <= 0 {
+			return 100
+		}
+		return v / float64(s.Equity) * 100
+	}
+	if r.MaxOpenPositions > 0 && len(s.Open)+1 > r.MaxOpenPositions {
+		e.Violations = append(e.Violations, RuleViolation{RuleMaxOpenPositions, float64(r.MaxOpenPositions), float64(len(s.Open) + 1)})
+	}
+	if r.MaxPositionPercent > 0 {
+		if v := percent(positionValue(p)); v > r.MaxPositionPercent {
+			e.Violations = append(e.Violations, RuleViolation{RuleMaxPosition, r.MaxPositionPercent, v})
+		}
+	}
+	if r.MaxSymbolPercent > 0 {
+		total := positionValue(p)
+		for _, o := range s.Open {
+			if o.Symbol == p.Symbol {
+				total += positionValue(o)
+			}
+		}
+		if v := percent(total); v > r.MaxSymbolPercent {
+			e.Violations = append(e.Violations, RuleViolation{RuleMaxSymbol, r.MaxSymbolPercent, v})
+		}
+	}
+	if sector := s.Sectors[p.Symbol]; r.MaxSectorPercent > 0 && sector != `` {
+		total := positionValue(p)
+		for _, o := range s.Open {
+			if s.Sectors[o.Symbol] == sector {
+				total += positionValue(o)
+			}
+		}
+		if v := percent(total); v > r.MaxSectorPercent {
+			e.Violations = append(e.Violations, RuleViolation{RuleMaxSector + ` ` + sector, r.MaxSectorPercent, v})
+		}
+	}
+	if r.DailyLossPercent > 0 && s.LossToday > 0 {
+		if v := percent(float64(s.LossToday)); v >= r.DailyLossPercent {
+			e.Violations = append(e.Violations, RuleViolation{RuleDailyLoss, r.DailyLossPercent, v})
+		}
+	}
+	if len(e.Violations) == 0 {
+		return nil
+	}
+	return e
+}
+
+// symbolSector returns the sector of a symbol from the settings
+func symbolSector(a Adapter, symbol string) (string, error) {
+	v, _, err := GetSetting(a, sectorSettingPrefix+symbol)
+	return v, err
+}
+
+// loadRiskState gathers the RiskState of the portfolio of p
+func loadRiskState(a Adapter, portfolio *Portfolio, p *Position, rules RiskRules) (RiskState, error) {
+	s := RiskState{Sectors: make(map[string]string)}
+	equity, _, err := PortfolioExposure(a, portfolio)
+	if err != nil {
+		return s, err
+	}
+	s.Equity = equity
+	positions, err := positionsForPortfolio(a, portfolio.Id)
+	if err != nil {
+		return s, err
+	}
+	day := truncateDay(time.Now().UTC())
+	if !p.StartedAt.IsZero() {
+		day = truncateDay(p.StartedAt.ToTime())
+	}
+	for _, o := range positions {
+		if p.Id != 0 && o.Id == p.Id {
+			continue
+		}
+		if isClosedPosition(o) {
+			if p.Id == 0 && truncateDay(o.ClosedAt.ToTime()).Equal(day) {
+				s.LossToday -= int(PositionPnL(o))
+			}
+			continue
+		}
+		s.Open = append(s.Open, o)
+	}
+	if s.LossToday < 0 {
+		s.LossToday = 0
+	}
+	if rules.MaxSectorPercent > 0 {
+		for _, o := range append([]*Position{p}, s.Open...) {
+			if _, ok := s.Sectors[o.Symbol]; ok {
+				continue
+			}
+			sector, err := symbolSector(a, o.Symbol)
+			if err != nil {
+				return s, err
+			}
+			s.Sectors[o.Symbol] = sector
+		}
+	}
+	return s, nil
+}
+
+// checkPositionRisk is the validator registered for Position, it only
+// looks at open positions so closing a position is never refused. A
+// stored position is only checked again when a column the rules depend
+// on changed.
+func checkPositionRisk(a Adapter, model interface{}) error {
+	p, ok := model.(*Position)
+	if !ok || isClosedPosition(p) || p.PortfolioId == 0 {
+		return nil
+	}
+	if !p._new && !p.IsQuantityDirty && !p.IsBuyDirty && !p.IsPortfolioIdDirty && !p.IsSymbolDirty {
+		return nil
+	}
+	rules, err := LoadRiskRules(a, p.PortfolioId)
+	if err != nil || rules.IsZero() {
+		return err
+	}
+	portfolio := NewPortfolio(a)
+	_, err = portfolio.Find(p.PortfolioId)
+	if err != nil {
+		return a.Oops(fmt.Sprintf(`no portfolio with id %d`, p.PortfolioId))
+	}
+	s, err := loadRiskState(a, portfolio, p, rules)
+	if err != nil {
+		return err
+	}
+	err = CheckRiskRules(rules, s, p)
+	if err != nil {
+		a.LogError(err)
+	}
+	return err
+}
+
+func init() {
+	RegisterValidator(`Position`, checkPositionRisk)
+}
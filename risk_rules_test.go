@@ -0,0 +1,107 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckRiskRules(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	open := testPosition(a, `long`, 100, 0, 100, nil, nil)
+	open.Symbol = `AAA`
+	other := testPosition(a, `long`, 100, 0, 100, nil, nil)
+	other.Symbol = `BBB`
+	p := testPosition(a, `long`, 100, 0, 50, nil, nil)
+	p.Symbol = `AAA`
+	s := RiskState{Equity: 100000, Open: []*Position{open, other}, Sectors: map[string]string{`AAA`: `tech`, `BBB`: `tech`}}
+	err := CheckRiskRules(RiskRules{MaxOpenPositions: 3, MaxPositionPercent: 10, MaxSymbolPercent: 20, MaxSectorPercent: 30}, s, p)
+	if err != nil {
+		t.Errorf(`the position is within the rules %s`, err)
+	}
+	err = CheckRiskRules(RiskRules{MaxOpenPositions: 2, MaxPositionPercent: 4, MaxSymbolPercent: 14, MaxSectorPercent: 24}, s, p)
+	v, ok := err.(*RuleViolationError)
+	if !ok {
+		t.Errorf(`expected a RuleViolationError got %v`, err)
+		return
+	}
+	var rules []string
+	for _, r := range v.Violations {
+		rules = append(rules, r.Rule)
+	}
+	if strings.Join(rules, `,`) != `max_open_positions,max_position_percent,max_symbol_percent,max_sector_percent tech` {
+		t.Errorf(`wrong violations %v`, rules)
+	}
+	s.LossToday = 2000
+	err = CheckRiskRules(RiskRules{DailyLossPercent: 2}, s, p)
+	if err == nil || !strings.Contains(err.Error(), RuleDailyLoss) {
+		t.Errorf(`the daily loss limit should stop the position got %v`, err)
+	}
+}
+
+func TestPositionCreateChecksRiskRules(t *testing.T) {
+	a := newFakeAdapter()
+	a.rows = func(q string) []map[string]string {
+		switch {
+		case strings.Contains(q, `'risk.max_open_positions'`):
+			return []map[string]string{{`id`: `1`, `skey`: `risk.max_open_positions`, `svalue`: `1`}}
+		case strings.Contains(q, `FROM portfolios`):
+			return []map[string]string{{`id`: `7`, `name`: `test`, `description`: ``, `value`: `100000`}}
+		case strings.Contains(q, `FROM positions`):
+			return []map[string]string{{`id`: `3`, `portfolio_id`: `7`, `symbol`: `AAA`, `started_at`: `2016-01-04 00:00:00`,
+				`closed_at`: ``, `ptype`: `long`, `buy`: `100`, `sell`: `0`, `stop_loss`: `0`, `quantity`: `10`, `lock_version`: `0`}}
+		}
+		return nil
+	}
+	p := NewPosition(a)
+	p.PortfolioId = 7
+	p.Symbol = `BBB`
+	p.Buy = 100
+	p.Quantity = 10
+	err := p.Create()
+	if _, ok := err.(*RuleViolationError); !ok {
+		t.Errorf(`expected a RuleViolationError got %v`, err)
+	}
+	if a.count(`INSERT INTO positions`) != 0 {
+		t.Errorf(`the position should not be stored %v`, a.executed)
+	}
+	// closing a position is never refused
+	p.ClosedAt = testDateTime(a, 2016, 1, 5)
+	err = p.Create()
+	if err != nil || a.count(`INSERT INTO positions`) != 1 {
+		t.Errorf(`a closed position should be stored %v`, err)
+	}
+}
+
+func TestLoadRiskRulesOnce(t *testing.T) {
+	a := newFakeAdapter()
+	var settings int
+	a.rows = func(q string) []map[string]string {
+		switch {
+		case strings.HasPrefix(q, `SELECT * FROM settings`):
+			settings++
+			return []map[string]string{{`id`: `1`, `skey`: `risk.max_position_percent`, `svalue`: `10`},
+				{`id`: `2`, `skey`: `risk.7.max_position_percent`, `svalue`: `20`},
+				{`id`: `3`, `skey`: `risk.daily_loss_percent`, `svalue`: `5`}}
+		case strings.HasPrefix(q, `SELECT * FROM positions`):
+			return []map[string]string{{`id`: `3`, `portfolio_id`: `7`, `symbol`: `AAA`, `started_at`: `2016-01-04 00:00:00`,
+				`closed_at`: ``, `ptype`: `long`, `buy`: `100`, `sell`: `0`, `stop_loss`: `0`, `quantity`: `10`, `lock_version`: `0`}}
+		}
+		return nil
+	}
+	r, err := LoadRiskRules(a, 7)
+	if err != nil || settings != 1 || r.MaxPositionPercent != 20 || r.DailyLossPercent != 5 || r.MaxOpenPositions != 0 {
+		t.Errorf(`expected the rules of portfolio 7 from one query got %+v after %d %v`, r, settings, err)
+	}
+	p := NewPosition(a)
+	_, err = p.Find(3)
+	if err != nil {
+		t.Errorf(`could not find the position %v`, err)
+		return
+	}
+	settings = 0
+	p.SetStopLoss(90)
+	err = p.Save()
+	if err != nil || settings != 0 {
+		t.Errorf(`changing the stop loss should not check the rules got %d queries %v`, settings, err)
+	}
+}
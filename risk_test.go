@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProposeTrade(t *testing.T) {
+	l := RiskLimits{RiskPercent: 1, ATRPeriod: 14, ATRMultiplier: 2}
+	// 1% of 100000 is 1000, a stop 2 ATRs of 250 away risks 500 a unit
+	p, err := ProposeTrade(l, 100000, 0, 5000, 250, false)
+	if err != nil {
+		t.Errorf(`failed to propose %s`, err)
+		return
+	}
+	if p.StopLoss != 4500 || p.Quantity != 2 || p.Risk != 1000 {
+		t.Errorf(`wrong proposal %+v`, p)
+	}
+	p, err = ProposeTrade(l, 100000, 0, 5000, 250, true)
+	if err != nil || p.StopLoss != 5500 || p.Quantity != 2 {
+		t.Errorf(`wrong short proposal %+v %v`, p, err)
+	}
+	l.MaxPositionPercent = 5
+	p, err = ProposeTrade(l, 100000, 0, 5000, 250, false)
+	if err != nil || p.Quantity != 1 || !p.Capped {
+		t.Errorf(`the position size should be capped %+v %v`, p, err)
+	}
+	l.MaxExposurePercent = 50
+	_, err = ProposeTrade(l, 100000, 48000, 5000, 250, false)
+	if err == nil || !strings.Contains(err.Error(), `refused`) {
+		t.Errorf(`a trade past the max exposure should be refused got %v`, err)
+	}
+	_, err = ProposeTrade(l, 100000, 0, 5000, 0, false)
+	if err == nil {
+		t.Errorf(`a trade without an ATR should fail`)
+	}
+}
+
+func TestLoadRiskLimits(t *testing.T) {
+	a := newFakeAdapter()
+	a.rows = func(q string) []map[string]string {
+		if strings.Contains(q, SettingRiskMaxExposure) {
+			return []map[string]string{{`id`: `1`, `skey`: SettingRiskMaxExposure, `svalue`: `80`}}
+		}
+		if strings.Contains(q, SettingRiskATRPeriod) {
+			return []map[string]string{{`id`: `2`, `skey`: SettingRiskATRPeriod, `svalue`: `20`}}
+		}
+		return nil
+	}
+	l, err := LoadRiskLimits(a)
+	if err != nil {
+		t.Errorf(`failed to load limits %s`, err)
+		return
+	}
+	if l.MaxExposurePercent != 80 || l.ATRPeriod != 20 || l.RiskPercent != DefaultRiskLimits.RiskPercent {
+		t.Errorf(`wrong limits %+v`, l)
+	}
+	err = SetSetting(a, SettingRiskMaxExposure, `90`)
+	if err != nil || a.count(`UPDATE settings`) != 1 {
+		t.Errorf(`an existing setting should be updated %v %v`, err, a.executed)
+	}
+	err = SetSetting(a, SettingRiskPerTrade, `2`)
+	if err != nil || a.count(`INSERT INTO settings`) != 1 {
+		t.Errorf(`a new setting should be created %v %v`, err, a.executed)
+	}
+}
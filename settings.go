@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// findSetting returns the Setting stored under key, or nil when there is
+// none, unlike FindBySkey a missing key is not an error
+func findSetting(a Adapter, key string) (*Setting, error) {
+	m := NewSetting(a)
+	q := fmt.Sprintf("SELECT * FROM %s WHERE `skey` = '%s' ORDER BY `id` LIMIT 1", m._table, a.SafeString(key))
+	results, err := a.Query(q)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+	err = m.FromDBValueMap(results[0])
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetSetting returns the value stored under key and whether it was set
+func GetSetting(a Adapter, key string) (string, bool, error) {
+	s, err := findSetting(a, key)
+	if err != nil || s == nil {
+		return ``, false, err
+	}
+	return s.Svalue, true, nil
+}
+
+// SetSetting stores value under key, replacing the previous value
+func SetSetting(a Adapter, key, value string) error {
+	s, err := findSetting(a, key)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		s = NewSetting(a)
+		s.Skey = key
+		s.Svalue = value
+		return s.Create()
+	}
+	s.SetSvalue(value)
+	return s.Save()
+}
+
+// settingFloat returns the setting under key as a float64, or def when
+// it is not set
+func settingFloat(a Adapter, key string, def float64) (float64, error) {
+	v, ok, err := GetSetting(a, key)
+	if err != nil || !ok {
+		return def, err
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return def, a.Oops(fmt.Sprintf(`setting %s should be a number, got %q`, key, v))
+	}
+	return f, nil
+}
+
+// settingCommand implements `gopaper setting <key> [value]`, printing the
+// value of key or storing a new one
+func settingCommand(a Adapter, args []string, w io.Writer) error {
+	switch len(args) {
+	case 1:
+		v, ok, err := GetSetting(a, args[0])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return oops(fmt.Sprintf(`%s is not set`, args[0]))
+		}
+		fmt.Fprintln(w, v)
+		return nil
+	case 2:
+		return SetSetting(a, args[0], args[1])
+	}
+	return oops(`usage: gopaper setting <key> [value]`)
+}
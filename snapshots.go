@@ -0,0 +1,236 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// allPortfolios returns every Portfolio in the database
+func allPortfolios(a Adapter) ([]*Portfolio, error) {
+	var portfolios []*Portfolio
+	m := NewPortfolio(a)
+	results, err := a.Query(fmt.Sprintf("SELECT * FROM %s ORDER BY `id`", m._table))
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		p := NewPortfolio(a)
+		err = p.FromDBValueMap(result)
+		if err != nil {
+			return nil, err
+		}
+		portfolios = append(portfolios, p)
+	}
+	return portfolios, nil
+}
+
+// playsForPosition returns the bars of a Position ordered by day, unlike
+// FindByPositionId a position without bars is not an error
+func playsForPosition(a Adapter, positionId int64) ([]*Play, error) {
+	var plays []*Play
+	m := NewPlay(a)
+	q := fmt.Sprintf("SELECT * FROM %s WHERE `position_id` = '%d' ORDER BY `day`", m._table, positionId)
+	results, err := a.Query(q)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		p := NewPlay(a)
+		err = p.FromDBValueMap(result)
+		if err != nil {
+			return nil, err
+		}
+		plays = append(plays, p)
+	}
+	return plays, nil
+}
+
+// tradingDays returns every distinct day present in the plays table
+func tradingDays(a Adapter) ([]time.Time, error) {
+	var days []time.Time
+	m := NewPlay(a)
+	results, err := a.Query(fmt.Sprintf("SELECT DISTINCT `day` FROM %s WHERE `day` IS NOT NULL ORDER BY `day`", m._table))
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		d, err := result["day"].AsDateTime()
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, truncateDay(d.ToTime()))
+	}
+	return days, nil
+}
+
+// isOpenOn returns true when the Position was open at the close of day
+func isOpenOn(p *Position, day time.Time) bool {
+	if p.StartedAt.IsZero() || truncateDay(p.StartedAt.ToTime()).After(day) {
+		return false
+	}
+	return !isClosedPosition(p) || truncateDay(p.ClosedAt.ToTime()).After(day)
+}
+
+// closeOn returns the closing price of the last bar at or before day,
+// falling back to the entry price when there is no bar yet
+func closeOn(p *Position, plays []*Play, day time.Time) int {
+	price := p.Buy
+	for _, pl := range plays {
+		if pl.Day.IsZero() || truncateDay(pl.Day.ToTime()).After(day) {
+			break
+		}
+		price = pl.AdjClose
+	}
+	return price
+}
+
+// snapshotValues computes the cash, market value and number of open
+// positions of a portfolio at the close of day. plays holds the bars of
+// each position keyed by position id, ordered by day.
+func snapshotValues(p *Portfolio, positions []*Position, plays map[int64][]*Play, day time.Time) (int, int, int) {
+	cash := p.Value
+	var marketValue, open int
+	for _, pos := range positions {
+		if pos.StartedAt.IsZero() || truncateDay(pos.StartedAt.ToTime()).After(day) {
+			continue
+		}
+		if !isOpenOn(pos, day) {
+			cash += int(PositionPnL(pos))
+			continue
+		}
+		open++
+		price := closeOn(pos, plays[pos.Id], day)
+		if isShortPosition(pos) {
+			cash += pos.Buy * pos.Quantity
+			marketValue -= price * pos.Quantity
+		} else {
+			cash -= pos.Buy * pos.Quantity
+			marketValue += price * pos.Quantity
+		}
+	}
+	return cash, marketValue, open
+}
+
+// snapshotsForPortfolio returns the stored snapshots of a portfolio ordered by day
+func snapshotsForPortfolio(a Adapter, portfolioId int64) ([]*PortfolioSnapshot, error) {
+	var snaps []*PortfolioSnapshot
+	m := NewPortfolioSnapshot(a)
+	q := fmt.Sprintf("SELECT * FROM %s WHERE `portfolio_id` = '%d' ORDER BY `day`", m._table, portfolioId)
+	results, err := a.Query(q)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		s := NewPortfolioSnapshot(a)
+		err = s.FromDBValueMap(result)
+		if err != nil {
+			return nil, err
+		}
+		snaps = append(snaps, s)
+	}
+	return snaps, nil
+}
+
+// EquityCurveFromSnapshots turns stored snapshots into an equity curve
+func EquityCurveFromSnapshots(snaps []*PortfolioSnapshot) []EquityPoint {
+	var curve []EquityPoint
+	for _, s := range snaps {
+		if s.Day.IsZero() {
+			continue
+		}
+		curve = append(curve, EquityPoint{Day: truncateDay(s.Day.ToTime()), Equity: float64(s.Cash + s.MarketValue)})
+	}
+	sort.Slice(curve, func(i, j int) bool { return curve[i].Day.Before(curve[j].Day) })
+	return curve
+}
+
+// SnapshotPortfolio records a PortfolioSnapshot for each of days, replacing
+// any snapshot already stored for that day. It returns the number of
+// snapshots written.
+func SnapshotPortfolio(a Adapter, p *Portfolio, days []time.Time) (int, error) {
+	positions, err := positionsForPortfolio(a, p.Id)
+	if err != nil {
+		return 0, err
+	}
+	plays := make(map[int64][]*Play)
+	for _, pos := range positions {
+		plays[pos.Id], err = playsForPosition(a, pos.Id)
+		if err != nil {
+			return 0, err
+		}
+	}
+	existing, err := snapshotsForPortfolio(a, p.Id)
+	if err != nil {
+		return 0, err
+	}
+	byDay := make(map[time.Time]*PortfolioSnapshot)
+	for _, s := range existing {
+		if !s.Day.IsZero() {
+			byDay[truncateDay(s.Day.ToTime())] = s
+		}
+	}
+	var written int
+	for _, day := range days {
+		cash, marketValue, open := snapshotValues(p, positions, plays, day)
+		if s, ok := byDay[day]; ok {
+			if s.Cash == cash && s.MarketValue == marketValue && s.OpenPositions == open {
+				continue
+			}
+			s.SetCash(cash)
+			s.SetMarketValue(marketValue)
+			s.SetOpenPositions(open)
+			err = s.Save()
+		} else {
+			s = NewPortfolioSnapshot(a)
+			s.PortfolioId = p.Id
+			s.Day = NewDateTime(a)
+			s.Day.FromTime(day)
+			s.Cash = cash
+			s.MarketValue = marketValue
+			s.OpenPositions = open
+			err = s.Create()
+		}
+		if err != nil {
+			return written, err
+		}
+		written++
+	}
+	return written, nil
+}
+
+// SnapshotPortfolios records the daily equity of every portfolio for each
+// trading day present in the plays table. It is safe to run repeatedly.
+func SnapshotPortfolios(a Adapter) (int, error) {
+	days, err := tradingDays(a)
+	if err != nil {
+		return 0, err
+	}
+	portfolios, err := allPortfolios(a)
+	if err != nil {
+		return 0, err
+	}
+	var written int
+	for _, p := range portfolios {
+		n, err := SnapshotPortfolio(a, p, days)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// snapshotCommand implements `gopaper snapshot`
+func snapshotCommand(a Adapter, args []string, w io.Writer) error {
+	if len(args) != 0 {
+		return oops(`usage: gopaper snapshot`)
+	}
+	n, err := SnapshotPortfolios(a)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "wrote %d portfolio snapshots\n", n)
+	return err
+}
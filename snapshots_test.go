@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func testPlay(a Adapter, positionId int64, y, m, d, close int) *Play {
+	p := NewPlay(a)
+	p.PositionId = positionId
+	p.Day = testDateTime(a, y, m, d)
+	p.AdjClose = close
+	return p
+}
+
+func TestSnapshotValues(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	portfolio := NewPortfolio(a)
+	portfolio.Value = 10000
+	long := testPosition(a, `long`, 100, 0, 10, testDateTime(a, 2016, 1, 4), NewDateTime(a))
+	long.Id = 1
+	short := testPosition(a, `short`, 200, 0, 5, testDateTime(a, 2016, 1, 5), NewDateTime(a))
+	short.Id = 2
+	closed := testPosition(a, `long`, 50, 60, 10, testDateTime(a, 2016, 1, 4), testDateTime(a, 2016, 1, 5))
+	closed.Id = 3
+	plays := map[int64][]*Play{
+		1: {testPlay(a, 1, 2016, 1, 4, 110), testPlay(a, 1, 2016, 1, 5, 120)},
+		2: {testPlay(a, 2, 2016, 1, 5, 190)},
+	}
+	positions := []*Position{long, short, closed}
+
+	day := time.Date(2016, 1, 4, 0, 0, 0, 0, time.UTC)
+	cash, mv, open := snapshotValues(portfolio, positions, plays, day)
+	if cash != 10000-1000-500 || mv != 1100+500 || open != 2 {
+		t.Errorf(`wrong values on the 4th %d %d %d`, cash, mv, open)
+	}
+	day = day.AddDate(0, 0, 1)
+	cash, mv, open = snapshotValues(portfolio, positions, plays, day)
+	if cash != 10000-1000+1000+100 || mv != 1200-950 || open != 2 {
+		t.Errorf(`wrong values on the 5th %d %d %d`, cash, mv, open)
+	}
+	// equity is the starting value plus the profit of every position
+	if cash+mv != 10000+200+50+100 {
+		t.Errorf(`wrong equity on the 5th %d`, cash+mv)
+	}
+}
+
+func TestEquityCurveFromSnapshots(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	s1 := NewPortfolioSnapshot(a)
+	s1.Day = testDateTime(a, 2016, 1, 5)
+	s1.Cash = 100
+	s1.MarketValue = 50
+	s2 := NewPortfolioSnapshot(a)
+	s2.Day = testDateTime(a, 2016, 1, 4)
+	s2.Cash = 100
+	curve := EquityCurveFromSnapshots([]*PortfolioSnapshot{s1, s2, NewPortfolioSnapshot(a)})
+	if len(curve) != 2 || curve[0].Equity != 100 || curve[1].Equity != 150 {
+		t.Errorf(`wrong curve %+v`, curve)
+	}
+}
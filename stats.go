@@ -0,0 +1,357 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tradingDaysPerYear is used to annualize the Sharpe and Sortino ratios
+const tradingDaysPerYear = 252
+
+// EquityPoint is a single day on a portfolio's equity curve
+type EquityPoint struct {
+	Day    time.Time `json:"day"`
+	Equity float64   `json:"equity"`
+}
+
+// PortfolioStats holds the performance analytics for a portfolio,
+// computed from its closed Positions and its daily equity curve.
+// Money values are in the same units as Position.Buy and Position.Sell.
+type PortfolioStats struct {
+	PortfolioId int64     `json:"portfolio_id"`
+	Name        string    `json:"name"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	StartEquity float64   `json:"start_equity"`
+	EndEquity   float64   `json:"end_equity"`
+	Trades      int       `json:"trades"`
+	Wins        int       `json:"wins"`
+	Losses      int       `json:"losses"`
+	// WinRate is the fraction of closed trades with a positive profit
+	WinRate float64 `json:"win_rate"`
+	AvgWin  float64 `json:"avg_win"`
+	// AvgLoss is reported as a positive number
+	AvgLoss float64 `json:"avg_loss"`
+	// ProfitFactor is gross profit over gross loss, it is zero when
+	// there are no losing trades
+	ProfitFactor float64 `json:"profit_factor"`
+	// MaxDrawdown is the largest peak to trough fall of the equity
+	// curve as a fraction of the peak
+	MaxDrawdown float64 `json:"max_drawdown"`
+	Sharpe      float64 `json:"sharpe"`
+	Sortino     float64 `json:"sortino"`
+	// Exposure is the fraction of days on the curve with at least
+	// one open position
+	Exposure float64 `json:"exposure"`
+	CAGR     float64 `json:"cagr"`
+}
+
+// isShortPosition returns true when the Position profits from a falling price
+func isShortPosition(p *Position) bool {
+	t := strings.ToLower(strings.TrimSpace(p.Ptype))
+	return t == `short` || t == `sell`
+}
+
+// isClosedPosition returns true once the Position has a ClosedAt date
+func isClosedPosition(p *Position) bool {
+	return !p.ClosedAt.IsZero()
+}
+
+// PositionPnL returns the realized profit or loss of a closed Position
+func PositionPnL(p *Position) float64 {
+	diff := float64(p.Sell - p.Buy)
+	if isShortPosition(p) {
+		diff = -diff
+	}
+	return diff * float64(p.Quantity)
+}
+
+// truncateDay strips the time of day from t
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// isWeekend returns true for Saturday and Sunday
+func isWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+// EquityCurveFromPositions builds a daily equity curve from the realized
+// profit of each Position, starting at start. It is used when no better
+// source for the curve exists. Weekends are skipped.
+func EquityCurveFromPositions(start float64, positions []*Position) []EquityPoint {
+	var curve []EquityPoint
+	var first, last time.Time
+	for _, p := range positions {
+		if p.StartedAt.IsZero() {
+			continue
+		}
+		s := truncateDay(p.StartedAt.ToTime())
+		if first.IsZero() || s.Before(first) {
+			first = s
+		}
+		if isClosedPosition(p) {
+			c := truncateDay(p.ClosedAt.ToTime())
+			if c.After(last) {
+				last = c
+			}
+		}
+	}
+	if first.IsZero() || last.IsZero() {
+		return curve
+	}
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		if isWeekend(d) {
+			continue
+		}
+		equity := start
+		for _, p := range positions {
+			if isClosedPosition(p) && !truncateDay(p.ClosedAt.ToTime()).After(d) {
+				equity += PositionPnL(p)
+			}
+		}
+		curve = append(curve, EquityPoint{Day: d, Equity: equity})
+	}
+	return curve
+}
+
+// ComputePortfolioStats computes the analytics for a set of positions
+// and an equity curve. Open positions only count towards Exposure.
+func ComputePortfolioStats(positions []*Position, curve []EquityPoint) *PortfolioStats {
+	s := &PortfolioStats{}
+	var grossProfit, grossLoss float64
+	for _, p := range positions {
+		if !isClosedPosition(p) {
+			continue
+		}
+		s.Trades++
+		pnl := PositionPnL(p)
+		if pnl > 0 {
+			s.Wins++
+			grossProfit += pnl
+		} else if pnl < 0 {
+			s.Losses++
+			grossLoss -= pnl
+		}
+	}
+	if s.Trades > 0 {
+		s.WinRate = float64(s.Wins) / float64(s.Trades)
+	}
+	if s.Wins > 0 {
+		s.AvgWin = grossProfit / float64(s.Wins)
+	}
+	if s.Losses > 0 {
+		s.AvgLoss = grossLoss / float64(s.Losses)
+	}
+	if grossLoss > 0 {
+		s.ProfitFactor = grossProfit / grossLoss
+	}
+	if len(curve) == 0 {
+		return s
+	}
+	sort.Slice(curve, func(i, j int) bool { return curve[i].Day.Before(curve[j].Day) })
+	s.From = curve[0].Day
+	s.To = curve[len(curve)-1].Day
+	s.StartEquity = curve[0].Equity
+	s.EndEquity = curve[len(curve)-1].Equity
+	s.MaxDrawdown = maxDrawdown(curve)
+	s.Sharpe, s.Sortino = sharpeSortino(curve)
+	s.Exposure = exposure(positions, curve)
+	years := s.To.Sub(s.From).Hours() / 24 / 365.25
+	if years > 0 && s.StartEquity > 0 && s.EndEquity > 0 {
+		s.CAGR = math.Pow(s.EndEquity/s.StartEquity, 1/years) - 1
+	}
+	return s
+}
+
+// maxDrawdown returns the largest fall from a peak as a fraction of that peak
+func maxDrawdown(curve []EquityPoint) float64 {
+	var peak, dd float64
+	for i, pt := range curve {
+		if i == 0 || pt.Equity > peak {
+			peak = pt.Equity
+		}
+		if peak > 0 {
+			if d := (peak - pt.Equity) / peak; d > dd {
+				dd = d
+			}
+		}
+	}
+	return dd
+}
+
+// sharpeSortino returns the annualized Sharpe and Sortino ratios of
+// the daily returns of the curve, with a risk free rate of zero
+func sharpeSortino(curve []EquityPoint) (float64, float64) {
+	var returns []float64
+	for i := 1; i < len(curve); i++ {
+		if curve[i-1].Equity == 0 {
+			continue
+		}
+		returns = append(returns, curve[i].Equity/curve[i-1].Equity-1)
+	}
+	if len(returns) < 2 {
+		return 0, 0
+	}
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+	var variance, downside float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+		if r < 0 {
+			downside += r * r
+		}
+	}
+	variance /= float64(len(returns) - 1)
+	downside /= float64(len(returns))
+	annual := math.Sqrt(tradingDaysPerYear)
+	var sharpe, sortino float64
+	if variance > 0 {
+		sharpe = mean / math.Sqrt(variance) * annual
+	}
+	if downside > 0 {
+		sortino = mean / math.Sqrt(downside) * annual
+	}
+	return sharpe, sortino
+}
+
+// exposure returns the fraction of curve days with a position open
+func exposure(positions []*Position, curve []EquityPoint) float64 {
+	var days int
+	for _, pt := range curve {
+		for _, p := range positions {
+			if p.StartedAt.IsZero() || truncateDay(p.StartedAt.ToTime()).After(pt.Day) {
+				continue
+			}
+			if isClosedPosition(p) && truncateDay(p.ClosedAt.ToTime()).Before(pt.Day) {
+				continue
+			}
+			days++
+			break
+		}
+	}
+	return float64(days) / float64(len(curve))
+}
+
+// findPortfolio looks a Portfolio up by id, or by name when ref
+// is not a number
+func findPortfolio(a Adapter, ref string) (*Portfolio, error) {
+	p := NewPortfolio(a)
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		_, err = p.Find(id)
+		if err != nil {
+			return nil, a.Oops(fmt.Sprintf(`no portfolio with id %d`, id))
+		}
+		return p, nil
+	}
+	results, err := p.FindByName(ref)
+	if err != nil || len(results) == 0 {
+		return nil, a.Oops(fmt.Sprintf(`no portfolio named %s`, ref))
+	}
+	return results[0], nil
+}
+
+// positionsForPortfolio returns every Position of the portfolio, unlike
+// FindByPortfolioId an empty portfolio is not an error
+func positionsForPortfolio(a Adapter, portfolioId int64) ([]*Position, error) {
+	var positions []*Position
+	m := NewPosition(a)
+	q := fmt.Sprintf("SELECT * FROM %s WHERE `portfolio_id` = '%d' ORDER BY `started_at`", m._table, portfolioId)
+	results, err := a.Query(q)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		p := NewPosition(a)
+		err = p.FromDBValueMap(result)
+		if err != nil {
+			return nil, err
+		}
+		positions = append(positions, p)
+	}
+	return positions, nil
+}
+
+// NewPortfolioStats loads the positions of the Portfolio and computes
+// its PortfolioStats. The equity curve comes from the stored snapshots,
+// see SnapshotPortfolios, or is rebuilt from the positions when there
+// are none.
+func NewPortfolioStats(a Adapter, p *Portfolio) (*PortfolioStats, error) {
+	positions, err := positionsForPortfolio(a, p.Id)
+	if err != nil {
+		return nil, err
+	}
+	snaps, err := snapshotsForPortfolio(a, p.Id)
+	if err != nil {
+		return nil, err
+	}
+	curve := EquityCurveFromSnapshots(snaps)
+	if len(curve) == 0 {
+		curve = EquityCurveFromPositions(float64(p.Value), positions)
+	}
+	s := ComputePortfolioStats(positions, curve)
+	s.PortfolioId = p.Id
+	s.Name = p.Name
+	return s, nil
+}
+
+// WriteText writes a human readable report of the stats to w
+func (s *PortfolioStats) WriteText(w io.Writer) error {
+	period := `n/a`
+	if !s.From.IsZero() {
+		period = fmt.Sprintf(`%s to %s`, s.From.Format(`2006-01-02`), s.To.Format(`2006-01-02`))
+	}
+	_, err := fmt.Fprintf(w, `Portfolio:      %s (%d)
+Period:         %s
+Equity:         %.2f -> %.2f
+Trades:         %d (%d won, %d lost)
+Win rate:       %.2f%%
+Average win:    %.2f
+Average loss:   %.2f
+Profit factor:  %.2f
+Max drawdown:   %.2f%%
+Sharpe:         %.2f
+Sortino:        %.2f
+Exposure:       %.2f%%
+CAGR:           %.2f%%
+`, s.Name, s.PortfolioId, period, s.StartEquity, s.EndEquity,
+		s.Trades, s.Wins, s.Losses, s.WinRate*100, s.AvgWin, s.AvgLoss,
+		s.ProfitFactor, s.MaxDrawdown*100, s.Sharpe, s.Sortino,
+		s.Exposure*100, s.CAGR*100)
+	return err
+}
+
+// WriteJSON writes the stats to w as indented JSON
+func (s *PortfolioStats) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent(``, `  `)
+	return enc.Encode(s)
+}
+
+// statsCommand implements `gopaper stats <portfolio>`
+func statsCommand(a Adapter, args []string, w io.Writer) error {
+	if len(args) != 1 {
+		return oops(`usage: gopaper [-json] stats <portfolio id or name>`)
+	}
+	p, err := findPortfolio(a, args[0])
+	if err != nil {
+		return err
+	}
+	s, err := NewPortfolioStats(a, p)
+	if err != nil {
+		return err
+	}
+	if *jsonOutput {
+		return s.WriteJSON(w)
+	}
+	return s.WriteText(w)
+}
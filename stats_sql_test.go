@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeSQL(t *testing.T) {
+	for q, shape := range map[string]string{
+		`SELECT * FROM plays WHERE id = '12'`:                                 `SELECT * FROM plays WHERE id = ?`,
+		`SELECT * FROM plays WHERE symbol = 'it\'s' AND day > 3.5`:            `SELECT * FROM plays WHERE symbol = ? AND day > ?`,
+		"SELECT * FROM positions\n  WHERE id IN ('1', '2','3')":               `SELECT * FROM positions WHERE id IN (...)`,
+		`INSERT INTO plays (a,b) VALUES ('1','x'),('2','y') ON DUPLICATE KEY`: `INSERT INTO plays (a,b) VALUES (...),... ON DUPLICATE KEY`,
+		`UPDATE positions SET lock_version = lock_version + 1 WHERE id = 7`:   `UPDATE positions SET lock_version = lock_version + ? WHERE id = ?`,
+	} {
+		if NormalizeSQL(q) != shape {
+			t.Errorf(`expected %s got %s`, shape, NormalizeSQL(q))
+		}
+	}
+}
+
+func TestStatementStats(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	a.SetLogs(&bytes.Buffer{})
+	var slow bytes.Buffer
+	a.SetSlowLog(&slow)
+	a.SlowQuery = time.Hour
+	now := time.Now()
+	a.recordStatement(`query`, `SELECT * FROM plays WHERE id = '1'`, now, 1, nil)
+	a.recordStatement(`query`, `SELECT * FROM plays WHERE id = '2'`, now, 1, nil)
+	a.recordStatement(`query`, `SELECT * FROM plays WHERE id = '3'`, now, 0, errors.New(`lost`))
+	a.recordStatement(`execute`, `UPDATE plays SET day = '2016-01-04' WHERE id = '1'`, now.Add(-2*time.Hour), 1, nil)
+	ss := a.Stats()
+	if len(ss) != 2 {
+		t.Errorf(`expected 2 shapes got %v`, ss)
+		return
+	}
+	u, s := ss[0], ss[1]
+	if u.Shape != `UPDATE plays SET day = ? WHERE id = ?` || u.Count != 1 || u.Slow != 1 || u.Max < 2*time.Hour {
+		t.Errorf(`the slow update should come first got %s`, u)
+	}
+	if s.Shape != `SELECT * FROM plays WHERE id = ?` || s.Count != 3 || s.Rows != 2 || s.Errors != 1 || s.Slow != 0 || s.Mean() != s.Total/3 {
+		t.Errorf(`the finder should be counted 3 times got %s`, s)
+	}
+	if !strings.HasPrefix(slow.String(), `[SLOW]:`) || !strings.Contains(slow.String(), `rows=1 UPDATE plays SET day = '2016-01-04'`) {
+		t.Errorf(`the update should be in the slow log got %s`, slow.String())
+	}
+	a.ResetStats()
+	if len(a.Stats()) != 0 {
+		t.Errorf(`the stats should be empty after a reset`)
+	}
+}
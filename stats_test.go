@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+func testDateTime(a Adapter, y, m, d int) *DateTime {
+	dt := NewDateTime(a)
+	dt.FromTime(time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC))
+	return dt
+}
+
+func testPosition(a Adapter, ptype string, buy, sell, qty int, start, end *DateTime) *Position {
+	p := NewPosition(a)
+	p.Ptype = ptype
+	p.Buy = buy
+	p.Sell = sell
+	p.Quantity = qty
+	p.StartedAt = start
+	p.ClosedAt = end
+	return p
+}
+
+func closeEnough(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestPositionPnL(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	long := testPosition(a, `long`, 100, 120, 10, nil, nil)
+	if PositionPnL(long) != 200 {
+		t.Errorf(`long pnl should be 200 got %f`, PositionPnL(long))
+	}
+	short := testPosition(a, `short`, 100, 120, 10, nil, nil)
+	if PositionPnL(short) != -200 {
+		t.Errorf(`short pnl should be -200 got %f`, PositionPnL(short))
+	}
+}
+
+func TestComputePortfolioStats(t *testing.T) {
+	a := NewMysqlAdapter(``)
+	positions := []*Position{
+		testPosition(a, `long`, 100, 150, 10, testDateTime(a, 2016, 1, 4), testDateTime(a, 2016, 1, 5)),
+		testPosition(a, `long`, 100, 80, 10, testDateTime(a, 2016, 1, 6), testDateTime(a, 2016, 1, 7)),
+		testPosition(a, `long`, 100, 130, 10, testDateTime(a, 2016, 1, 7), testDateTime(a, 2016, 1, 8)),
+		// still open, only counts towards exposure
+		testPosition(a, `long`, 100, 0, 10, testDateTime(a, 2016, 1, 8), NewDateTime(a)),
+	}
+	curve := EquityCurveFromPositions(1000, positions)
+	if len(curve) != 5 {
+		t.Errorf(`expected 5 weekdays on the curve got %d`, len(curve))
+		return
+	}
+	s := ComputePortfolioStats(positions, curve)
+	if s.Trades != 3 || s.Wins != 2 || s.Losses != 1 {
+		t.Errorf(`wrong trade counts %+v`, s)
+	}
+	if !closeEnough(s.WinRate, 2.0/3.0) {
+		t.Errorf(`wrong win rate %f`, s.WinRate)
+	}
+	if !closeEnough(s.AvgWin, 400) || !closeEnough(s.AvgLoss, 200) {
+		t.Errorf(`wrong averages %f %f`, s.AvgWin, s.AvgLoss)
+	}
+	if !closeEnough(s.ProfitFactor, 4) {
+		t.Errorf(`wrong profit factor %f`, s.ProfitFactor)
+	}
+	// 1000, 1500, 1500, 1300, 1600
+	if !closeEnough(s.MaxDrawdown, 200.0/1500.0) {
+		t.Errorf(`wrong max drawdown %f`, s.MaxDrawdown)
+	}
+	if s.StartEquity != 1000 || s.EndEquity != 1600 {
+		t.Errorf(`wrong equity %f -> %f`, s.StartEquity, s.EndEquity)
+	}
+	if !closeEnough(s.Exposure, 1) {
+		t.Errorf(`wrong exposure %f`, s.Exposure)
+	}
+	if s.Sharpe <= 0 || s.Sortino <= 0 || s.CAGR <= 0 {
+		t.Errorf(`expected positive ratios %+v`, s)
+	}
+}
+
+func TestPortfolioStatsOutput(t *testing.T) {
+	s := &PortfolioStats{Name: `test`, Trades: 2, Wins: 1, Losses: 1, WinRate: 0.5}
+	var b bytes.Buffer
+	err := s.WriteText(&b)
+	if err != nil || !bytes.Contains(b.Bytes(), []byte(`Win rate:       50.00%`)) {
+		t.Errorf(`bad text output %s %v`, b.String(), err)
+	}
+	b.Reset()
+	err = s.WriteJSON(&b)
+	if err != nil {
+		t.Errorf(`failed to write json %s`, err)
+		return
+	}
+	var s2 PortfolioStats
+	err = json.Unmarshal(b.Bytes(), &s2)
+	if err != nil || s2.Trades != 2 || s2.Name != `test` {
+		t.Errorf(`json did not round trip %s %v`, b.String(), err)
+	}
+}
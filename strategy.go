@@ -0,0 +1,466 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Strategy decides when to open and close positions. The Backtester
+// calls OnStart once, OnBar for every trading day in order and OnEnd
+// after the last day, before the remaining positions are closed.
+type Strategy interface {
+	OnStart(bt *Backtest) error
+	OnBar(bt *Backtest, day time.Time, bars map[string]*Play) error
+	OnEnd(bt *Backtest) error
+}
+
+// StrategyFunc lets a plain function be used as a Strategy, it is
+// called for every bar and has nothing to do on start or end
+type StrategyFunc func(bt *Backtest, day time.Time, bars map[string]*Play) error
+
+// OnStart does nothing
+func (f StrategyFunc) OnStart(bt *Backtest) error {
+	return nil
+}
+
+// OnBar calls f
+func (f StrategyFunc) OnBar(bt *Backtest, day time.Time, bars map[string]*Play) error {
+	return f(bt, day, bars)
+}
+
+// OnEnd does nothing
+func (f StrategyFunc) OnEnd(bt *Backtest) error {
+	return nil
+}
+
+// StrategyParams are the parameters of a strategy as read from YAML
+type StrategyParams map[string]interface{}
+
+// Int returns the parameter key as an int, or def when it is not set
+func (p StrategyParams) Int(key string, def int) (int, error) {
+	v, ok := p[key]
+	if !ok {
+		return def, nil
+	}
+	switch n := v.(type) {
+	case int:
+		return n, nil
+	case float64:
+		return int(n), nil
+	case string:
+		i, err := strconv.Atoi(n)
+		if err == nil {
+			return i, nil
+		}
+	}
+	return def, oops(fmt.Sprintf(`parameter %s should be a whole number, got %v`, key, v))
+}
+
+// Float returns the parameter key as a float64, or def when it is not set
+func (p StrategyParams) Float(key string, def float64) (float64, error) {
+	v, ok := p[key]
+	if !ok {
+		return def, nil
+	}
+	switch n := v.(type) {
+	case int:
+		return float64(n), nil
+	case float64:
+		return n, nil
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		if err == nil {
+			return f, nil
+		}
+	}
+	return def, oops(fmt.Sprintf(`parameter %s should be a number, got %v`, key, v))
+}
+
+// StrategyFactory builds a Strategy from its parameters
+type StrategyFactory func(params StrategyParams) (Strategy, error)
+
+// strategyRegistry holds the strategies known by type name
+var strategyRegistry = make(map[string]StrategyFactory)
+
+// RegisterStrategy makes a strategy available to the configuration under
+// name, registering the same name twice replaces the first factory
+func RegisterStrategy(name string, factory StrategyFactory) {
+	strategyRegistry[name] = factory
+}
+
+// NewStrategy builds the registered strategy called name
+func NewStrategy(name string, params StrategyParams) (Strategy, error) {
+	factory, ok := strategyRegistry[name]
+	if !ok {
+		return nil, oops(fmt.Sprintf(`unknown strategy %s`, name))
+	}
+	return factory(params)
+}
+
+// StrategyNames returns the registered strategy names, sorted
+func StrategyNames() []string {
+	var names []string
+	for name := range strategyRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	RegisterStrategy(`sma_crossover`, newSMACrossover)
+	RegisterStrategy(`breakout`, newBreakout)
+	RegisterStrategy(`rsi_reversion`, newRSIReversion)
+}
+
+// StrategyConfig configures one strategy run, e.g.
+//
+//	strategies:
+//	  - name: aapl crossover
+//	    type: sma_crossover
+//	    symbols: [AAPL]
+//	    cash: 1000000
+//	    params:
+//	      fast: 10
+//	      slow: 30
+//	      quantity: 10
+type StrategyConfig struct {
+	Name    string         `yaml:"name"`
+	Type    string         `yaml:"type"`
+	Symbols []string       `yaml:"symbols"`
+	Cash    int            `yaml:"cash"`
+	Params  StrategyParams `yaml:"params"`
+}
+
+// StrategiesConfig is the file read by `gopaper strategy`
+type StrategiesConfig struct {
+	// Cash is the default starting cash of every strategy
+	Cash       int              `yaml:"cash"`
+	Strategies []StrategyConfig `yaml:"strategies"`
+}
+
+// LoadStrategiesConfig reads the strategies from YAML
+func LoadStrategiesConfig(b []byte) (*StrategiesConfig, error) {
+	c := &StrategiesConfig{}
+	err := yaml.Unmarshal(b, c)
+	if err != nil {
+		return nil, oops(fmt.Sprintf(`could not read strategies %s`, err))
+	}
+	for i, s := range c.Strategies {
+		if s.Type == `` {
+			return nil, oops(fmt.Sprintf(`strategy %d has no type`, i+1))
+		}
+		if _, ok := strategyRegistry[s.Type]; !ok {
+			return nil, oops(fmt.Sprintf(`strategy %d has unknown type %s`, i+1, s.Type))
+		}
+		if s.Name == `` {
+			c.Strategies[i].Name = s.Type
+		}
+		if s.Cash == 0 {
+			c.Strategies[i].Cash = c.Cash
+		}
+		if c.Strategies[i].Cash <= 0 {
+			return nil, oops(fmt.Sprintf(`strategy %d needs cash, set cash for it or for every strategy`, i+1))
+		}
+	}
+	return c, nil
+}
+
+// sortedSymbols returns the symbols of bars in a stable order, so a
+// strategy trades the same way on every run
+func sortedSymbols(bars map[string]*Play) []string {
+	var symbols []string
+	for s := range bars {
+		symbols = append(symbols, s)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// SMACrossover buys when the fast simple moving average of the close
+// crosses above the slow one and sells when it crosses back below
+type SMACrossover struct {
+	Fast     int
+	Slow     int
+	Quantity int
+}
+
+func newSMACrossover(params StrategyParams) (Strategy, error) {
+	s := &SMACrossover{}
+	var err error
+	if s.Fast, err = params.Int(`fast`, 10); err != nil {
+		return nil, err
+	}
+	if s.Slow, err = params.Int(`slow`, 30); err != nil {
+		return nil, err
+	}
+	if s.Quantity, err = params.Int(`quantity`, 1); err != nil {
+		return nil, err
+	}
+	if s.Fast <= 0 || s.Slow <= s.Fast {
+		return nil, oops(fmt.Sprintf(`sma_crossover needs 0 < fast < slow, got %d and %d`, s.Fast, s.Slow))
+	}
+	return s, nil
+}
+
+// OnStart does nothing
+func (s *SMACrossover) OnStart(bt *Backtest) error {
+	return nil
+}
+
+// OnBar looks for a crossover in every symbol
+func (s *SMACrossover) OnBar(bt *Backtest, day time.Time, bars map[string]*Play) error {
+	for _, symbol := range sortedSymbols(bars) {
+		closes := Closes(bt.History(symbol))
+		if len(closes) <= s.Slow {
+			continue
+		}
+		fastSMA, slowSMA := SMA(closes, s.Fast), SMA(closes, s.Slow)
+		fast, slow := fastSMA.Last(), slowSMA.Last()
+		prevFast, prevSlow := fastSMA.At(1), slowSMA.At(1)
+		open := bt.OpenPosition(symbol)
+		if open == nil && prevFast <= prevSlow && fast > slow && bt.CanBuy(symbol, s.Quantity) {
+			p, err := bt.Buy(symbol, s.Quantity, 0)
+			if err != nil {
+				return err
+			}
+			err = bt.Note(p, fmt.Sprintf(`sma_crossover: %d day average %.2f crossed above the %d day average %.2f`, s.Fast, fromPrice(int(fast)), s.Slow, fromPrice(int(slow))))
+			if err != nil {
+				return err
+			}
+		} else if open != nil && prevFast >= prevSlow && fast < slow {
+			err := bt.Note(open, fmt.Sprintf(`sma_crossover: %d day average %.2f crossed below the %d day average %.2f`, s.Fast, fromPrice(int(fast)), s.Slow, fromPrice(int(slow))))
+			if err != nil {
+				return err
+			}
+			err = bt.Close(open)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// OnEnd does nothing
+func (s *SMACrossover) OnEnd(bt *Backtest) error {
+	return nil
+}
+
+// Breakout buys when the close rises above the highest high of the
+// previous Days bars and sells when it falls below their lowest low.
+// StopPercent places a stop loss that far below the entry when set.
+type Breakout struct {
+	Days        int
+	Quantity    int
+	StopPercent float64
+}
+
+func newBreakout(params StrategyParams) (Strategy, error) {
+	s := &Breakout{}
+	var err error
+	if s.Days, err = params.Int(`days`, 20); err != nil {
+		return nil, err
+	}
+	if s.Quantity, err = params.Int(`quantity`, 1); err != nil {
+		return nil, err
+	}
+	if s.StopPercent, err = params.Float(`stop_percent`, 0); err != nil {
+		return nil, err
+	}
+	if s.Days <= 0 {
+		return nil, oops(fmt.Sprintf(`breakout needs days > 0, got %d`, s.Days))
+	}
+	return s, nil
+}
+
+// OnStart does nothing
+func (s *Breakout) OnStart(bt *Backtest) error {
+	return nil
+}
+
+// OnBar looks for a breakout in every symbol
+func (s *Breakout) OnBar(bt *Backtest, day time.Time, bars map[string]*Play) error {
+	for _, symbol := range sortedSymbols(bars) {
+		history := bt.History(symbol)
+		if len(history) <= s.Days {
+			continue
+		}
+		high := int(Highest(Highs(history), s.Days).Last())
+		low := int(Lowest(Lows(history), s.Days).Last())
+		c := bars[symbol].AdjClose
+		open := bt.OpenPosition(symbol)
+		if open == nil && c > high && bt.CanBuy(symbol, s.Quantity) {
+			stop := 0
+			if s.StopPercent > 0 {
+				stop = int(float64(c) * (1 - s.StopPercent/100))
+			}
+			p, err := bt.Buy(symbol, s.Quantity, stop)
+			if err != nil {
+				return err
+			}
+			err = bt.Note(p, fmt.Sprintf(`breakout: close %.2f above the %d day high %.2f`, fromPrice(c), s.Days, fromPrice(high)))
+			if err != nil {
+				return err
+			}
+		} else if open != nil && c < low {
+			err := bt.Note(open, fmt.Sprintf(`breakout: close %.2f below the %d day low %.2f`, fromPrice(c), s.Days, fromPrice(low)))
+			if err != nil {
+				return err
+			}
+			err = bt.Close(open)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// OnEnd does nothing
+func (s *Breakout) OnEnd(bt *Backtest) error {
+	return nil
+}
+
+// RSIReversion buys when Wilder's relative strength index of the close
+// falls below Oversold and sells once it rises above Overbought
+type RSIReversion struct {
+	Period     int
+	Oversold   float64
+	Overbought float64
+	Quantity   int
+}
+
+func newRSIReversion(params StrategyParams) (Strategy, error) {
+	s := &RSIReversion{}
+	var err error
+	if s.Period, err = params.Int(`period`, 14); err != nil {
+		return nil, err
+	}
+	if s.Oversold, err = params.Float(`oversold`, 30); err != nil {
+		return nil, err
+	}
+	if s.Overbought, err = params.Float(`overbought`, 70); err != nil {
+		return nil, err
+	}
+	if s.Quantity, err = params.Int(`quantity`, 1); err != nil {
+		return nil, err
+	}
+	if s.Period <= 0 || s.Oversold >= s.Overbought {
+		return nil, oops(`rsi_reversion needs period > 0 and oversold < overbought`)
+	}
+	return s, nil
+}
+
+// OnStart does nothing
+func (s *RSIReversion) OnStart(bt *Backtest) error {
+	return nil
+}
+
+// OnBar checks the RSI of every symbol
+func (s *RSIReversion) OnBar(bt *Backtest, day time.Time, bars map[string]*Play) error {
+	for _, symbol := range sortedSymbols(bars) {
+		closes := Closes(bt.History(symbol))
+		if len(closes) <= s.Period {
+			continue
+		}
+		r := RSI(closes, s.Period).Last()
+		open := bt.OpenPosition(symbol)
+		if open == nil && r < s.Oversold && bt.CanBuy(symbol, s.Quantity) {
+			p, err := bt.Buy(symbol, s.Quantity, 0)
+			if err != nil {
+				return err
+			}
+			err = bt.Note(p, fmt.Sprintf(`rsi_reversion: RSI %.1f below %.1f`, r, s.Oversold))
+			if err != nil {
+				return err
+			}
+		} else if open != nil && r > s.Overbought {
+			err := bt.Note(open, fmt.Sprintf(`rsi_reversion: RSI %.1f above %.1f`, r, s.Overbought))
+			if err != nil {
+				return err
+			}
+			err = bt.Close(open)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// OnEnd does nothing
+func (s *RSIReversion) OnEnd(bt *Backtest) error {
+	return nil
+}
+
+// strategyCommand implements `gopaper strategy list` and
+// `gopaper strategy run [-config file] [-name name]`. Runs happen in
+// paper mode, each strategy trades its own portfolio so the candidate
+// positions and notes it produced can be reviewed afterwards.
+func strategyCommand(a Adapter, args []string, w io.Writer) error {
+	usage := `usage: gopaper strategy (list | run [-config strategies.yml] [-name name])`
+	if len(args) == 0 {
+		return oops(usage)
+	}
+	switch args[0] {
+	case `list`:
+		for _, name := range StrategyNames() {
+			fmt.Fprintln(w, name)
+		}
+		return nil
+	case `run`:
+	default:
+		return oops(usage)
+	}
+	fs := flag.NewFlagSet(`strategy run`, flag.ContinueOnError)
+	fs.SetOutput(w)
+	config := fs.String(`config`, `strategies.yml`, `the YAML file with the strategies`)
+	name := fs.String(`name`, ``, `only run the strategy with this name`)
+	err := fs.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadFile(*config)
+	if err != nil {
+		return oops(fmt.Sprintf(`could not read %s %s`, *config, err))
+	}
+	c, err := LoadStrategiesConfig(b)
+	if err != nil {
+		return err
+	}
+	ran := 0
+	for _, sc := range c.Strategies {
+		if *name != `` && sc.Name != *name {
+			continue
+		}
+		s, err := NewStrategy(sc.Type, sc.Params)
+		if err != nil {
+			return oops(fmt.Sprintf(`strategy %s: %s`, sc.Name, err))
+		}
+		bt := NewBacktester(a, s, sc.Cash)
+		bt.Name = fmt.Sprintf(`paper %s %s`, sc.Name, time.Now().UTC().Format(`2006-01-02 15:04:05`))
+		bt.Symbols = sc.Symbols
+		result, err := bt.Run()
+		if err != nil {
+			return err
+		}
+		err = result.WriteText(w)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+		ran++
+	}
+	if ran == 0 {
+		return oops(fmt.Sprintf(`no strategies to run in %s`, *config))
+	}
+	return nil
+}
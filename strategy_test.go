@@ -0,0 +1,131 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCloseRows returns one bar row per close, on consecutive days
+// starting 2016-01-04
+func testCloseRows(symbol string, closes ...int) []map[string]string {
+	var rows []map[string]string
+	day := time.Date(2016, 1, 4, 0, 0, 0, 0, time.UTC)
+	for i, c := range closes {
+		rows = append(rows, testBarRow(int64(i+1), symbol, day.Year(), int(day.Month()), day.Day(), c, c, c, c))
+		day = day.AddDate(0, 0, 1)
+	}
+	return rows
+}
+
+func TestLoadStrategiesConfig(t *testing.T) {
+	c, err := LoadStrategiesConfig([]byte(`
+cash: 5000
+strategies:
+  - type: sma_crossover
+    params:
+      fast: 2
+      slow: 4
+  - name: big breakout
+    type: breakout
+    cash: 100
+    params:
+      days: 3
+`))
+	if err != nil {
+		t.Errorf(`failed to load strategies %s`, err)
+		return
+	}
+	if len(c.Strategies) != 2 || c.Strategies[0].Name != `sma_crossover` || c.Strategies[0].Cash != 5000 || c.Strategies[1].Cash != 100 {
+		t.Errorf(`wrong config %+v`, c)
+		return
+	}
+	s, err := NewStrategy(c.Strategies[0].Type, c.Strategies[0].Params)
+	if err != nil {
+		t.Errorf(`failed to build strategy %s`, err)
+		return
+	}
+	if sma := s.(*SMACrossover); sma.Fast != 2 || sma.Slow != 4 || sma.Quantity != 1 {
+		t.Errorf(`wrong params %+v`, sma)
+	}
+	_, err = LoadStrategiesConfig([]byte("strategies:\n  - type: nope\n"))
+	if err == nil {
+		t.Errorf(`an unknown strategy type should fail`)
+	}
+	_, err = NewStrategy(`sma_crossover`, StrategyParams{`fast`: 5, `slow`: 5})
+	if err == nil {
+		t.Errorf(`fast must be below slow`)
+	}
+	_, err = LoadStrategiesConfig([]byte("strategies:\n  - type: breakout\n"))
+	if err == nil || !strings.Contains(err.Error(), `strategy 1 needs cash`) {
+		t.Errorf(`a strategy without cash should fail got %v`, err)
+	}
+}
+
+func TestSMACrossover(t *testing.T) {
+	a := newFakeAdapter()
+	a.rows = func(q string) []map[string]string {
+		if !strings.Contains(q, `JOIN`) {
+			return nil
+		}
+		return testCloseRows(`AAA`, 100, 100, 100, 100, 110, 120, 130, 120, 100, 90, 80)
+	}
+	s, _ := NewStrategy(`sma_crossover`, StrategyParams{`fast`: 2, `slow`: 4, `quantity`: 5})
+	r, err := NewBacktester(a, s, 10000).Run()
+	if err != nil {
+		t.Errorf(`backtest failed %s`, err)
+		return
+	}
+	if len(r.Trades) != 1 {
+		t.Errorf(`expected one trade got %d`, len(r.Trades))
+		return
+	}
+	if r.Trades[0].Buy != 110 || r.Trades[0].Sell != 100 {
+		t.Errorf(`expected to buy at 110 and sell at 100 got %d %d`, r.Trades[0].Buy, r.Trades[0].Sell)
+	}
+	if a.count("INSERT INTO notes (`value`, `portfolio_id`, `position_id`) VALUES ('sma_crossover") != 2 {
+		t.Errorf(`expected a note for the entry and the exit got %v`, a.executed)
+	}
+}
+
+func TestBreakout(t *testing.T) {
+	a := newFakeAdapter()
+	a.rows = func(q string) []map[string]string {
+		if !strings.Contains(q, `JOIN`) {
+			return nil
+		}
+		return testCloseRows(`AAA`, 100, 101, 99, 105, 106, 104, 98)
+	}
+	s, _ := NewStrategy(`breakout`, StrategyParams{`days`: 3})
+	r, err := NewBacktester(a, s, 10000).Run()
+	if err != nil {
+		t.Errorf(`backtest failed %s`, err)
+		return
+	}
+	if len(r.Trades) != 1 || r.Trades[0].Buy != 105 || r.Trades[0].Sell != 98 {
+		t.Errorf(`expected to buy the breakout at 105 and exit at 98 got %+v`, r.Trades)
+	}
+}
+
+func TestBreakoutWithoutCash(t *testing.T) {
+	a := newFakeAdapter()
+	a.rows = func(q string) []map[string]string {
+		if !strings.Contains(q, `JOIN`) {
+			return nil
+		}
+		return testCloseRows(`AAA`, 100, 101, 99, 105, 106, 104, 98)
+	}
+	s, _ := NewStrategy(`breakout`, StrategyParams{`days`: 3, `quantity`: 10})
+	r, err := NewBacktester(a, s, 1000).Run()
+	if err != nil || len(r.Trades) != 0 {
+		t.Errorf(`a signal the cash cannot pay for should be skipped got %+v %v`, r, err)
+	}
+}
+
+func TestStrategyList(t *testing.T) {
+	var buf strings.Builder
+	err := strategyCommand(newFakeAdapter(), []string{`list`}, &buf)
+	if err != nil || buf.String() != "breakout\nrsi_reversion\nsma_crossover\n" {
+		t.Errorf(`wrong strategy list %q %v`, buf.String(), err)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+// validatePosition rejects the negative amounts the positions table
+// cannot express a meaning for
+func validatePosition(a Adapter, model interface{}) error {
+	p, ok := model.(*Position)
+	if !ok {
+		return nil
+	}
+	errs := make(ValidationErrors)
+	if p.Quantity < 0 {
+		errs.Add(`quantity`, `must not be negative`)
+	}
+	if p.Buy < 0 {
+		errs.Add(`buy`, `must not be negative`)
+	}
+	if p.Sell < 0 {
+		errs.Add(`sell`, `must not be negative`)
+	}
+	if p.StopLoss < 0 {
+		errs.Add(`stop_loss`, `must not be negative`)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validatePlay rejects negative prices and volumes
+func validatePlay(a Adapter, model interface{}) error {
+	p, ok := model.(*Play)
+	if !ok {
+		return nil
+	}
+	errs := make(ValidationErrors)
+	for _, f := range []struct {
+		name  string
+		value int
+	}{
+		{`open`, p.Open},
+		{`high`, p.High},
+		{`low`, p.Low},
+		{`adj_close`, p.AdjClose},
+		{`pvolume`, p.Pvolume},
+	} {
+		if f.value < 0 {
+			errs.Add(f.name, `must not be negative`)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func init() {
+	RegisterValidator(`Position`, validatePosition)
+	RegisterValidator(`Play`, validatePlay)
+}
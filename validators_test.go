@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestGeneratedValidate(t *testing.T) {
+	a := newFakeAdapter()
+	n := NewNote(a)
+	n.Value = `a note`
+	err := n.Create()
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Errorf(`expected ValidationErrors got %v`, err)
+		return
+	}
+	if len(errs[`portfolio_id`]) != 1 || len(errs[`position_id`]) != 1 || len(errs) != 2 {
+		t.Errorf(`both ids should be required got %v`, errs)
+	}
+	if errs.Error() != `portfolio_id is required, position_id is required` {
+		t.Errorf(`wrong message %s`, errs.Error())
+	}
+	if len(a.executed) != 0 {
+		t.Errorf(`an invalid note should not be stored %v`, a.executed)
+	}
+	p := NewPortfolio(a)
+	p.Name = string(make([]byte, 256))
+	err = p.Save()
+	if errs, ok := err.(ValidationErrors); !ok || len(errs[`name`]) != 1 {
+		t.Errorf(`a name over 255 characters should fail got %v`, err)
+	}
+	p.Name = ``
+	err = p.Validate()
+	if errs, ok := err.(ValidationErrors); !ok || errs[`name`][0] != `is required` {
+		t.Errorf(`an empty name should fail got %v`, err)
+	}
+	p.Name = `ok`
+	if p.Validate() != nil {
+		t.Errorf(`the portfolio should be valid`)
+	}
+}
+
+func TestRegisteredValidators(t *testing.T) {
+	a := newFakeAdapter()
+	p := NewPosition(a)
+	p.PortfolioId = 1
+	p.Quantity = -5
+	p.Buy = -1
+	err := p.Create()
+	errs, ok := err.(ValidationErrors)
+	if !ok || len(errs[`quantity`]) != 1 || len(errs[`buy`]) != 1 {
+		t.Errorf(`negative quantity and buy should fail got %v`, err)
+	}
+	p.Quantity = 5
+	p.Buy = 100
+	err = p.Create()
+	if err != nil || a.count(`INSERT INTO positions`) != 1 {
+		t.Errorf(`a valid position should be stored %v`, err)
+	}
+	pl := NewPlay(a)
+	pl.PositionId = 1
+	pl.Low = -1
+	err = pl.Create()
+	if errs, ok := err.(ValidationErrors); !ok || len(errs[`low`]) != 1 {
+		t.Errorf(`a negative low should fail got %v`, err)
+	}
+}